@@ -0,0 +1,627 @@
+package fastlog
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// BackpressurePolicy 异步队列已满时的处理策略
+type BackpressurePolicy uint8
+
+const (
+	// BackpressureBlock 阻塞调用方直到队列有空位, 不丢弃日志 (默认)
+	BackpressureBlock BackpressurePolicy = iota
+
+	// BackpressureDropNewest 丢弃当前日志, 调用方立即返回
+	BackpressureDropNewest
+
+	// BackpressureDropOldest 丢弃队列中最早的日志, 为当前日志腾出空位
+	// 被挤出的日志所属级别使用阻塞策略时不会丢弃, 而是由调用方直接写入
+	BackpressureDropOldest
+
+	// BackpressureBlockTimeout 阻塞调用方至多 AsyncConfig.BlockTimeout, 超时后丢弃当前日志
+	BackpressureBlockTimeout
+)
+
+// String 返回策略名称
+//
+// 返回:
+//   - string: 策略名称
+func (p BackpressurePolicy) String() string {
+	switch p {
+	case BackpressureBlock:
+		return "block"
+	case BackpressureDropNewest:
+		return "drop_newest"
+	case BackpressureDropOldest:
+		return "drop_oldest"
+	case BackpressureBlockTimeout:
+		return "block_timeout"
+	default:
+		return fmt.Sprintf("BackpressurePolicy(%d)", uint8(p))
+	}
+}
+
+// 异步日志默认值
+const (
+	DefaultAsyncQueueSize           = 8192                   // 默认队列容量 (条)
+	DefaultAsyncWorkers             = 1                      // 默认消费协程数量
+	DefaultAsyncDrainTimeout        = 5 * time.Second        // 默认 Sync/Close 等待队列清空的最长时间
+	DefaultAsyncBlockTimeout        = 100 * time.Millisecond // 默认 BackpressureBlockTimeout 的最长等待时间
+	DefaultAsyncDropSummaryInterval = 10 * time.Second       // 默认丢弃摘要的输出间隔
+)
+
+// errAsyncDrainTimeout 在期限内未能清空异步队列
+var errAsyncDrainTimeout = errors.New("async log queue not drained before timeout")
+
+// AsyncConfig 异步日志配置
+//
+// 启用后日志在调用方协程完成级别检查、采样和格式化, 随后放入有界环形队列立即返回;
+// 写入器写入和 hooks 由后台消费协程执行, 慢速磁盘或终端不会阻塞业务协程。
+// 队列已满时按级别对应的 BackpressurePolicy 处理, 默认阻塞等待空位。
+//
+// 示例:
+//
+//	cfg := fastlog.NewConfig("logs/app.log")
+//	cfg.AsyncLog = &fastlog.AsyncConfig{
+//	    QueueSize: 16384,
+//	    Policy:    fastlog.BackpressureDropNewest, // 默认丢弃
+//	    LevelPolicies: map[fastlog.Level]fastlog.BackpressurePolicy{
+//	        fastlog.ERROR: fastlog.BackpressureBlock, // ERROR 及以上从不丢弃
+//	        fastlog.FATAL: fastlog.BackpressureBlock,
+//	        fastlog.PANIC: fastlog.BackpressureBlock,
+//	    },
+//	}
+//	logger := fastlog.New(cfg)
+//	defer func() { _ = logger.Close() }() // 等待队列清空后关闭
+type AsyncConfig struct {
+	// QueueSize 队列容量 (条), 向上取整为 2 的幂 (至少为 2), 零值默认 8192
+	QueueSize int
+
+	// Workers 消费协程数量, 零值默认 1
+	// 多于 1 时写入并发执行 (仍串行写入同一写入器), 不保证日志的输出顺序
+	Workers int
+
+	// DrainTimeout Sync 和 Close 等待队列清空的最长时间, 零值默认 5 秒
+	DrainTimeout time.Duration
+
+	// ======== 背压配置 ========
+
+	// Policy 队列已满时的默认处理策略, 零值默认 BackpressureBlock
+	Policy BackpressurePolicy
+
+	// LevelPolicies 按级别覆盖处理策略, 未列出的级别使用 Policy, 未列出的 Always 级别使用 BackpressureBlock
+	LevelPolicies map[Level]BackpressurePolicy
+
+	// BlockTimeout BackpressureBlockTimeout 策略的最长等待时间, 零值默认 100 毫秒
+	BlockTimeout time.Duration
+
+	// DropSummaryInterval 丢弃摘要的输出间隔, 零值默认 10 秒, 负数表示不输出
+	// 间隔内有日志被丢弃时, 向日志中写入一条 WARN 级别的 "N log entries dropped" 摘要
+	DropSummaryInterval time.Duration
+}
+
+// validate 验证异步日志配置
+//
+// 返回:
+//   - error: 验证通过时返回 nil, 否则返回错误信息
+func (c *AsyncConfig) validate() error {
+	if c.QueueSize < 0 {
+		return errors.New("async queue size must be >= 0")
+	}
+	if c.Workers < 0 {
+		return errors.New("async workers must be >= 0")
+	}
+	if c.DrainTimeout < 0 || c.BlockTimeout < 0 {
+		return errors.New("async timeouts must be >= 0")
+	}
+	if c.Policy > BackpressureBlockTimeout {
+		return fmt.Errorf("unsupported backpressure policy %d", c.Policy)
+	}
+	for lvl, p := range c.LevelPolicies {
+		if p > BackpressureBlockTimeout {
+			return fmt.Errorf("unsupported backpressure policy %d for level %s", p, lvl)
+		}
+	}
+	return nil
+}
+
+// AsyncStats 异步日志统计信息
+type AsyncStats struct {
+	Queued         int              // 当前排队的日志条数
+	Processed      uint64           // 已写入的日志条数
+	Dropped        uint64           // 因背压策略、关闭后记录或关闭超时而丢弃的日志条数
+	DroppedByLevel map[Level]uint64 // 按级别统计的丢弃条数, 只包含有丢弃的级别
+}
+
+// asyncRecord 队列中的一条日志
+type asyncRecord struct {
+	level    Level     // 日志级别, 用于背压策略和丢弃统计
+	data     []byte    // 格式化后的日志
+	buf      *[]byte   // data 所属的池化缓冲区, 非追加式格式化器时为 nil
+	rendered *rendered // 多格式化器的格式化结果, 配置了 Outputs 等时代替 data
+	entry    *Entry    // 日志条目副本, 供 hooks 使用, 没有 hooks 时为 nil
+}
+
+// ringSlot 环形队列槽位
+type ringSlot struct {
+	seq atomic.Uint64 // 序号: 等于位置时可写入, 等于位置+1 时可读取
+	rec asyncRecord   // 日志记录
+}
+
+// ringBuffer 有界无锁多生产者队列 (Vyukov bounded MPMC queue)
+//
+// 生产者和消费者分别通过 CAS 推进 tail 和 head, 槽位序号保证写入完成后才能被读取。
+type ringBuffer struct {
+	_     [64]byte      // 填充, 避免伪共享
+	tail  atomic.Uint64 // 下一个写入位置
+	_     [56]byte      // 填充
+	head  atomic.Uint64 // 下一个读取位置
+	_     [56]byte      // 填充
+	mask  uint64        // 容量 - 1
+	slots []ringSlot    // 槽位
+}
+
+// newRingBuffer 创建容量为 size 向上取整到 2 的幂的环形队列
+//
+// 容量至少为 2: 容量为 1 时 "已满" 与 "可写入下一位置" 的槽位序号相同, 无法区分。
+func newRingBuffer(size int) *ringBuffer {
+	n := uint64(2)
+	for n < uint64(size) {
+		n <<= 1
+	}
+	r := &ringBuffer{mask: n - 1, slots: make([]ringSlot, n)}
+	for i := range r.slots {
+		r.slots[i].seq.Store(uint64(i))
+	}
+	return r
+}
+
+// push 写入一条记录, 队列已满时返回 false
+func (r *ringBuffer) push(rec asyncRecord) bool {
+	pos := r.tail.Load()
+	for {
+		slot := &r.slots[pos&r.mask]
+		switch diff := int64(slot.seq.Load() - pos); {
+		case diff == 0:
+			if r.tail.CompareAndSwap(pos, pos+1) {
+				slot.rec = rec
+				slot.seq.Store(pos + 1)
+				return true
+			}
+			pos = r.tail.Load()
+		case diff < 0:
+			return false
+		default:
+			pos = r.tail.Load()
+		}
+	}
+}
+
+// pop 读取一条记录, 队列为空 (或下一条尚未写入完成) 时返回 false
+func (r *ringBuffer) pop() (asyncRecord, bool) {
+	pos := r.head.Load()
+	for {
+		slot := &r.slots[pos&r.mask]
+		switch diff := int64(slot.seq.Load() - (pos + 1)); {
+		case diff == 0:
+			if r.head.CompareAndSwap(pos, pos+1) {
+				rec := slot.rec
+				slot.rec = asyncRecord{}
+				slot.seq.Store(pos + r.mask + 1)
+				return rec, true
+			}
+			pos = r.head.Load()
+		case diff < 0:
+			return asyncRecord{}, false
+		default:
+			pos = r.head.Load()
+		}
+	}
+}
+
+// len 返回当前队列长度的近似值
+func (r *ringBuffer) len() int {
+	head := r.head.Load() // 先读 head, 保证 tail >= head
+	return int(r.tail.Load() - head)
+}
+
+// asyncCore 异步日志核心, 与子日志记录器共享
+type asyncCore struct {
+	l               *Logger                      // 根日志记录器, 提供写入器、hooks 和锁
+	ring            *ringBuffer                  // 日志队列
+	drainTimeout    time.Duration                // Sync/Close 等待队列清空的最长时间
+	policy          BackpressurePolicy           // 默认背压策略
+	levelPolicies   map[Level]BackpressurePolicy // 按级别覆盖的背压策略, 只读
+	blockTimeout    time.Duration                // BackpressureBlockTimeout 的最长等待时间
+	summaryInterval time.Duration                // 丢弃摘要间隔, <= 0 表示不输出
+
+	enqueued  atomic.Uint64                // 已入队条数
+	finished  atomic.Uint64                // 已出队条数 (写入或被挤出), drain 据此判断队列是否清空
+	processed atomic.Uint64                // 已写入条数
+	dropped   [maxLevels + 1]atomic.Uint64 // 按级别槽位的丢弃条数, 下标 0 统计未注册的级别
+	inflight  atomic.Int64                 // 正在入队的生产者数量, Close 据此等待入队完成
+	waiting   atomic.Int32                 // 空闲等待的消费协程数量
+	closed    atomic.Bool                  // 是否已关闭, 关闭后不再入队
+	stopped   atomic.Bool                  // 消费协程是否应立即退出
+
+	wake      chan struct{}  // 唤醒空闲的消费协程
+	stop      chan struct{}  // 停止信号
+	wg        sync.WaitGroup // 等待消费协程退出
+	closeOnce sync.Once      // 保证只关闭一次
+	closeErr  error          // 关闭结果
+
+	summaryMu   sync.Mutex            // 保护 reported
+	reported    [maxLevels + 1]uint64 // 已在摘要中报告的丢弃条数
+	summaryStop chan struct{}         // 停止丢弃摘要协程
+	summaryDone chan struct{}         // 丢弃摘要协程已退出
+}
+
+// newAsyncCore 创建异步日志核心并启动消费协程
+//
+// 参数:
+//   - l: 根日志记录器
+//   - cfg: 异步日志配置
+//
+// 返回:
+//   - *asyncCore: 异步日志核心
+func newAsyncCore(l *Logger, cfg *AsyncConfig) *asyncCore {
+	size := cfg.QueueSize
+	if size == 0 {
+		size = DefaultAsyncQueueSize
+	}
+	workers := cfg.Workers
+	if workers == 0 {
+		workers = DefaultAsyncWorkers
+	}
+	a := &asyncCore{
+		l:               l,
+		ring:            newRingBuffer(size),
+		drainTimeout:    cfg.DrainTimeout,
+		policy:          cfg.Policy,
+		blockTimeout:    cfg.BlockTimeout,
+		summaryInterval: cfg.DropSummaryInterval,
+		wake:            make(chan struct{}, workers),
+		stop:            make(chan struct{}),
+	}
+	if a.drainTimeout == 0 {
+		a.drainTimeout = DefaultAsyncDrainTimeout
+	}
+	if a.blockTimeout == 0 {
+		a.blockTimeout = DefaultAsyncBlockTimeout
+	}
+	if a.summaryInterval == 0 {
+		a.summaryInterval = DefaultAsyncDropSummaryInterval
+	}
+	if len(cfg.LevelPolicies) > 0 {
+		a.levelPolicies = make(map[Level]BackpressurePolicy, len(cfg.LevelPolicies))
+		for lvl, p := range cfg.LevelPolicies {
+			a.levelPolicies[lvl] = p
+		}
+	}
+
+	a.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go a.consume()
+	}
+	if a.summaryInterval > 0 {
+		a.summaryStop = make(chan struct{})
+		a.summaryDone = make(chan struct{})
+		go a.summarize()
+	}
+	return a
+}
+
+// policyFor 返回级别对应的背压策略, 未单独配置的 Always 级别使用 BackpressureBlock
+func (a *asyncCore) policyFor(level Level) BackpressurePolicy {
+	if p, ok := a.levelPolicies[level]; ok {
+		return p
+	}
+	if alwaysEnabled(level) {
+		return BackpressureBlock
+	}
+	return a.policy
+}
+
+// droppable 返回该级别的日志是否可被丢弃 (阻塞策略的级别不可丢弃)
+func (a *asyncCore) droppable(level Level) bool {
+	return a.policyFor(level) != BackpressureBlock
+}
+
+// drop 丢弃一条日志: 计数并归还缓冲区和条目
+func (a *asyncCore) drop(rec asyncRecord) {
+	a.dropped[levelSlot(rec.level)].Add(1)
+	a.release(rec)
+}
+
+// release 归还记录持有的缓冲区和条目
+func (a *asyncCore) release(rec asyncRecord) {
+	if rec.buf != nil {
+		putBuffer(rec.buf, rec.data)
+	}
+	if rec.rendered != nil {
+		rec.rendered.release()
+	}
+	if rec.entry != nil {
+		PutEntry(rec.entry)
+	}
+}
+
+// enqueue 将已格式化的日志放入队列, 队列已满时按背压策略处理
+//
+// 参数:
+//   - entry: 日志条目, 存在 hooks 时复制一份交给消费协程
+//   - rec: 格式化结果, 缓冲区所有权转交给队列
+//   - policy: 队列已满时的背压策略, 为 nil 时使用级别对应的策略
+//
+// 返回:
+//   - bool: 放入队列时返回 true, 被丢弃时返回 false
+func (a *asyncCore) enqueue(entry *Entry, rec asyncRecord, policy *BackpressurePolicy) bool {
+	a.inflight.Add(1)
+	defer a.inflight.Add(-1)
+
+	rec.level = entry.Level
+	if a.closed.Load() {
+		a.drop(rec)
+		return false
+	}
+	if len(a.l.hooks) > 0 {
+		rec.entry = copyEntry(entry)
+	}
+
+	p := a.policyFor(rec.level)
+	if policy != nil {
+		p = *policy
+	}
+	if !a.ring.push(rec) && !a.pushFull(rec, p) {
+		a.drop(rec)
+		return false
+	}
+	a.enqueued.Add(1)
+
+	// 有消费协程空闲时唤醒一个; 与 consume 中先登记等待再重新检查队列配合, 不会丢失唤醒
+	if a.waiting.Load() > 0 {
+		select {
+		case a.wake <- struct{}{}:
+		default:
+		}
+	}
+	return true
+}
+
+// pushFull 队列已满时按背压策略重试写入
+//
+// 参数:
+//   - rec: 日志记录
+//   - policy: 背压策略
+//
+// 返回:
+//   - bool: 写入成功返回 true, 需要丢弃当前日志时返回 false
+func (a *asyncCore) pushFull(rec asyncRecord, policy BackpressurePolicy) bool {
+	if policy == BackpressureDropNewest {
+		return false
+	}
+
+	var deadline time.Time
+	if policy == BackpressureBlockTimeout {
+		deadline = time.Now().Add(a.blockTimeout)
+	}
+	for spins := 0; !a.ring.push(rec); spins++ {
+		if policy == BackpressureDropOldest {
+			// 挤出最早的日志; 其级别不可丢弃时由调用方直接写入, 保证不丢失
+			if old, ok := a.ring.pop(); ok {
+				a.finished.Add(1)
+				if a.droppable(old.level) {
+					a.drop(old)
+				} else {
+					a.process(old)
+				}
+				continue
+			}
+		}
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			return false
+		}
+
+		// 等待消费协程腾出空位: 先让出处理器, 之后短暂休眠
+		if spins < 16 {
+			runtime.Gosched()
+		} else {
+			time.Sleep(50 * time.Microsecond)
+		}
+	}
+	return true
+}
+
+// copyEntry 复制日志条目及其字段, 供异步 hooks 使用
+func copyEntry(entry *Entry) *Entry {
+	e := GetEntry()
+	fields := append(e.Fields[:0], entry.Fields...)
+	*e = *entry
+	e.Fields = fields
+	e.prefix = nil // 副本可能被异步 hook 修改, 不使用编码缓存
+	return e
+}
+
+// consume 消费协程: 依次写入队列中的日志, 队列为空时等待唤醒
+func (a *asyncCore) consume() {
+	defer a.wg.Done()
+	for !a.stopped.Load() {
+		if rec, ok := a.ring.pop(); ok {
+			a.process(rec)
+			a.finished.Add(1)
+			continue
+		}
+
+		// 先登记等待再重新检查, 避免在检查与等待之间错过生产者的唤醒
+		a.waiting.Add(1)
+		if rec, ok := a.ring.pop(); ok {
+			a.waiting.Add(-1)
+			a.process(rec)
+			a.finished.Add(1)
+			continue
+		}
+		select {
+		case <-a.wake:
+		case <-a.stop:
+		}
+		a.waiting.Add(-1)
+	}
+}
+
+// process 写入一条日志并执行 hooks, 随后归还缓冲区和条目
+func (a *asyncCore) process(rec asyncRecord) {
+	if rec.rendered != nil {
+		a.l.writeRendered(rec.entry, rec.rendered)
+	} else {
+		a.l.write(rec.level, rec.entry, rec.data)
+	}
+	a.release(rec)
+	a.processed.Add(1)
+}
+
+// drain 等待当前已入队的日志全部写入
+//
+// 参数:
+//   - deadline: 最长等待到该时间
+//
+// 返回:
+//   - error: 超时返回 errAsyncDrainTimeout
+func (a *asyncCore) drain(deadline time.Time) error {
+	target := a.enqueued.Load()
+	wait := 50 * time.Microsecond
+	for a.finished.Load() < target {
+		if time.Now().After(deadline) {
+			return errAsyncDrainTimeout
+		}
+		time.Sleep(wait)
+		wait = min(wait*2, time.Millisecond)
+	}
+	return nil
+}
+
+// sync 等待队列清空
+//
+// 返回:
+//   - error: 期限内未清空时返回错误
+func (a *asyncCore) sync() error {
+	return a.drain(time.Now().Add(a.drainTimeout))
+}
+
+// close 停止接收新日志, 等待队列清空后停止消费协程
+//
+// 超过期限仍未写入的日志计入丢弃数。重复调用返回首次关闭的结果。
+//
+// 返回:
+//   - error: 期限内未清空时返回错误
+func (a *asyncCore) close() error {
+	a.closeOnce.Do(func() {
+		deadline := time.Now().Add(a.drainTimeout)
+
+		// 停止摘要协程, 并在关闭前报告尚未报告的丢弃
+		if a.summaryStop != nil {
+			close(a.summaryStop)
+			<-a.summaryDone
+			a.reportDrops()
+		}
+
+		a.closed.Store(true)
+		for a.inflight.Load() > 0 {
+			runtime.Gosched() // 等待已通过关闭检查的生产者完成入队
+		}
+
+		if err := a.drain(deadline); err != nil {
+			a.closeErr = fmt.Errorf("%w: %d entries dropped", err, a.enqueued.Load()-a.finished.Load())
+		}
+		a.stopped.Store(true)
+		close(a.stop)
+		a.wg.Wait()
+
+		// 丢弃未写入的日志
+		for {
+			rec, ok := a.ring.pop()
+			if !ok {
+				break
+			}
+			a.finished.Add(1)
+			a.drop(rec)
+		}
+	})
+	return a.closeErr
+}
+
+// summarize 丢弃摘要协程, 按间隔报告新增的丢弃
+func (a *asyncCore) summarize() {
+	defer close(a.summaryDone)
+	ticker := time.NewTicker(a.summaryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-a.summaryStop:
+			return
+		case <-ticker.C:
+			a.reportDrops()
+		}
+	}
+}
+
+// reportDrops 自上次报告以来有日志被丢弃时, 写入一条 WARN 级别的摘要
+//
+// 摘要绕过级别和采样检查, 以 BackpressureBlockTimeout 策略入队;
+// 仍未能入队时本次统计留到下一次报告。
+//
+//	12 log entries dropped  dropped=12 by_level.DEBUG=10 by_level.INFO=2
+func (a *asyncCore) reportDrops() {
+	a.summaryMu.Lock()
+	defer a.summaryMu.Unlock()
+
+	var total uint64
+	var fields []Field
+	var counts [maxLevels + 1]uint64
+	for i := range a.dropped {
+		counts[i] = a.dropped[i].Load()
+		delta := counts[i] - a.reported[i]
+		if delta == 0 {
+			continue
+		}
+		total += delta
+		name := "other"
+		if i > 0 {
+			name = slotLevel(i).String()
+		}
+		fields = append(fields, Uint64(name, delta))
+	}
+	if total == 0 {
+		return
+	}
+
+	fields = append([]Field{Uint64("dropped", total), Namespace("by_level")}, fields...)
+	policy := BackpressureBlockTimeout
+	if a.l.emit(time.Now(), WARN, fmt.Sprintf("%d log entries dropped", total), fields, 0, &policy) {
+		a.reported = counts
+	}
+}
+
+// stats 返回统计信息快照
+func (a *asyncCore) stats() AsyncStats {
+	st := AsyncStats{
+		Queued:    a.ring.len(),
+		Processed: a.processed.Load(),
+	}
+	for i := range a.dropped {
+		n := a.dropped[i].Load()
+		if n == 0 {
+			continue
+		}
+		if st.DroppedByLevel == nil {
+			st.DroppedByLevel = make(map[Level]uint64)
+		}
+		st.DroppedByLevel[slotLevel(i)] = n
+		st.Dropped += n
+	}
+	return st
+}
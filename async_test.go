@@ -0,0 +1,417 @@
+package fastlog
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// gateWriter 在 gate 关闭前阻塞写入, 模拟慢速磁盘
+type gateWriter struct {
+	mu    sync.Mutex
+	lines []string
+	gate  chan struct{}
+}
+
+func (g *gateWriter) Write(p []byte) (int, error) {
+	<-g.gate
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.lines = append(g.lines, strings.TrimSuffix(string(p), "\n"))
+	return len(p), nil
+}
+
+func (g *gateWriter) Close() error { return nil }
+
+func (g *gateWriter) written() []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]string(nil), g.lines...)
+}
+
+func TestRingBufferConcurrent(t *testing.T) {
+	r := newRingBuffer(5)
+	if len(r.slots) != 8 {
+		t.Fatalf("capacity = %d, want 8", len(r.slots))
+	}
+	for i := 0; i < 8; i++ {
+		if !r.push(asyncRecord{data: []byte{byte(i)}}) {
+			t.Fatalf("push(%d) failed before full", i)
+		}
+	}
+	if r.push(asyncRecord{}) || r.len() != 8 {
+		t.Fatalf("push on full queue should fail, len = %d", r.len())
+	}
+	for i := 0; i < 8; i++ {
+		if rec, ok := r.pop(); !ok || rec.data[0] != byte(i) {
+			t.Fatalf("pop(%d) = %v, %v", i, rec.data, ok)
+		}
+	}
+	if _, ok := r.pop(); ok {
+		t.Fatal("pop on empty queue should fail")
+	}
+
+	// 多生产者多消费者: 每条记录恰好被取出一次
+	r = newRingBuffer(64)
+	const producers, perProducer = 4, 5000
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < perProducer; i++ {
+				rec := asyncRecord{data: []byte(fmt.Sprintf("%d-%d", p, i))}
+				for !r.push(rec) {
+					runtime.Gosched()
+				}
+			}
+		}(p)
+	}
+	seen := make(chan string, producers*perProducer)
+	var consumers sync.WaitGroup
+	done := make(chan struct{})
+	for c := 0; c < 2; c++ {
+		consumers.Add(1)
+		go func() {
+			defer consumers.Done()
+			for {
+				if rec, ok := r.pop(); ok {
+					seen <- string(rec.data)
+					continue
+				}
+				select {
+				case <-done:
+					if r.len() == 0 {
+						return
+					}
+				default:
+					runtime.Gosched()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(done)
+	consumers.Wait()
+	close(seen)
+
+	unique := make(map[string]bool)
+	for s := range seen {
+		if unique[s] {
+			t.Fatalf("record %s popped twice", s)
+		}
+		unique[s] = true
+	}
+	if len(unique) != producers*perProducer {
+		t.Errorf("popped %d records, want %d", len(unique), producers*perProducer)
+	}
+}
+
+func TestAsyncLoggerDoesNotBlockCaller(t *testing.T) {
+	w := &gateWriter{gate: make(chan struct{})}
+	l := New(&Config{OutputConsole: true, Formatter: Simple{}, AsyncLog: &AsyncConfig{QueueSize: 16}})
+	l.writer = w
+
+	// 写入器阻塞期间记录日志立即返回
+	start := time.Now()
+	for i := 0; i < 10; i++ {
+		l.Infow("queued", Int("n", i))
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("logging blocked for %v", elapsed)
+	}
+	if st := l.AsyncStats(); st.Processed != 0 {
+		t.Fatalf("stats before release = %+v", st)
+	}
+
+	close(w.gate)
+	if err := l.Sync(); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	lines := w.written()
+	if len(lines) != 10 {
+		t.Fatalf("written %d lines, want 10", len(lines))
+	}
+	for i, line := range lines {
+		if !strings.HasSuffix(line, fmt.Sprintf("queued n=%d", i)) {
+			t.Errorf("line %d = %q, want in-order output", i, line)
+		}
+	}
+
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	l.Info("after close")
+	if st := l.AsyncStats(); st.Processed != 10 || st.Dropped != 1 || st.Queued != 0 {
+		t.Errorf("stats after close = %+v", st)
+	}
+}
+
+func TestAsyncLoggerQueueFullBlocks(t *testing.T) {
+	w := &gateWriter{gate: make(chan struct{})}
+	l := New(&Config{OutputConsole: true, Formatter: Simple{}, AsyncLog: &AsyncConfig{QueueSize: 2}})
+	l.writer = w
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 20; i++ {
+			l.Info("x")
+		}
+	}()
+	select {
+	case <-done:
+		t.Fatal("logging should wait for space when the queue is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(w.gate)
+	<-done
+	_ = l.Close()
+	if got := len(w.written()); got != 20 {
+		t.Errorf("written %d lines, want 20 (no loss when blocking)", got)
+	}
+}
+
+func TestAsyncLoggerDrainTimeout(t *testing.T) {
+	w := &gateWriter{gate: make(chan struct{})}
+	l := New(&Config{OutputConsole: true, Formatter: Simple{}, AsyncLog: &AsyncConfig{DrainTimeout: 20 * time.Millisecond}})
+	l.writer = w
+
+	l.Info("one")
+	l.Info("two")
+	if err := l.Sync(); err == nil {
+		t.Error("Sync() should time out while the writer is blocked")
+	}
+
+	closed := make(chan error, 1)
+	go func() { closed <- l.Close() }()
+	time.Sleep(50 * time.Millisecond) // 超过期限后释放写入器, 正在写入的一条完成, 其余丢弃
+	close(w.gate)
+	if err := <-closed; err == nil || !strings.Contains(err.Error(), "dropped") {
+		t.Errorf("Close() error = %v, want drain timeout", err)
+	}
+	if st := l.AsyncStats(); st.Processed != 1 || st.Dropped != 1 {
+		t.Errorf("stats = %+v", st)
+	}
+}
+
+func TestAsyncLoggerHooksAndChildren(t *testing.T) {
+	srv := newSinkServer(t)
+	m := newMock()
+	l := New(&Config{
+		OutputConsole: true,
+		Formatter:     Simple{},
+		HTTP:          &HTTPSinkConfig{URL: srv.URL},
+		AsyncLog:      &AsyncConfig{Workers: 2},
+	})
+	l.writer = m
+
+	child := l.Named("api").With(String("k", "v"))
+	child.Infow("from child", Int("n", 1))
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if !strings.Contains(m.String(), "from child") || !m.closed {
+		t.Errorf("writer output = %q, closed = %v", m.String(), m.closed)
+	}
+	reqs := srv.received()
+	if len(reqs) != 1 || !strings.Contains(string(reqs[0].body), `"n":1`) || !strings.Contains(string(reqs[0].body), `"k":"v"`) {
+		t.Errorf("hook requests = %+v", reqs)
+	}
+}
+
+func TestConfigAsyncValidate(t *testing.T) {
+	for _, c := range []*AsyncConfig{
+		{QueueSize: -1},
+		{Workers: -1},
+		{DrainTimeout: -time.Second},
+		{BlockTimeout: -time.Second},
+		{Policy: 9},
+		{LevelPolicies: map[Level]BackpressurePolicy{DEBUG: 9}},
+	} {
+		cfg := &Config{OutputConsole: true, AsyncLog: c}
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("Validate(%+v) should fail", *c)
+		}
+	}
+	cfg := &Config{OutputConsole: true, AsyncLog: &AsyncConfig{LevelPolicies: map[Level]BackpressurePolicy{DEBUG: BackpressureDropNewest}}}
+	clone := cfg.Clone()
+	clone.AsyncLog.LevelPolicies[DEBUG] = BackpressureBlock
+	if clone.AsyncLog == cfg.AsyncLog || cfg.AsyncLog.LevelPolicies[DEBUG] != BackpressureDropNewest {
+		t.Error("Clone() should copy AsyncLog and its LevelPolicies")
+	}
+	if BackpressureDropOldest.String() != "drop_oldest" || BackpressurePolicy(9).String() != "BackpressurePolicy(9)" {
+		t.Error("BackpressurePolicy.String() mismatch")
+	}
+}
+
+// BenchmarkLoggerAsync 与 BenchmarkLoggerJSON (同步写入) 对比
+func BenchmarkLoggerAsync(b *testing.B) {
+	l := New(&Config{Level: INFO, OutputConsole: true, Formatter: JSON{}, AsyncLog: &AsyncConfig{}})
+	l.writer = discardWriteCloser{}
+	defer func() { _ = l.Close() }()
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			l.Infow("用户登录成功", String("user", "alice"), Int("age", 30))
+		}
+	})
+}
+
+// newGatedAsyncLogger 创建写入器被阻塞的异步日志记录器, 并让消费协程取走第一条日志后阻塞,
+// 此后队列中可再容纳 QueueSize 条日志
+func newGatedAsyncLogger(t *testing.T, cfg *AsyncConfig) (*Logger, *gateWriter) {
+	t.Helper()
+	w := &gateWriter{gate: make(chan struct{})}
+	l := New(&Config{Level: DEBUG, OutputConsole: true, Formatter: MustPattern("%level %msg%[ %fields%]"), AsyncLog: cfg})
+	l.writer = w
+	l.Info("first")
+	waitFor(t, "consumer blocked", func() bool { return l.AsyncStats().Queued == 0 })
+	return l, w
+}
+
+// messages 返回写入的日志消息 (去掉级别), 以逗号分隔
+func messages(w *gateWriter) string {
+	var msgs []string
+	for _, line := range w.written() {
+		_, msg, _ := strings.Cut(line, " ")
+		msgs = append(msgs, msg)
+	}
+	return strings.Join(msgs, ",")
+}
+
+func TestAsyncBackpressurePolicies(t *testing.T) {
+	protectErrors := map[Level]BackpressurePolicy{ERROR: BackpressureBlock}
+
+	t.Run("drop newest except errors", func(t *testing.T) {
+		l, w := newGatedAsyncLogger(t, &AsyncConfig{QueueSize: 2, Policy: BackpressureDropNewest, LevelPolicies: protectErrors, DropSummaryInterval: -1})
+		l.Info("b")
+		l.Info("c")
+		l.Debug("dropped debug")
+		l.Info("dropped info")
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			l.Error("kept")
+		}()
+		select {
+		case <-done:
+			t.Fatal("ERROR with block policy should wait for space")
+		case <-time.After(30 * time.Millisecond):
+		}
+		close(w.gate)
+		<-done
+		_ = l.Close()
+
+		if got := messages(w); got != "first,b,c,kept" {
+			t.Errorf("written = %q", got)
+		}
+		st := l.AsyncStats()
+		if st.Dropped != 2 || st.DroppedByLevel[DEBUG] != 1 || st.DroppedByLevel[INFO] != 1 || st.DroppedByLevel[ERROR] != 0 {
+			t.Errorf("stats = %+v", st)
+		}
+	})
+
+	t.Run("drop oldest", func(t *testing.T) {
+		l, w := newGatedAsyncLogger(t, &AsyncConfig{QueueSize: 2, Policy: BackpressureDropOldest, DropSummaryInterval: -1})
+		l.Info("b")
+		l.Info("c")
+		l.Info("d") // 挤出 b
+		close(w.gate)
+		_ = l.Close()
+
+		if got := messages(w); got != "first,c,d" {
+			t.Errorf("written = %q", got)
+		}
+		if st := l.AsyncStats(); st.DroppedByLevel[INFO] != 1 {
+			t.Errorf("stats = %+v", st)
+		}
+	})
+
+	t.Run("drop oldest keeps protected levels", func(t *testing.T) {
+		l, w := newGatedAsyncLogger(t, &AsyncConfig{QueueSize: 2, Policy: BackpressureDropOldest, LevelPolicies: protectErrors, DropSummaryInterval: -1})
+		l.Error("e")
+		l.Info("b")
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			l.Info("c") // 挤出 ERROR 日志时由调用方直接写入
+		}()
+		time.Sleep(20 * time.Millisecond)
+		close(w.gate)
+		<-done
+		_ = l.Close()
+
+		// 被挤出的 ERROR 日志由调用方写入, 与消费协程的写入顺序不确定
+		got := strings.Split(messages(w), ",")
+		sort.Strings(got)
+		if strings.Join(got, ",") != "b,c,e,first" {
+			t.Errorf("written = %q", got)
+		}
+		if st := l.AsyncStats(); st.Dropped != 0 || st.Processed != 4 {
+			t.Errorf("stats = %+v", st)
+		}
+	})
+
+	t.Run("block with timeout", func(t *testing.T) {
+		l, w := newGatedAsyncLogger(t, &AsyncConfig{QueueSize: 2, Policy: BackpressureBlockTimeout, BlockTimeout: 20 * time.Millisecond, DropSummaryInterval: -1})
+		l.Warn("b")
+		l.Warn("c")
+		start := time.Now()
+		l.Warn("timed out")
+		if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+			t.Errorf("Warn() returned after %v, want to wait for BlockTimeout", elapsed)
+		}
+		close(w.gate)
+		_ = l.Close()
+
+		if got := messages(w); got != "first,b,c" {
+			t.Errorf("written = %q", got)
+		}
+		if st := l.AsyncStats(); st.DroppedByLevel[WARN] != 1 {
+			t.Errorf("stats = %+v", st)
+		}
+	})
+}
+
+func TestAsyncDropSummary(t *testing.T) {
+	t.Run("periodic", func(t *testing.T) {
+		l, w := newGatedAsyncLogger(t, &AsyncConfig{QueueSize: 2, Policy: BackpressureDropNewest, DropSummaryInterval: 20 * time.Millisecond})
+		defer func() { _ = l.Close() }()
+		l.Info("b")
+		l.Info("c")
+		l.Debug("x")
+		l.Debug("x")
+		l.Info("x")
+		close(w.gate)
+
+		waitFor(t, "drop summary", func() bool { return strings.Contains(messages(w), "entries dropped") })
+		lines := w.written()
+		summary := lines[len(lines)-1]
+		if summary != "WARN 3 log entries dropped dropped=3 by_level.DEBUG=2 by_level.INFO=1" {
+			t.Errorf("summary = %q", summary)
+		}
+	})
+
+	t.Run("on close", func(t *testing.T) {
+		l, w := newGatedAsyncLogger(t, &AsyncConfig{QueueSize: 2, Policy: BackpressureDropNewest, DropSummaryInterval: time.Hour})
+		l.SetLevel(ERROR) // 摘要不受级别过滤
+		l.Error("b")
+		l.Error("c")
+		l.Error("x")
+		close(w.gate)
+		_ = l.Close()
+
+		if got := messages(w); !strings.HasSuffix(got, ",1 log entries dropped dropped=1 by_level.ERROR=1") {
+			t.Errorf("written = %q", got)
+		}
+	})
+}
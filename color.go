@@ -0,0 +1,411 @@
+package fastlog
+
+import (
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+)
+
+// ColorDepth 终端支持的颜色深度
+type ColorDepth int
+
+const (
+	// ColorDepthAuto 自动检测 (默认)
+	ColorDepthAuto ColorDepth = iota
+
+	// ColorDepthNone 不输出颜色
+	ColorDepthNone
+
+	// ColorDepth16 16 色 (ANSI 基本色及其高亮色)
+	ColorDepth16
+
+	// ColorDepth256 256 色
+	ColorDepth256
+
+	// ColorDepthTrue 真彩色 (24 位 RGB)
+	ColorDepthTrue
+)
+
+// String 返回颜色深度的字符串表示
+func (d ColorDepth) String() string {
+	switch d {
+	case ColorDepthAuto:
+		return "auto"
+	case ColorDepthNone:
+		return "none"
+	case ColorDepth16:
+		return "16"
+	case ColorDepth256:
+		return "256"
+	case ColorDepthTrue:
+		return "truecolor"
+	default:
+		return "ColorDepth(" + strconv.Itoa(int(d)) + ")"
+	}
+}
+
+// DetectColorDepth 检测终端文件支持的颜色深度
+//
+// 检测顺序:
+//   - FORCE_COLOR: 设置后忽略是否为终端, 0 或 false 禁用颜色, 2 为 256 色, 3 为真彩色, 其他值至少 16 色
+//   - NO_COLOR: 非空时禁用颜色
+//   - f 不是终端 (管道、文件) 或 TERM=dumb 时禁用颜色
+//   - COLORTERM=truecolor/24bit 或 Windows Terminal 为真彩色, TERM 含 256color 为 256 色, 否则 16 色
+//
+// 参数:
+//   - f: 终端文件, 如 os.Stdout
+//
+// 返回:
+//   - ColorDepth: 颜色深度, 不会返回 ColorDepthAuto
+func DetectColorDepth(f *os.File) ColorDepth {
+	tty := f != nil && (isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd()))
+	return detectColorDepth(tty, os.LookupEnv)
+}
+
+// detectColorDepth 根据是否为终端和环境变量检测颜色深度
+//
+// 参数:
+//   - tty: 输出是否为终端
+//   - lookupEnv: 环境变量查询函数, 通常为 os.LookupEnv
+//
+// 返回:
+//   - ColorDepth: 颜色深度
+func detectColorDepth(tty bool, lookupEnv func(string) (string, bool)) ColorDepth {
+	if v, ok := lookupEnv("FORCE_COLOR"); ok {
+		switch strings.ToLower(v) {
+		case "0", "false":
+			return ColorDepthNone
+		case "2":
+			return ColorDepth256
+		case "3":
+			return ColorDepthTrue
+		}
+		return max(termColorDepth(lookupEnv), ColorDepth16)
+	}
+	if v, ok := lookupEnv("NO_COLOR"); ok && v != "" {
+		return ColorDepthNone
+	}
+	if !tty {
+		return ColorDepthNone
+	}
+	return termColorDepth(lookupEnv)
+}
+
+// termColorDepth 根据 TERM、COLORTERM 等终端环境变量判断颜色深度
+func termColorDepth(lookupEnv func(string) (string, bool)) ColorDepth {
+	term, _ := lookupEnv("TERM")
+	if term == "dumb" {
+		return ColorDepthNone
+	}
+	colorTerm, _ := lookupEnv("COLORTERM")
+	if colorTerm == "truecolor" || colorTerm == "24bit" || strings.Contains(term, "truecolor") || strings.Contains(term, "direct") {
+		return ColorDepthTrue
+	}
+	if _, ok := lookupEnv("WT_SESSION"); ok && runtime.GOOS == "windows" {
+		return ColorDepthTrue // Windows Terminal
+	}
+	if strings.Contains(term, "256color") {
+		return ColorDepth256
+	}
+	return ColorDepth16
+}
+
+// Color 终端颜色, 可以是 16 色、256 色或 RGB 真彩色
+//
+// 零值 ColorDefault 表示使用终端默认颜色。终端颜色深度不足时,
+// 256 色和 RGB 颜色自动转换为最接近的可用颜色。
+type Color uint32
+
+// 颜色种类, 存放在 Color 的高 8 位
+const (
+	colorKindBasic Color = 1 << 24 // 16 色, 低 4 位为编号
+	colorKind256   Color = 2 << 24 // 256 色, 低 8 位为编号
+	colorKindRGB   Color = 3 << 24 // 真彩色, 低 24 位为 RGB
+	colorKindMask  Color = 0xff << 24
+)
+
+// 16 色常量
+const (
+	ColorDefault       Color = 0                  // 终端默认颜色
+	ColorBlack         Color = colorKindBasic | 0 // 黑色
+	ColorRed           Color = colorKindBasic | 1 // 红色
+	ColorGreen         Color = colorKindBasic | 2 // 绿色
+	ColorYellow        Color = colorKindBasic | 3 // 黄色
+	ColorBlue          Color = colorKindBasic | 4 // 蓝色
+	ColorMagenta       Color = colorKindBasic | 5 // 紫色
+	ColorCyan          Color = colorKindBasic | 6 // 青色
+	ColorWhite         Color = colorKindBasic | 7 // 白色
+	ColorBrightBlack   Color = colorKindBasic | 8 // 亮黑色 (灰色)
+	ColorBrightRed     Color = colorKindBasic | 9
+	ColorBrightGreen   Color = colorKindBasic | 10
+	ColorBrightYellow  Color = colorKindBasic | 11
+	ColorBrightBlue    Color = colorKindBasic | 12
+	ColorBrightMagenta Color = colorKindBasic | 13
+	ColorBrightCyan    Color = colorKindBasic | 14
+	ColorBrightWhite   Color = colorKindBasic | 15
+)
+
+// Color256 返回 256 色调色板中的颜色
+//
+// 参数:
+//   - n: 调色板编号, 0~15 为基本色, 16~231 为 6x6x6 色块, 232~255 为灰阶
+//
+// 返回:
+//   - Color: 颜色
+func Color256(n uint8) Color {
+	return colorKind256 | Color(n)
+}
+
+// ColorRGB 返回 24 位真彩色
+//
+// 参数:
+//   - r: 红色分量
+//   - g: 绿色分量
+//   - b: 蓝色分量
+//
+// 返回:
+//   - Color: 颜色
+func ColorRGB(r, g, b uint8) Color {
+	return colorKindRGB | Color(r)<<16 | Color(g)<<8 | Color(b)
+}
+
+// basicPalette 16 色的近似 RGB 值 (xterm 默认配色), 用于降级时寻找最接近的颜色
+var basicPalette = [16][3]uint8{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// cubeLevels 256 色 6x6x6 色块每个分量的取值
+var cubeLevels = [6]uint8{0, 95, 135, 175, 215, 255}
+
+// rgb 返回颜色的 RGB 分量
+func (c Color) rgb() (r, g, b uint8) {
+	n := uint8(c)
+	switch c & colorKindMask {
+	case colorKindRGB:
+		return uint8(c >> 16), uint8(c >> 8), n
+	case colorKindBasic:
+		p := basicPalette[n&0x0f]
+		return p[0], p[1], p[2]
+	}
+	switch {
+	case n < 16:
+		p := basicPalette[n]
+		return p[0], p[1], p[2]
+	case n < 232:
+		n -= 16
+		return cubeLevels[n/36], cubeLevels[n/6%6], cubeLevels[n%6]
+	default:
+		v := 8 + (n-232)*10
+		return v, v, v
+	}
+}
+
+// to256 将颜色转换为 256 色编号
+func (c Color) to256() uint8 {
+	switch c & colorKindMask {
+	case colorKindBasic, colorKind256:
+		return uint8(c)
+	}
+	r, g, b := c.rgb()
+	// 灰色优先使用灰阶
+	if r == g && g == b {
+		switch {
+		case r < 8:
+			return 16
+		case r > 238:
+			return 231
+		default:
+			return 232 + (r-8)/10
+		}
+	}
+	return 16 + 36*cubeIndex(r) + 6*cubeIndex(g) + cubeIndex(b)
+}
+
+// cubeIndex 返回分量在 6x6x6 色块中最接近的取值编号
+func cubeIndex(v uint8) uint8 {
+	if v < 48 {
+		return 0
+	}
+	if v < 115 {
+		return 1
+	}
+	return (v - 35) / 40
+}
+
+// to16 将颜色转换为最接近的 16 色编号
+func (c Color) to16() uint8 {
+	if c&colorKindMask == colorKindBasic {
+		return uint8(c) & 0x0f
+	}
+	if c&colorKindMask == colorKind256 && uint8(c) < 16 {
+		return uint8(c)
+	}
+	r, g, b := c.rgb()
+	best, bestDist := 0, -1
+	for i, p := range basicPalette {
+		dr, dg, db := int(r)-int(p[0]), int(g)-int(p[1]), int(b)-int(p[2])
+		if d := dr*dr + dg*dg + db*db; bestDist < 0 || d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	return uint8(best)
+}
+
+// appendSGR 追加颜色的 SGR 参数 (不含 ESC [ 和 m)
+//
+// 参数:
+//   - dst: 目标缓冲区
+//   - depth: 颜色深度, 不足时降级
+//   - bg: 是否为背景色
+//
+// 返回:
+//   - []byte: 追加后的缓冲区
+func (c Color) appendSGR(dst []byte, depth ColorDepth, bg bool) []byte {
+	base := 30
+	if bg {
+		base = 40
+	}
+	switch {
+	case depth >= ColorDepthTrue && c&colorKindMask == colorKindRGB:
+		r, g, b := c.rgb()
+		dst = strconv.AppendInt(dst, int64(base+8), 10)
+		dst = append(dst, ";2;"...)
+		dst = strconv.AppendUint(dst, uint64(r), 10)
+		dst = append(dst, ';')
+		dst = strconv.AppendUint(dst, uint64(g), 10)
+		dst = append(dst, ';')
+		return strconv.AppendUint(dst, uint64(b), 10)
+	case depth >= ColorDepth256 && c&colorKindMask != colorKindBasic:
+		dst = strconv.AppendInt(dst, int64(base+8), 10)
+		dst = append(dst, ";5;"...)
+		return strconv.AppendUint(dst, uint64(c.to256()), 10)
+	default:
+		n := int(c.to16())
+		if n >= 8 {
+			base += 60 // 高亮色: 90~97 / 100~107
+			n -= 8
+		}
+		return strconv.AppendInt(dst, int64(base+n), 10)
+	}
+}
+
+// Style 终端文本样式
+type Style struct {
+	Fg        Color // 前景色, 零值为终端默认颜色
+	Bg        Color // 背景色, 零值为终端默认颜色
+	Bold      bool  // 加粗
+	Faint     bool  // 变暗
+	Italic    bool  // 斜体
+	Underline bool  // 下划线
+}
+
+// sequence 返回样式在指定颜色深度下的转义序列, 零值样式或无颜色时返回空字符串
+//
+// 参数:
+//   - depth: 颜色深度
+//
+// 返回:
+//   - string: 如 "\x1b[31;1m"
+func (s Style) sequence(depth ColorDepth) string {
+	if s == (Style{}) || depth <= ColorDepthNone {
+		return ""
+	}
+	buf := []byte("\x1b[")
+	params := len(buf)
+	sep := func() {
+		if len(buf) > params {
+			buf = append(buf, ';')
+		}
+	}
+	if s.Fg != ColorDefault {
+		buf = s.Fg.appendSGR(buf, depth, false)
+	}
+	if s.Bg != ColorDefault {
+		sep()
+		buf = s.Bg.appendSGR(buf, depth, true)
+	}
+	for i, on := range [4]bool{s.Bold, s.Faint, s.Italic, s.Underline} {
+		if on {
+			sep()
+			buf = strconv.AppendInt(buf, int64(i+1), 10)
+		}
+	}
+	return string(append(buf, 'm'))
+}
+
+// Theme 彩色输出主题, 为级别名称以及可选的时间戳、调用者和字段键指定样式
+//
+// 时间戳、调用者和字段键按 Def 等文本格式的布局识别: 级别名称之前以数字开头的部分
+// 为时间戳, 级别之后形如 file.go:func:line 的部分为调用者, key= 中的 key 为字段键。
+//
+// 示例:
+//
+//	theme := fastlog.DefaultTheme()
+//	theme.Levels[fastlog.INFO] = fastlog.Style{Fg: fastlog.ColorRGB(80, 200, 120), Bold: true}
+//	theme.Time = fastlog.Style{Fg: fastlog.ColorBrightBlack}
+//	theme.FieldKey = fastlog.Style{Fg: fastlog.ColorCyan}
+//	cfg.ColorTheme = theme
+type Theme struct {
+	Levels   map[Level]Style // 级别样式, 未设置的级别不着色
+	Time     Style           // 时间戳样式, 零值不着色
+	Caller   Style           // 调用者样式, 零值不着色
+	FieldKey Style           // 字段键样式, 零值不着色
+}
+
+// DefaultTheme 返回默认主题, 只为级别名称着色
+//
+// DEBUG 青色、INFO 蓝色、WARN 黄色、ERROR 和 FATAL 红色、PANIC 紫色, 均加粗;
+// 通过 RegisterLevel 注册的自定义级别使用其 Style。
+//
+// 返回:
+//   - *Theme: 新的默认主题, 可修改后使用
+func DefaultTheme() *Theme {
+	t := &Theme{Levels: make(map[Level]Style)}
+	for _, spec := range levels.Load().specs {
+		if spec.Style != (Style{}) {
+			t.Levels[spec.Level] = spec.Style
+		}
+	}
+	return t
+}
+
+// compiledTheme 按颜色深度预先生成转义序列的主题（内部使用）
+type compiledTheme struct {
+	levels   map[Level]string // 级别样式
+	time     string           // 时间戳样式
+	caller   string           // 调用者样式
+	fieldKey string           // 字段键样式
+}
+
+// compile 按颜色深度生成主题的转义序列
+//
+// 参数:
+//   - depth: 颜色深度
+//
+// 返回:
+//   - *compiledTheme: 预先生成的主题
+func (t *Theme) compile(depth ColorDepth) *compiledTheme {
+	ct := &compiledTheme{
+		levels:   make(map[Level]string, len(t.Levels)),
+		time:     t.Time.sequence(depth),
+		caller:   t.Caller.sequence(depth),
+		fieldKey: t.FieldKey.sequence(depth),
+	}
+	for lvl, s := range t.Levels {
+		if seq := s.sequence(depth); seq != "" {
+			ct.levels[lvl] = seq
+		}
+	}
+	return ct
+}
+
+// segments 是否需要为级别以外的部分着色
+func (ct *compiledTheme) segments() bool {
+	return ct.time != "" || ct.caller != "" || ct.fieldKey != ""
+}
@@ -0,0 +1,105 @@
+package fastlog
+
+import (
+	"bytes"
+	"testing"
+)
+
+// fakeEnv 返回基于映射的环境变量查询函数
+func fakeEnv(env map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+}
+
+func TestDetectColorDepth(t *testing.T) {
+	tests := []struct {
+		name string
+		tty  bool
+		env  map[string]string
+		want ColorDepth
+	}{
+		{"pipe", false, map[string]string{"TERM": "xterm-256color"}, ColorDepthNone},
+		{"basic terminal", true, map[string]string{"TERM": "xterm"}, ColorDepth16},
+		{"256 color terminal", true, map[string]string{"TERM": "xterm-256color"}, ColorDepth256},
+		{"truecolor terminal", true, map[string]string{"TERM": "xterm-256color", "COLORTERM": "truecolor"}, ColorDepthTrue},
+		{"dumb terminal", true, map[string]string{"TERM": "dumb"}, ColorDepthNone},
+		{"NO_COLOR", true, map[string]string{"TERM": "xterm", "NO_COLOR": "1"}, ColorDepthNone},
+		{"empty NO_COLOR", true, map[string]string{"TERM": "xterm", "NO_COLOR": ""}, ColorDepth16},
+		{"FORCE_COLOR on pipe", false, map[string]string{"FORCE_COLOR": "1", "TERM": "dumb"}, ColorDepth16},
+		{"FORCE_COLOR over NO_COLOR", false, map[string]string{"FORCE_COLOR": "", "NO_COLOR": "1", "TERM": "xterm-256color"}, ColorDepth256},
+		{"FORCE_COLOR=3", false, map[string]string{"FORCE_COLOR": "3"}, ColorDepthTrue},
+		{"FORCE_COLOR=0", true, map[string]string{"FORCE_COLOR": "0", "TERM": "xterm"}, ColorDepthNone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detectColorDepth(tt.tty, fakeEnv(tt.env)); got != tt.want {
+				t.Errorf("detectColorDepth() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStyleSequence(t *testing.T) {
+	orange := Style{Fg: ColorRGB(255, 135, 0), Bold: true}
+	tests := []struct {
+		name  string
+		style Style
+		depth ColorDepth
+		want  string
+	}{
+		{"basic", Style{Fg: ColorRed, Bold: true}, ColorDepth16, "\x1b[31;1m"},
+		{"bright with background", Style{Fg: ColorBrightWhite, Bg: ColorBlue, Underline: true}, ColorDepthTrue, "\x1b[97;44;4m"},
+		{"truecolor", orange, ColorDepthTrue, "\x1b[38;2;255;135;0;1m"},
+		{"rgb to 256", orange, ColorDepth256, "\x1b[38;5;208;1m"},
+		{"rgb to 16", orange, ColorDepth16, "\x1b[33;1m"},
+		{"256 gray to 16", Style{Fg: Color256(244)}, ColorDepth16, "\x1b[90m"},
+		{"attributes only", Style{Faint: true, Italic: true}, ColorDepth16, "\x1b[2;3m"},
+		{"no color", orange, ColorDepthNone, ""},
+		{"zero style", Style{}, ColorDepthTrue, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.style.sequence(tt.depth); got != tt.want {
+				t.Errorf("sequence() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestColorWriterTheme(t *testing.T) {
+	theme := DefaultTheme()
+	theme.Levels[INFO] = Style{Fg: ColorRGB(80, 200, 120)}
+	theme.Time = Style{Fg: ColorBrightBlack}
+	theme.Caller = Style{Underline: true}
+	theme.FieldKey = Style{Fg: ColorCyan}
+
+	buf := &bytes.Buffer{}
+	cw := &ColorWriter{w: buf, Depth: ColorDepthTrue, Theme: theme}
+	line := "2026-01-15 10:30:45 | INFO   | main.go:main:15 - ready port=80, tls.enabled=true\n"
+	if _, err := cw.WriteLevel(INFO, []byte(line)); err != nil {
+		t.Fatalf("WriteLevel() error = %v", err)
+	}
+	want := "\x1b[90m2026-01-15 10:30:45\x1b[0m | \x1b[38;2;80;200;120mINFO\x1b[0m   | \x1b[4mmain.go:main:15\x1b[0m - ready " +
+		"\x1b[36mport\x1b[0m=80, \x1b[36mtls.enabled\x1b[0m=true\n"
+	if got := buf.String(); got != want {
+		t.Errorf("themed output =\n%q\nwant\n%q", got, want)
+	}
+
+	// 无颜色终端原样输出
+	buf.Reset()
+	cw = &ColorWriter{w: buf, Depth: ColorDepthNone, Theme: theme}
+	_, _ = cw.WriteLevel(INFO, []byte(line))
+	if buf.String() != line {
+		t.Errorf("ColorDepthNone output = %q", buf.String())
+	}
+
+	// 级别没有样式时仍为其他部分着色
+	buf.Reset()
+	cw = &ColorWriter{w: buf, Depth: ColorDepth16, Theme: &Theme{FieldKey: Style{Fg: ColorCyan}}}
+	_, _ = cw.WriteLevel(WARN, []byte("WARN slow id=1\n"))
+	if got := buf.String(); got != "WARN slow \x1b[36mid\x1b[0m=1\n" {
+		t.Errorf("field key output = %q", got)
+	}
+}
@@ -227,8 +227,9 @@ func Docker() *Config {
 
 // Config 日志记录器配置
 //
-// OutputConsole 和 OutputFile 可同时启用, 日志会同时写入终端和文件。
-// 两者必须设置一个输出, 否则会报错。
+// OutputConsole 和 OutputFile 可同时启用, 日志会同时写入终端和文件, 两者共用 Formatter。
+// 需要每个输出使用不同格式化器或级别时使用 Outputs。
+// OutputConsole、OutputFile、Outputs、Net、Syslog 和 HTTP 至少设置一个输出, 否则会报错。
 type Config struct {
 	// ======== 基础日志配置 ========
 
@@ -244,6 +245,10 @@ type Config struct {
 	// Fields 预设字段, 每条日志都会自动携带这些字段
 	Fields []Field
 
+	// ContextExtractors 上下文字段提取器
+	// 通过 InfoCtx 等 *Ctx 方法或 Logger.Ctx 记录日志时依次调用, 提取的字段位于调用字段之前
+	ContextExtractors []ContextExtractor
+
 	// SamplerTick 采样时间窗口, 零值表示不启用采样
 	// 例如 10*time.Second 表示每 10 秒为一个采样窗口
 	SamplerTick time.Duration
@@ -262,6 +267,13 @@ type Config struct {
 	// NoColor 设为 true 时禁用终端彩色输出, 仅当 OutputConsole=true 时生效
 	NoColor bool
 
+	// ColorScope 彩色输出的着色范围, 零值仅为级别名称着色, ColorScopeLine 为整行着色
+	ColorScope ColorScope
+
+	// ColorTheme 终端彩色主题, nil 时使用 DefaultTheme
+	// 颜色深度根据终端和 NO_COLOR、FORCE_COLOR、TERM 等环境变量自动检测, 不支持时自动降级
+	ColorTheme *Theme
+
 	// ======== 文件输出配置 ========
 
 	// OutputFile 是否输出到文件
@@ -307,6 +319,46 @@ type Config struct {
 	// RotateByDay 是否按天轮转
 	RotateByDay bool
 
+	// ======== 独立输出配置 ========
+
+	// Outputs 独立输出列表, 每个输出使用自己的写入器、格式化器和最低级别
+	// 与 OutputConsole、OutputFile 并存; Level 为零值时默认取各输出的最低级别
+	Outputs []Output
+
+	// ======== 路由配置 ========
+
+	// Routes 路由规则, 按级别范围、日志记录器名称、消息前缀或字段值将日志额外写入规则指定的文件
+	// 启用 LevelRouter 时, 内置的级别规则 (见 LevelRoutes) 排在 Routes 之前
+	Routes []Route
+
+	// FanOut 按字段值分发配置, 非 nil 时日志额外按字段值 (如租户) 写入各自的文件
+	// 文件按需打开, 数量受 MaxOpen 限制, 空闲文件自动关闭
+	FanOut *FanOutConfig
+
+	// ======== 网络输出配置 ========
+
+	// Net 网络输出配置, 非 nil 时日志额外发送到 TCP、UDP 或 unix 套接字
+	// 断线期间日志写入有界缓冲区并在后台重连, 不会在每次写入时报错
+	Net *NetWriterConfig
+
+	// ======== Syslog 输出配置 ========
+
+	// Syslog syslog 输出配置, 非 nil 时每条日志额外以 syslog 格式发送
+	// 可单独使用, 此时 OutputConsole 和 OutputFile 均可为 false
+	Syslog *SyslogConfig
+
+	// ======== HTTP 批量输出配置 ========
+
+	// HTTP HTTP 批量输出配置, 非 nil 时日志额外按批发送到 Loki、Elasticsearch 或 JSON 接口
+	// 发送在后台进行, Logger.Close 会发送剩余日志后再返回
+	HTTP *HTTPSinkConfig
+
+	// ======== 异步日志配置 ========
+
+	// AsyncLog 异步日志配置, 非 nil 时写入器写入和 hooks 由后台消费协程执行
+	// 与 Async (日志文件的异步清理) 相互独立
+	AsyncLog *AsyncConfig
+
 	// ======== 缓冲写入配置 ========
 
 	// MaxBufferSize 缓冲区大小 (字节) , 零值默认 256KB
@@ -326,6 +378,9 @@ type Config struct {
 	// 注意: 启用后, 每条日志会同时写入主文件和对应级别专属文件
 	LevelRouter bool
 
+	// LevelRouterFormatter 级别专属文件使用的格式化器, nil 时使用 Formatter
+	LevelRouterFormatter Formatter
+
 	// BufferEnabled 是否启用缓冲写入
 	// true:  使用 BufferedWriter (默认) , 提升写入性能
 	// false: 直接使用 LogRotateX, 无缓冲, 立即落盘
@@ -350,47 +405,102 @@ func (c *Config) NewSampler() *Sampler {
 // Clone 克隆配置
 //
 // 返回配置的深拷贝副本, 与原始配置完全独立互不干扰。
-// Fields 切片会独立复制。
+// Fields、ContextExtractors、Outputs、Routes 切片以及 FanOut、Net、Syslog、HTTP、AsyncLog 配置会独立复制。
 func (c *Config) Clone() *Config {
 	clone := *c
 	if len(c.Fields) > 0 {
 		clone.Fields = make([]Field, len(c.Fields))
 		copy(clone.Fields, c.Fields)
 	}
+	if len(c.ContextExtractors) > 0 {
+		clone.ContextExtractors = make([]ContextExtractor, len(c.ContextExtractors))
+		copy(clone.ContextExtractors, c.ContextExtractors)
+	}
+	if len(c.Outputs) > 0 {
+		clone.Outputs = make([]Output, len(c.Outputs))
+		copy(clone.Outputs, c.Outputs)
+	}
+	if len(c.Routes) > 0 {
+		clone.Routes = make([]Route, len(c.Routes))
+		copy(clone.Routes, c.Routes)
+	}
+	if c.FanOut != nil {
+		fanOutCfg := *c.FanOut
+		clone.FanOut = &fanOutCfg
+	}
+	if c.Net != nil {
+		netCfg := *c.Net
+		clone.Net = &netCfg
+	}
+	if c.Syslog != nil {
+		syslogCfg := *c.Syslog
+		clone.Syslog = &syslogCfg
+	}
+	if c.HTTP != nil {
+		httpCfg := *c.HTTP
+		clone.HTTP = &httpCfg
+	}
+	if c.AsyncLog != nil {
+		asyncCfg := *c.AsyncLog
+		if c.AsyncLog.LevelPolicies != nil {
+			asyncCfg.LevelPolicies = make(map[Level]BackpressurePolicy, len(c.AsyncLog.LevelPolicies))
+			for lvl, p := range c.AsyncLog.LevelPolicies {
+				asyncCfg.LevelPolicies[lvl] = p
+			}
+		}
+		clone.AsyncLog = &asyncCfg
+	}
 	return &clone
 }
 
 // NewWriter 根据配置创建日志写入器
 //
-// 返回日志写入器, 用于将日志写入终端或文件。
+// 返回日志写入器, 用于将日志写入终端、文件或网络。
 func (c *Config) NewWriter() io.WriteCloser {
-	// 根据配置创建对应的写入器
-	switch {
-	// 情况1: 仅终端输出
-	case !c.OutputFile && c.OutputConsole:
-		return NewColorWriter(c.NoColor)
-
-	// 情况2: 仅文件输出
-	case c.OutputFile && !c.OutputConsole:
-		return c.newFileWriter()
-
-	// 情况3: 同时输出到文件和终端
-	case c.OutputFile && c.OutputConsole:
-		fileWriter := c.newFileWriter()
-		consoleWriter := NewColorWriter(c.NoColor)
-		return NewMultiWriter(fileWriter, consoleWriter)
-
-	// 情况4: 未设置任何输出 (理论上不会走到这里, 因为 Validate 已检查)
+	// 按文件、终端、网络的顺序收集写入器
+	var writers []io.WriteCloser
+	if c.OutputFile {
+		writers = append(writers, c.newFileWriter(c.LogPath))
+	}
+	if c.OutputConsole {
+		cw := NewColorWriter(c.NoColor)
+		cw.Scope = c.ColorScope
+		cw.Theme = c.ColorTheme
+		writers = append(writers, cw)
+	}
+	if c.Net != nil {
+		// 配置已由 Validate 检查, 创建不会失败
+		if w, err := NewNetWriter(*c.Net); err == nil {
+			writers = append(writers, w)
+		}
+	}
+
+	switch len(writers) {
+	// 仅独立输出、路由规则、按键分发、syslog 或 HTTP 输出, 主写入器丢弃数据, 由对应输出或钩子发送
+	case 0:
+		if len(c.Outputs) > 0 || len(c.Routes) > 0 || c.FanOut != nil || c.Syslog != nil || c.HTTP != nil {
+			return &ConsoleWriter{w: io.Discard}
+		}
+		return nil // 未设置任何输出 (理论上不会走到这里, 因为 Validate 已检查)
+
+	// 单一输出
+	case 1:
+		return writers[0]
+
+	// 多路输出
 	default:
-		return nil
+		return NewMultiWriter(writers...)
 	}
 }
 
-// newFileWriter 创建文件写入器 (内部辅助方法)
-func (c *Config) newFileWriter() io.WriteCloser {
+// newFileWriter 按轮转和缓冲配置创建文件写入器 (内部辅助方法)
+//
+// 参数:
+//   - path: 日志文件路径
+func (c *Config) newFileWriter(path string) io.WriteCloser {
 	// 创建日志切割器 (核心写入器)
 	logger := &logrotatex.LogRotateX{
-		LogFilePath:   c.LogPath,       // 日志文件路径
+		LogFilePath:   path,            // 日志文件路径
 		MaxSize:       c.MaxSize,       // 最大日志文件大小, 单位为MB
 		MaxAge:        c.MaxAge,        // 最大日志文件保留天数
 		MaxFiles:      c.MaxFiles,      // 最大日志文件保留数量
@@ -421,10 +531,48 @@ func (c *Config) newFileWriter() io.WriteCloser {
 //   - error: 验证通过时返回 nil, 否则返回错误信息
 func (c *Config) Validate() error {
 	// 如果未设置输出, 返回错误
-	if !c.OutputFile && !c.OutputConsole {
+	if !c.OutputFile && !c.OutputConsole && len(c.Outputs) == 0 && len(c.Routes) == 0 && c.FanOut == nil && c.Net == nil && c.Syslog == nil && c.HTTP == nil {
 		return errors.New("output must be set")
 	}
 
+	// 验证独立输出配置
+	for i := range c.Outputs {
+		if err := c.Outputs[i].validate(i); err != nil {
+			return err
+		}
+		if c.OutputFile && c.Outputs[i].Path == c.LogPath {
+			return fmt.Errorf("output %d: path %s conflicts with log path", i, c.LogPath)
+		}
+	}
+
+	// 验证网络输出配置
+	if c.Net != nil {
+		if err := c.Net.validate(); err != nil {
+			return err
+		}
+	}
+
+	// 验证 syslog 配置
+	if c.Syslog != nil {
+		if err := c.Syslog.validate(); err != nil {
+			return err
+		}
+	}
+
+	// 验证 HTTP 批量输出配置
+	if c.HTTP != nil {
+		if err := c.HTTP.validate(); err != nil {
+			return err
+		}
+	}
+
+	// 验证异步日志配置
+	if c.AsyncLog != nil {
+		if err := c.AsyncLog.validate(); err != nil {
+			return err
+		}
+	}
+
 	// 验证采样器配置
 	if c.SamplerTick > 0 {
 		// 如果启用了采样, SamplerInitial 必须 >= 0 (零值表示不放行)
@@ -466,13 +614,41 @@ func (c *Config) Validate() error {
 		// 检查路径冲突: LogPath 不能与任何级别文件冲突
 		dir := filepath.Dir(c.LogPath)
 		for _, lvl := range AllLevels() {
-			lvlPath := filepath.Join(dir, lvl.String()+".log")
+			lvlPath := filepath.Join(dir, levelFileName(lvl))
 			if lvlPath == c.LogPath {
 				return fmt.Errorf("log path %s conflicts with level file path", c.LogPath)
 			}
 		}
 	}
 
+	// 验证路由规则: 规则文件不能与 LogPath、独立输出或其他规则的文件相同
+	for i := range c.Routes {
+		if err := c.Routes[i].validate(i); err != nil {
+			return err
+		}
+	}
+	// 验证按键分发配置
+	if c.FanOut != nil {
+		if err := c.FanOut.validate(); err != nil {
+			return err
+		}
+	}
+
+	paths := make(map[string]bool)
+	for _, o := range c.Outputs {
+		paths[filepath.Clean(o.Path)] = o.Path != ""
+	}
+	if c.OutputFile {
+		paths[filepath.Clean(c.LogPath)] = true
+	}
+	for _, r := range c.routes() {
+		p := filepath.Clean(r.Path)
+		if paths[p] {
+			return fmt.Errorf("route path %s conflicts with another output", r.Path)
+		}
+		paths[p] = true
+	}
+
 	// 验证缓冲写入配置
 	if c.MaxBufferSize < 0 {
 		return errors.New("max buffer size must be >= 0")
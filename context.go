@@ -0,0 +1,233 @@
+package fastlog
+
+import (
+	"context"
+	"os"
+)
+
+// ContextExtractor 上下文字段提取器
+//
+// 从 context.Context 中提取请求级数据 (如 trace_id、user_id) 并转换为字段。
+// 通过 Config.ContextExtractors 注册, 在 *Ctx 方法和 Logger.Ctx 中调用。
+// 没有可提取的数据时应返回 nil。
+//
+// 示例:
+//
+//	cfg.ContextExtractors = []fastlog.ContextExtractor{
+//	    func(ctx context.Context) []fastlog.Field {
+//	        if uid, ok := ctx.Value(userIDKey{}).(string); ok {
+//	            return []fastlog.Field{fastlog.String("user_id", uid)}
+//	        }
+//	        return nil
+//	    },
+//	}
+type ContextExtractor func(ctx context.Context) []Field
+
+// loggerCtxKey 上下文中存放 *Logger 的键
+type loggerCtxKey struct{}
+
+// fieldsCtxKey 上下文中存放字段的键
+type fieldsCtxKey struct{}
+
+// NewContext 返回携带日志记录器的新上下文
+//
+// 参数:
+//   - ctx: 父上下文
+//   - l: 日志记录器
+//
+// 返回:
+//   - context.Context: 携带日志记录器的上下文
+func NewContext(ctx context.Context, l *Logger) context.Context {
+	return context.WithValue(ctx, loggerCtxKey{}, l)
+}
+
+// FromContext 从上下文中取出日志记录器
+//
+// 上下文中没有日志记录器时返回全局默认日志记录器 L()。
+//
+// 参数:
+//   - ctx: 上下文
+//
+// 返回:
+//   - *Logger: 日志记录器, 不会为 nil
+func FromContext(ctx context.Context) *Logger {
+	if ctx != nil {
+		if l, ok := ctx.Value(loggerCtxKey{}).(*Logger); ok && l != nil {
+			return l
+		}
+	}
+	return L()
+}
+
+// ContextWithFields 返回携带字段的新上下文
+//
+// 字段会追加到上下文中已有的字段之后, 不修改父上下文。
+// 通过 *Ctx 方法或 Logger.Ctx 记录日志时, 这些字段会自动加入日志条目。
+//
+// 参数:
+//   - ctx: 父上下文
+//   - fields: 要携带的字段
+//
+// 返回:
+//   - context.Context: 携带字段的上下文
+//
+// 示例:
+//
+//	ctx = fastlog.ContextWithFields(ctx, fastlog.String("request_id", id))
+//	logger.InfoCtx(ctx, "开始处理") // 输出: ... 开始处理 request_id=xxx
+func ContextWithFields(ctx context.Context, fields ...Field) context.Context {
+	if len(fields) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, fieldsCtxKey{}, mergeFields(FieldsFromContext(ctx), fields))
+}
+
+// FieldsFromContext 返回上下文中通过 ContextWithFields 存放的字段
+//
+// 参数:
+//   - ctx: 上下文
+//
+// 返回:
+//   - []Field: 字段列表, 只读, 没有时返回 nil
+func FieldsFromContext(ctx context.Context) []Field {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(fieldsCtxKey{}).([]Field)
+	return fields
+}
+
+// contextFields 收集上下文字段并拼接调用字段（内部方法）
+//
+// 顺序: 上下文中存放的字段 → 链路字段 (trace_id、span_id) → 提取器字段 → 调用字段。
+// 上下文中没有任何字段时直接返回调用字段, 无额外分配。
+//
+// 参数:
+//   - ctx: 上下文
+//   - fields: 调用字段
+//
+// 返回:
+//   - []Field: 合并后的字段
+func (l *Logger) contextFields(ctx context.Context, fields []Field) []Field {
+	if ctx == nil {
+		return fields
+	}
+
+	var merged []Field
+	if stored := FieldsFromContext(ctx); len(stored) > 0 {
+		merged = append(merged, stored...)
+	}
+	merged = append(merged, traceFields(ctx)...)
+	for _, extract := range l.config.ContextExtractors {
+		merged = append(merged, extract(ctx)...)
+	}
+	if len(merged) == 0 {
+		return fields
+	}
+	return append(merged, fields...)
+}
+
+// Ctx 返回携带上下文字段的子日志记录器
+//
+// 在创建时执行一次字段提取, 适合在同一请求中多次记录日志。
+// 上下文中没有任何字段时返回自身。
+//
+// 参数:
+//   - ctx: 上下文
+//
+// 返回:
+//   - *Logger: 子日志记录器
+//
+// 示例:
+//
+//	log := logger.Ctx(r.Context())
+//	log.Info("开始处理")
+//	log.Infow("处理完成", fastlog.Int("status", 200))
+func (l *Logger) Ctx(ctx context.Context) *Logger {
+	return l.With(l.contextFields(ctx, nil)...)
+}
+
+// DebugCtx 记录带上下文字段的调试日志
+//
+// 参数:
+//   - ctx: 上下文
+//   - msg: 日志消息
+//   - fields: 日志字段
+func (l *Logger) DebugCtx(ctx context.Context, msg string, fields ...Field) {
+	if l.Enabled(DEBUG) {
+		l.log(DEBUG, msg, l.contextFields(ctx, fields))
+	}
+}
+
+// InfoCtx 记录带上下文字段的信息日志
+//
+// 参数:
+//   - ctx: 上下文
+//   - msg: 日志消息
+//   - fields: 日志字段
+func (l *Logger) InfoCtx(ctx context.Context, msg string, fields ...Field) {
+	if l.Enabled(INFO) {
+		l.log(INFO, msg, l.contextFields(ctx, fields))
+	}
+}
+
+// WarnCtx 记录带上下文字段的警告日志
+//
+// 参数:
+//   - ctx: 上下文
+//   - msg: 日志消息
+//   - fields: 日志字段
+func (l *Logger) WarnCtx(ctx context.Context, msg string, fields ...Field) {
+	if l.Enabled(WARN) {
+		l.log(WARN, msg, l.contextFields(ctx, fields))
+	}
+}
+
+// ErrorCtx 记录带上下文字段的错误日志
+//
+// 参数:
+//   - ctx: 上下文
+//   - msg: 日志消息
+//   - fields: 日志字段
+func (l *Logger) ErrorCtx(ctx context.Context, msg string, fields ...Field) {
+	if l.Enabled(ERROR) {
+		l.log(ERROR, msg, l.contextFields(ctx, fields))
+	}
+}
+
+// FatalCtx 记录带上下文字段的致命日志并退出程序
+//
+// 参数:
+//   - ctx: 上下文
+//   - msg: 日志消息
+//   - fields: 日志字段
+func (l *Logger) FatalCtx(ctx context.Context, msg string, fields ...Field) {
+	l.log(FATAL, msg, l.contextFields(ctx, fields))
+	_ = l.Sync()
+	os.Exit(1)
+}
+
+// PanicCtx 记录带上下文字段的恐慌日志并触发 panic
+//
+// 参数:
+//   - ctx: 上下文
+//   - msg: 日志消息
+//   - fields: 日志字段
+func (l *Logger) PanicCtx(ctx context.Context, msg string, fields ...Field) {
+	l.log(PANIC, msg, l.contextFields(ctx, fields))
+	_ = l.Sync()
+	panic(msg)
+}
+
+// LogCtx 记录指定级别的带上下文字段的日志
+//
+// 参数:
+//   - ctx: 上下文
+//   - level: 日志级别
+//   - msg: 日志消息
+//   - fields: 日志字段
+func (l *Logger) LogCtx(ctx context.Context, level Level, msg string, fields ...Field) {
+	if l.Enabled(level) {
+		l.log(level, msg, l.contextFields(ctx, fields))
+	}
+}
@@ -0,0 +1,133 @@
+package fastlog
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+type testUserKey struct{}
+
+func TestContextLogger(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		l := New(&Config{Level: INFO, OutputConsole: true})
+		ctx := NewContext(context.Background(), l)
+		if got := FromContext(ctx); got != l {
+			t.Errorf("FromContext() should return stored logger")
+		}
+	})
+
+	t.Run("fallback to global", func(t *testing.T) {
+		if got := FromContext(context.Background()); got != L() {
+			t.Errorf("FromContext() without logger should return L()")
+		}
+	})
+}
+
+func TestContextWithFields(t *testing.T) {
+	parent := ContextWithFields(context.Background(), String("a", "1"))
+	child := ContextWithFields(parent, String("b", "2"))
+
+	if got := len(FieldsFromContext(parent)); got != 1 {
+		t.Errorf("parent fields len = %d, want 1", got)
+	}
+	fields := FieldsFromContext(child)
+	if len(fields) != 2 || fields[0].Key() != "a" || fields[1].Key() != "b" {
+		t.Errorf("child fields = %v, want [a b]", fields)
+	}
+	if ContextWithFields(parent) != parent {
+		t.Errorf("ContextWithFields() without fields should return ctx unchanged")
+	}
+}
+
+func TestLoggerCtxMethods(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := New(&Config{
+		Level:         DEBUG,
+		OutputConsole: true,
+		Formatter:     &testFormatter{buf: buf},
+		Fields:        []Field{String("app", "fastlog")},
+		ContextExtractors: []ContextExtractor{
+			func(ctx context.Context) []Field {
+				if uid, ok := ctx.Value(testUserKey{}).(string); ok {
+					return []Field{String("user_id", uid)}
+				}
+				return nil
+			},
+		},
+	})
+
+	ctx := ContextWithFields(context.Background(), String("request_id", "r-1"))
+	ctx = context.WithValue(ctx, testUserKey{}, "u-9")
+
+	l.InfoCtx(ctx, "handled", String("path", "/api"))
+	want := "INFO handled app=fastlog request_id=r-1 user_id=u-9 path=/api\n"
+	if got := buf.String(); got != want {
+		t.Errorf("InfoCtx output = %q, want %q", got, want)
+	}
+
+	buf.Reset()
+	l.DebugCtx(ctx, "d")
+	l.WarnCtx(ctx, "w")
+	l.ErrorCtx(ctx, "e")
+	for _, lvl := range []string{"DEBUG d", "WARN w", "ERROR e"} {
+		if !strings.Contains(buf.String(), lvl+" app=fastlog request_id=r-1 user_id=u-9") {
+			t.Errorf("output should contain %q with context fields, got: %q", lvl, buf.String())
+		}
+	}
+
+	buf.Reset()
+	l.InfoCtx(context.Background(), "plain")
+	if got := buf.String(); got != "INFO plain app=fastlog\n" {
+		t.Errorf("InfoCtx without context fields = %q", got)
+	}
+}
+
+func TestLoggerCtxDisabledSkipsExtractors(t *testing.T) {
+	called := false
+	l := New(&Config{
+		Level:         WARN,
+		OutputConsole: true,
+		ContextExtractors: []ContextExtractor{
+			func(ctx context.Context) []Field {
+				called = true
+				return nil
+			},
+		},
+	})
+
+	l.InfoCtx(context.Background(), "suppressed")
+	if called {
+		t.Errorf("extractors should not run when level is disabled")
+	}
+}
+
+func TestLoggerCtx(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := New(&Config{Level: INFO, OutputConsole: true, Formatter: &testFormatter{buf: buf}})
+
+	if l.Ctx(context.Background()) != l {
+		t.Errorf("Ctx() without context fields should return the same logger")
+	}
+
+	ctx := ContextWithFields(context.Background(), String("trace_id", "t-1"))
+	log := l.Ctx(ctx)
+	log.Infow("one", Int("n", 1))
+	log.Info("two")
+
+	want := "INFO one trace_id=t-1 n=1\nINFO two trace_id=t-1\n"
+	if got := buf.String(); got != want {
+		t.Errorf("Ctx() logger output = %q, want %q", got, want)
+	}
+}
+
+func TestLoggerCtxCaller(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := New(&Config{Level: INFO, OutputConsole: true, Formatter: &testFormatter{buf: buf}, Caller: true})
+
+	l.InfoCtx(context.Background(), "with caller")
+	if !strings.Contains(buf.String(), "context_test.go:TestLoggerCtxCaller") {
+		t.Errorf("InfoCtx should report user caller, got: %q", buf.String())
+	}
+}
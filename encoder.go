@@ -0,0 +1,621 @@
+package fastlog
+
+import (
+	"math"
+	"strconv"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/goccy/go-json"
+)
+
+// hexDigits 十六进制字符表, 用于 JSON 控制字符转义
+const hexDigits = "0123456789abcdef"
+
+// jsonEncoder JSON 编码器, 直接向字节切片追加, 实现 ObjectEncoder 和 ArrayEncoder
+//
+// 逗号分隔由前一个字节自动推断: 紧跟 '{'、'[' 或键后的 ':' 时不加逗号。
+type jsonEncoder struct {
+	buf []byte // 输出缓冲区
+	tf  string // 时间格式
+}
+
+// jsonEncoderPool JSON 编码器池
+//
+// 编码器会以接口形式传给 ObjectMarshaler, 必然逃逸到堆上, 因此池化复用。
+var jsonEncoderPool = sync.Pool{
+	New: func() interface{} {
+		return &jsonEncoder{}
+	},
+}
+
+// getJSONEncoder 从池中获取 JSON 编码器
+//
+// 参数:
+//   - buf: 输出缓冲区, 编码结果追加到其后
+//   - tf: 时间格式
+//
+// 返回:
+//   - *jsonEncoder: 编码器实例
+func getJSONEncoder(buf []byte, tf string) *jsonEncoder {
+	e := jsonEncoderPool.Get().(*jsonEncoder)
+	e.buf = buf
+	e.tf = tf
+	return e
+}
+
+// putJSONEncoder 将 JSON 编码器放回池中, 并返回其输出缓冲区
+//
+// 参数:
+//   - e: 编码器实例
+//
+// 返回:
+//   - []byte: 编码结果
+func putJSONEncoder(e *jsonEncoder) []byte {
+	buf := e.buf
+	e.buf = nil
+	e.tf = ""
+	jsonEncoderPool.Put(e)
+	return buf
+}
+
+// sep 在键或数组元素前追加逗号 (如果需要)
+func (e *jsonEncoder) sep() {
+	if n := len(e.buf); n > 0 {
+		switch e.buf[n-1] {
+		case '{', '[', ':':
+		default:
+			e.buf = append(e.buf, ',')
+		}
+	}
+}
+
+// addKey 追加 "key":
+func (e *jsonEncoder) addKey(key string) {
+	e.sep()
+	e.buf = appendJSONString(e.buf, key)
+	e.buf = append(e.buf, ':')
+}
+
+// fillEmpty 编码函数未写入任何值时补 null, 保证输出为合法 JSON
+func (e *jsonEncoder) fillEmpty() {
+	if n := len(e.buf); n > 0 && e.buf[n-1] == ':' {
+		e.buf = append(e.buf, "null"...)
+	}
+}
+
+// AddString 实现 ObjectEncoder
+func (e *jsonEncoder) AddString(key, val string) {
+	e.addKey(key)
+	e.buf = appendJSONString(e.buf, val)
+}
+
+// AddInt 实现 ObjectEncoder
+func (e *jsonEncoder) AddInt(key string, val int) {
+	e.AddInt64(key, int64(val))
+}
+
+// AddInt64 实现 ObjectEncoder
+func (e *jsonEncoder) AddInt64(key string, val int64) {
+	e.addKey(key)
+	e.buf = strconv.AppendInt(e.buf, val, 10)
+}
+
+// AddUint64 实现 ObjectEncoder
+func (e *jsonEncoder) AddUint64(key string, val uint64) {
+	e.addKey(key)
+	e.buf = strconv.AppendUint(e.buf, val, 10)
+}
+
+// AddFloat64 实现 ObjectEncoder
+func (e *jsonEncoder) AddFloat64(key string, val float64) {
+	e.addKey(key)
+	e.buf = appendJSONFloat(e.buf, val)
+}
+
+// AddBool 实现 ObjectEncoder
+func (e *jsonEncoder) AddBool(key string, val bool) {
+	e.addKey(key)
+	e.buf = strconv.AppendBool(e.buf, val)
+}
+
+// AddTime 实现 ObjectEncoder
+func (e *jsonEncoder) AddTime(key string, val time.Time) {
+	e.addKey(key)
+	e.appendTime(val)
+}
+
+// AddDuration 实现 ObjectEncoder
+func (e *jsonEncoder) AddDuration(key string, val time.Duration) {
+	e.addKey(key)
+	e.buf = appendJSONString(e.buf, val.String())
+}
+
+// AddObject 实现 ObjectEncoder
+func (e *jsonEncoder) AddObject(key string, obj ObjectMarshaler) error {
+	e.addKey(key)
+	return e.appendObject(obj)
+}
+
+// AddArray 实现 ObjectEncoder
+func (e *jsonEncoder) AddArray(key string, arr ArrayMarshaler) error {
+	e.addKey(key)
+	return e.appendArray(arr)
+}
+
+// AddAny 实现 ObjectEncoder
+func (e *jsonEncoder) AddAny(key string, val interface{}) {
+	e.addKey(key)
+	e.appendAny(val)
+}
+
+// AppendString 实现 ArrayEncoder
+func (e *jsonEncoder) AppendString(val string) {
+	e.sep()
+	e.buf = appendJSONString(e.buf, val)
+}
+
+// AppendInt 实现 ArrayEncoder
+func (e *jsonEncoder) AppendInt(val int) {
+	e.AppendInt64(int64(val))
+}
+
+// AppendInt64 实现 ArrayEncoder
+func (e *jsonEncoder) AppendInt64(val int64) {
+	e.sep()
+	e.buf = strconv.AppendInt(e.buf, val, 10)
+}
+
+// AppendUint64 实现 ArrayEncoder
+func (e *jsonEncoder) AppendUint64(val uint64) {
+	e.sep()
+	e.buf = strconv.AppendUint(e.buf, val, 10)
+}
+
+// AppendFloat64 实现 ArrayEncoder
+func (e *jsonEncoder) AppendFloat64(val float64) {
+	e.sep()
+	e.buf = appendJSONFloat(e.buf, val)
+}
+
+// AppendBool 实现 ArrayEncoder
+func (e *jsonEncoder) AppendBool(val bool) {
+	e.sep()
+	e.buf = strconv.AppendBool(e.buf, val)
+}
+
+// AppendTime 实现 ArrayEncoder
+func (e *jsonEncoder) AppendTime(val time.Time) {
+	e.sep()
+	e.appendTime(val)
+}
+
+// AppendDuration 实现 ArrayEncoder
+func (e *jsonEncoder) AppendDuration(val time.Duration) {
+	e.sep()
+	e.buf = appendJSONString(e.buf, val.String())
+}
+
+// AppendObject 实现 ArrayEncoder
+func (e *jsonEncoder) AppendObject(obj ObjectMarshaler) error {
+	e.sep()
+	return e.appendObject(obj)
+}
+
+// AppendArray 实现 ArrayEncoder
+func (e *jsonEncoder) AppendArray(arr ArrayMarshaler) error {
+	e.sep()
+	return e.appendArray(arr)
+}
+
+// appendTime 追加带引号的时间字符串
+func (e *jsonEncoder) appendTime(t time.Time) {
+	e.buf = append(e.buf, '"')
+	e.buf = t.AppendFormat(e.buf, e.tf)
+	e.buf = append(e.buf, '"')
+}
+
+// AppendTimeLayout 按指定格式追加时间, 供 LayoutTimeEncoder 避免中间字符串
+func (e *jsonEncoder) AppendTimeLayout(t time.Time, layout string) {
+	e.sep()
+	e.buf = append(e.buf, '"')
+	e.buf = t.AppendFormat(e.buf, layout)
+	e.buf = append(e.buf, '"')
+}
+
+// appendObject 追加嵌套对象, 序列化失败时回退为错误信息字符串
+func (e *jsonEncoder) appendObject(obj ObjectMarshaler) error {
+	mark := len(e.buf)
+	e.buf = append(e.buf, '{')
+	err := obj.MarshalLogObject(e)
+	if err != nil {
+		e.buf = appendJSONString(e.buf[:mark], err.Error())
+		return err
+	}
+	e.buf = append(e.buf, '}')
+	return nil
+}
+
+// appendArray 追加嵌套数组, 序列化失败时回退为错误信息字符串
+func (e *jsonEncoder) appendArray(arr ArrayMarshaler) error {
+	mark := len(e.buf)
+	e.buf = append(e.buf, '[')
+	err := arr.MarshalLogArray(e)
+	if err != nil {
+		e.buf = appendJSONString(e.buf[:mark], err.Error())
+		return err
+	}
+	e.buf = append(e.buf, ']')
+	return nil
+}
+
+// appendAny 使用反射序列化任意值, 失败时回退为错误信息字符串
+func (e *jsonEncoder) appendAny(val interface{}) {
+	b, err := json.Marshal(val)
+	if err != nil {
+		e.buf = appendJSONString(e.buf, err.Error())
+		return
+	}
+	e.buf = append(e.buf, b...)
+}
+
+// textEncoder 文本编码器, 实现 ObjectEncoder 和 ArrayEncoder
+//
+// 对象输出为 {k=v k2=v2}, 数组输出为 [a b c], 元素间以空格分隔。
+type textEncoder struct {
+	buf   []byte // 输出缓冲区
+	tf    string // 时间格式
+	keyed bool   // 刚由 addEncodedKey 写入 key=, 下一个值无需分隔符
+}
+
+// sep 在键或数组元素前追加空格 (如果需要)
+func (e *textEncoder) sep() {
+	if e.keyed {
+		e.keyed = false
+		return
+	}
+	if n := len(e.buf); n > 0 && e.buf[n-1] != '{' && e.buf[n-1] != '[' {
+		e.buf = append(e.buf, ' ')
+	}
+}
+
+// addKey 追加 key=
+func (e *textEncoder) addKey(key string) {
+	e.sep()
+	e.buf = append(e.buf, key...)
+	e.buf = append(e.buf, '=')
+}
+
+// addEncodedKey 追加 key=, 随后的值由 LevelEncoder 等通过 Append* 方法写入
+func (e *textEncoder) addEncodedKey(key string) {
+	e.addKey(key)
+	e.keyed = true
+}
+
+// AddString 实现 ObjectEncoder
+func (e *textEncoder) AddString(key, val string) {
+	e.addKey(key)
+	e.buf = append(e.buf, val...)
+}
+
+// AddInt 实现 ObjectEncoder
+func (e *textEncoder) AddInt(key string, val int) {
+	e.AddInt64(key, int64(val))
+}
+
+// AddInt64 实现 ObjectEncoder
+func (e *textEncoder) AddInt64(key string, val int64) {
+	e.addKey(key)
+	e.buf = strconv.AppendInt(e.buf, val, 10)
+}
+
+// AddUint64 实现 ObjectEncoder
+func (e *textEncoder) AddUint64(key string, val uint64) {
+	e.addKey(key)
+	e.buf = strconv.AppendUint(e.buf, val, 10)
+}
+
+// AddFloat64 实现 ObjectEncoder
+func (e *textEncoder) AddFloat64(key string, val float64) {
+	e.addKey(key)
+	e.buf = strconv.AppendFloat(e.buf, val, 'g', -1, 64)
+}
+
+// AddBool 实现 ObjectEncoder
+func (e *textEncoder) AddBool(key string, val bool) {
+	e.addKey(key)
+	e.buf = strconv.AppendBool(e.buf, val)
+}
+
+// AddTime 实现 ObjectEncoder
+func (e *textEncoder) AddTime(key string, val time.Time) {
+	e.addKey(key)
+	e.buf = val.AppendFormat(e.buf, e.tf)
+}
+
+// AddDuration 实现 ObjectEncoder
+func (e *textEncoder) AddDuration(key string, val time.Duration) {
+	e.addKey(key)
+	e.buf = append(e.buf, val.String()...)
+}
+
+// AddObject 实现 ObjectEncoder
+func (e *textEncoder) AddObject(key string, obj ObjectMarshaler) error {
+	e.addKey(key)
+	return e.appendObject(obj)
+}
+
+// AddArray 实现 ObjectEncoder
+func (e *textEncoder) AddArray(key string, arr ArrayMarshaler) error {
+	e.addKey(key)
+	return e.appendArray(arr)
+}
+
+// AddAny 实现 ObjectEncoder
+func (e *textEncoder) AddAny(key string, val interface{}) {
+	e.addKey(key)
+	e.buf = append(e.buf, Any("", val).Value()...)
+}
+
+// AppendString 实现 ArrayEncoder
+func (e *textEncoder) AppendString(val string) {
+	e.sep()
+	e.buf = append(e.buf, val...)
+}
+
+// AppendInt 实现 ArrayEncoder
+func (e *textEncoder) AppendInt(val int) {
+	e.AppendInt64(int64(val))
+}
+
+// AppendInt64 实现 ArrayEncoder
+func (e *textEncoder) AppendInt64(val int64) {
+	e.sep()
+	e.buf = strconv.AppendInt(e.buf, val, 10)
+}
+
+// AppendUint64 实现 ArrayEncoder
+func (e *textEncoder) AppendUint64(val uint64) {
+	e.sep()
+	e.buf = strconv.AppendUint(e.buf, val, 10)
+}
+
+// AppendFloat64 实现 ArrayEncoder
+func (e *textEncoder) AppendFloat64(val float64) {
+	e.sep()
+	e.buf = strconv.AppendFloat(e.buf, val, 'g', -1, 64)
+}
+
+// AppendBool 实现 ArrayEncoder
+func (e *textEncoder) AppendBool(val bool) {
+	e.sep()
+	e.buf = strconv.AppendBool(e.buf, val)
+}
+
+// AppendTime 实现 ArrayEncoder
+func (e *textEncoder) AppendTime(val time.Time) {
+	e.sep()
+	e.buf = val.AppendFormat(e.buf, e.tf)
+}
+
+// AppendTimeLayout 按指定格式追加时间, 供 LayoutTimeEncoder 避免中间字符串
+func (e *textEncoder) AppendTimeLayout(t time.Time, layout string) {
+	e.sep()
+	e.buf = t.AppendFormat(e.buf, layout)
+}
+
+// AppendDuration 实现 ArrayEncoder
+func (e *textEncoder) AppendDuration(val time.Duration) {
+	e.sep()
+	e.buf = append(e.buf, val.String()...)
+}
+
+// AppendObject 实现 ArrayEncoder
+func (e *textEncoder) AppendObject(obj ObjectMarshaler) error {
+	e.sep()
+	return e.appendObject(obj)
+}
+
+// AppendArray 实现 ArrayEncoder
+func (e *textEncoder) AppendArray(arr ArrayMarshaler) error {
+	e.sep()
+	return e.appendArray(arr)
+}
+
+// appendObject 追加嵌套对象, 序列化失败时回退为错误信息
+func (e *textEncoder) appendObject(obj ObjectMarshaler) error {
+	mark := len(e.buf)
+	e.buf = append(e.buf, '{')
+	err := obj.MarshalLogObject(e)
+	if err != nil {
+		e.buf = append(e.buf[:mark], err.Error()...)
+		return err
+	}
+	e.buf = append(e.buf, '}')
+	return nil
+}
+
+// appendArray 追加嵌套数组, 序列化失败时回退为错误信息
+func (e *textEncoder) appendArray(arr ArrayMarshaler) error {
+	mark := len(e.buf)
+	e.buf = append(e.buf, '[')
+	err := arr.MarshalLogArray(e)
+	if err != nil {
+		e.buf = append(e.buf[:mark], err.Error()...)
+		return err
+	}
+	e.buf = append(e.buf, ']')
+	return nil
+}
+
+// appendJSONString 追加带引号并转义的 JSON 字符串
+//
+// 转义双引号、反斜杠和全部控制字符, 非法 UTF-8 替换为 �。
+//
+// 参数:
+//   - dst: 目标缓冲区
+//   - s: 原始字符串
+//
+// 返回:
+//   - []byte: 追加后的缓冲区
+func appendJSONString(dst []byte, s string) []byte {
+	dst = append(dst, '"')
+	start := 0 // 尚未写入的安全片段起点
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' {
+				i++
+				continue
+			}
+			dst = append(dst, s[start:i]...)
+			switch c {
+			case '"', '\\':
+				dst = append(dst, '\\', c)
+			case '\n':
+				dst = append(dst, '\\', 'n')
+			case '\r':
+				dst = append(dst, '\\', 'r')
+			case '\t':
+				dst = append(dst, '\\', 't')
+			default:
+				dst = append(dst, '\\', 'u', '0', '0', hexDigits[c>>4], hexDigits[c&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, `�`...)
+			i++
+			start = i
+			continue
+		}
+		i += size
+	}
+	dst = append(dst, s[start:]...)
+	return append(dst, '"')
+}
+
+// appendJSONFloat 追加 JSON 浮点数, NaN 和 ±Inf 输出为字符串
+//
+// 参数:
+//   - dst: 目标缓冲区
+//   - f: 浮点数
+//
+// 返回:
+//   - []byte: 追加后的缓冲区
+func appendJSONFloat(dst []byte, f float64) []byte {
+	switch {
+	case math.IsNaN(f):
+		return append(dst, `"NaN"`...)
+	case math.IsInf(f, 1):
+		return append(dst, `"+Inf"`...)
+	case math.IsInf(f, -1):
+		return append(dst, `"-Inf"`...)
+	}
+	return strconv.AppendFloat(dst, f, 'g', -1, 64)
+}
+
+// encodeText 以文本编码器渲染对象或数组字段
+//
+// 参数:
+//   - f: ObjectType 或 ArrayType 字段
+//   - tf: 时间格式
+//
+// 返回:
+//   - string: 渲染结果, 如 {name=alice age=30} 或 [1 2 3]
+func encodeText(f Field, tf string) string {
+	enc := textEncoder{tf: tf}
+	enc.appendField(f)
+	return string(enc.buf)
+}
+
+// appendField 追加对象或数组字段的值 (不含键)
+func (e *textEncoder) appendField(f Field) {
+	switch f.typ {
+	case ObjectType:
+		if obj, ok := f.iface.(ObjectMarshaler); ok {
+			_ = e.appendObject(obj)
+			return
+		}
+	case ArrayType:
+		if arr, ok := f.iface.(ArrayMarshaler); ok {
+			_ = e.appendArray(arr)
+			return
+		}
+	}
+	e.buf = append(e.buf, "null"...)
+}
+
+// encodeJSON 以 JSON 编码器渲染对象或数组字段
+//
+// 参数:
+//   - f: ObjectType 或 ArrayType 字段
+//   - tf: 时间格式
+//
+// 返回:
+//   - json.RawMessage: 已编码的 JSON 值
+func encodeJSON(f Field, tf string) json.RawMessage {
+	enc := jsonEncoder{tf: tf}
+	enc.appendValue(f)
+	return enc.buf
+}
+
+// addField 追加字段键值对
+func (e *jsonEncoder) addField(f Field) {
+	e.addKey(f.key)
+	e.appendValue(f)
+}
+
+// appendValue 按字段类型直接追加字段值 (不含键), 基础类型不经过 interface{} 装箱
+func (e *jsonEncoder) appendValue(f Field) {
+	if f.typ == LogValuerType {
+		f = f.resolve()
+	}
+	switch f.typ {
+	case StringType, ErrorType:
+		e.buf = appendJSONString(e.buf, f.stringVal)
+
+	case IntType, Int64Type:
+		e.buf = strconv.AppendInt(e.buf, f.intVal, 10)
+
+	case UintType, Uint64Type:
+		e.buf = strconv.AppendUint(e.buf, f.uintVal, 10)
+
+	case Float64Type:
+		e.buf = appendJSONFloat(e.buf, f.floatVal)
+
+	case BoolType:
+		e.buf = strconv.AppendBool(e.buf, f.boolVal)
+
+	case TimeType:
+		e.appendTime(f.timeVal)
+
+	case DurationType:
+		e.buf = appendJSONString(e.buf, f.duration.String())
+
+	case AnyType:
+		e.appendAny(f.iface)
+
+	case ObjectType:
+		if obj, ok := f.iface.(ObjectMarshaler); ok {
+			_ = e.appendObject(obj)
+			return
+		}
+		e.buf = append(e.buf, "null"...)
+
+	case ArrayType:
+		if arr, ok := f.iface.(ArrayMarshaler); ok {
+			_ = e.appendArray(arr)
+			return
+		}
+		e.buf = append(e.buf, "null"...)
+
+	default:
+		e.buf = append(e.buf, "null"...)
+	}
+}
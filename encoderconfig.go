@@ -0,0 +1,310 @@
+package fastlog
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// 默认的基础字段键名
+const (
+	DefaultTimeKey       = "time"    // 时间键名
+	DefaultLevelKey      = "level"   // 级别键名
+	DefaultMessageKey    = "message" // 消息键名
+	DefaultCallerKey     = "caller"  // 调用者键名
+	DefaultNameKey       = "logger"  // 日志记录器名称键名
+	DefaultStacktraceKey = "stack"   // 堆栈键名, 与 Stack() 字段的键名一致
+)
+
+// EncoderConfig 编码器配置, 控制 JSON、KV 和 Logfmt 格式的基础字段键名和取值编码方式
+//
+// 所有字段均可留空, 零值使用默认键名和编码方式, 与未配置时的输出完全一致。
+//
+// 示例:
+//
+//	// Loki 风格: msg 键, 小写级别, RFC3339Nano 时间
+//	cfg.Formatter = fastlog.JSON{EncoderConfig: &fastlog.EncoderConfig{
+//	    MessageKey:  "msg",
+//	    EncodeLevel: fastlog.LowercaseLevelEncoder,
+//	    EncodeTime:  fastlog.RFC3339NanoTimeEncoder,
+//	}}
+//
+//	// ELK 风格: @timestamp 键, 毫秒时间戳
+//	cfg.Formatter = fastlog.JSON{EncoderConfig: &fastlog.EncoderConfig{
+//	    TimeKey:    "@timestamp",
+//	    EncodeTime: fastlog.EpochMillisTimeEncoder,
+//	}}
+type EncoderConfig struct {
+	// ======== 键名配置 ========
+
+	// TimeKey 时间键名, 零值默认 "time"
+	TimeKey string
+
+	// LevelKey 级别键名, 零值默认 "level"
+	LevelKey string
+
+	// MessageKey 消息键名, 零值默认 "message"
+	MessageKey string
+
+	// CallerKey 调用者键名, 零值默认 "caller"
+	CallerKey string
+
+	// NameKey 日志记录器名称键名, 零值默认 "logger"
+	NameKey string
+
+	// StacktraceKey 堆栈键名, 零值默认 "stack"
+	// 键名为 "stack" 的字段 (如 Stack() 创建的字段) 输出时使用该键名
+	StacktraceKey string
+
+	// ======== 编码器配置 ========
+
+	// EncodeLevel 级别编码器, 零值默认 CapitalLevelEncoder
+	EncodeLevel LevelEncoder
+
+	// EncodeTime 时间编码器, 零值默认按 Config.TimeFormat 格式化
+	EncodeTime TimeEncoder
+
+	// EncodeCaller 调用者编码器, 零值默认 ShortCallerEncoder
+	EncodeCaller CallerEncoder
+}
+
+// LevelEncoder 级别编码器, 将级别写入编码器
+type LevelEncoder func(l Level, enc PrimitiveArrayEncoder)
+
+// TimeEncoder 时间编码器, 将时间写入编码器
+type TimeEncoder func(t time.Time, enc PrimitiveArrayEncoder)
+
+// CallerEncoder 调用者编码器, 将日志条目的调用者信息写入编码器
+type CallerEncoder func(e *Entry, enc PrimitiveArrayEncoder)
+
+// timeLayoutAppender 支持按指定格式直接追加时间的编码器 (内置编码器均实现)
+type timeLayoutAppender interface {
+	AppendTimeLayout(t time.Time, layout string)
+}
+
+// CapitalLevelEncoder 大写级别编码器, 如 INFO
+//
+// 参数:
+//   - l: 日志级别
+//   - enc: 编码器
+func CapitalLevelEncoder(l Level, enc PrimitiveArrayEncoder) {
+	enc.AppendString(l.String())
+}
+
+// LowercaseLevelEncoder 小写级别编码器, 如 info
+//
+// 参数:
+//   - l: 日志级别
+//   - enc: 编码器
+func LowercaseLevelEncoder(l Level, enc PrimitiveArrayEncoder) {
+	switch l {
+	case DEBUG:
+		enc.AppendString("debug")
+	case INFO:
+		enc.AppendString("info")
+	case WARN:
+		enc.AppendString("warn")
+	case ERROR:
+		enc.AppendString("error")
+	case FATAL:
+		enc.AppendString("fatal")
+	case PANIC:
+		enc.AppendString("panic")
+	default:
+		enc.AppendString(strings.ToLower(l.String()))
+	}
+}
+
+// ShortLevelEncoder 单字母级别编码器, 如 I, 与 Compact 格式一致
+//
+// 参数:
+//   - l: 日志级别
+//   - enc: 编码器
+func ShortLevelEncoder(l Level, enc PrimitiveArrayEncoder) {
+	name := l.String()
+	enc.AppendString(name[:1])
+}
+
+// CapitalColorLevelEncoder 带终端颜色的大写级别编码器
+//
+// 颜色与 ColorWriter 一致, 仅为级别名称着色, 适合直接输出到终端的 KV 格式。
+// 自定义级别按注册时的 Style 以 16 色输出。
+//
+// 参数:
+//   - l: 日志级别
+//   - enc: 编码器
+func CapitalColorLevelEncoder(l Level, enc PrimitiveArrayEncoder) {
+	switch l {
+	case DEBUG:
+		enc.AppendString("\x1b[36;1m" + LevelNameDebug + "\x1b[0m")
+	case INFO:
+		enc.AppendString("\x1b[34;1m" + LevelNameInfo + "\x1b[0m")
+	case WARN:
+		enc.AppendString("\x1b[33;1m" + LevelNameWarn + "\x1b[0m")
+	case ERROR:
+		enc.AppendString("\x1b[31;1m" + LevelNameError + "\x1b[0m")
+	case FATAL:
+		enc.AppendString("\x1b[31;1m" + LevelNameFatal + "\x1b[0m")
+	case PANIC:
+		enc.AppendString("\x1b[35;1m" + LevelNamePanic + "\x1b[0m")
+	default:
+		spec, ok := LookupLevel(l)
+		if !ok || spec.Style == (Style{}) {
+			enc.AppendString(l.String())
+			return
+		}
+		enc.AppendString(spec.Style.sequence(ColorDepth16) + spec.Name + colorReset)
+	}
+}
+
+// LayoutTimeEncoder 创建按指定格式输出的时间编码器
+//
+// 参数:
+//   - layout: Go 时间格式, 如 time.RFC3339
+//
+// 返回:
+//   - TimeEncoder: 时间编码器
+func LayoutTimeEncoder(layout string) TimeEncoder {
+	return func(t time.Time, enc PrimitiveArrayEncoder) {
+		if a, ok := enc.(timeLayoutAppender); ok {
+			a.AppendTimeLayout(t, layout)
+			return
+		}
+		enc.AppendString(t.Format(layout))
+	}
+}
+
+// RFC3339NanoTimeEncoder RFC3339 纳秒精度时间编码器, 如 2026-01-15T10:30:45.123456789Z
+var RFC3339NanoTimeEncoder = LayoutTimeEncoder(time.RFC3339Nano)
+
+// EpochTimeEncoder Unix 秒时间戳编码器
+//
+// 参数:
+//   - t: 时间
+//   - enc: 编码器
+func EpochTimeEncoder(t time.Time, enc PrimitiveArrayEncoder) {
+	enc.AppendInt64(t.Unix())
+}
+
+// EpochMillisTimeEncoder Unix 毫秒时间戳编码器
+//
+// 参数:
+//   - t: 时间
+//   - enc: 编码器
+func EpochMillisTimeEncoder(t time.Time, enc PrimitiveArrayEncoder) {
+	enc.AppendInt64(t.UnixMilli())
+}
+
+// EpochNanosTimeEncoder Unix 纳秒时间戳编码器
+//
+// 参数:
+//   - t: 时间
+//   - enc: 编码器
+func EpochNanosTimeEncoder(t time.Time, enc PrimitiveArrayEncoder) {
+	enc.AppendInt64(t.UnixNano())
+}
+
+// ShortCallerEncoder 短调用者编码器, 格式为 "文件名:函数名:行号"
+//
+// 参数:
+//   - e: 日志条目
+//   - enc: 编码器
+func ShortCallerEncoder(e *Entry, enc PrimitiveArrayEncoder) {
+	enc.AppendString(e.Caller)
+}
+
+// FullCallerEncoder 完整路径调用者编码器, 格式为 "完整文件路径:函数名:行号"
+//
+// 条目未携带程序计数器时 (如自定义构造的条目) 回退为短格式。
+//
+// 参数:
+//   - e: 日志条目
+//   - enc: 编码器
+func FullCallerEncoder(e *Entry, enc PrimitiveArrayEncoder) {
+	if e.CallerPC == 0 {
+		enc.AppendString(e.Caller)
+		return
+	}
+	frame, _ := runtime.CallersFrames([]uintptr{e.CallerPC}).Next()
+	fnName := frame.Function
+	if i := strings.LastIndexByte(fnName, '.'); i >= 0 {
+		fnName = fnName[i+1:]
+	}
+	enc.AppendString(fmt.Sprintf("%s:%s:%d", frame.File, fnName, frame.Line))
+}
+
+// timeKey 返回时间键名, c 为 nil 时返回默认值
+func (c *EncoderConfig) timeKey() string {
+	if c == nil || c.TimeKey == "" {
+		return DefaultTimeKey
+	}
+	return c.TimeKey
+}
+
+// levelKey 返回级别键名, c 为 nil 时返回默认值
+func (c *EncoderConfig) levelKey() string {
+	if c == nil || c.LevelKey == "" {
+		return DefaultLevelKey
+	}
+	return c.LevelKey
+}
+
+// messageKey 返回消息键名, c 为 nil 时返回默认值
+func (c *EncoderConfig) messageKey() string {
+	if c == nil || c.MessageKey == "" {
+		return DefaultMessageKey
+	}
+	return c.MessageKey
+}
+
+// callerKey 返回调用者键名, c 为 nil 时返回默认值
+func (c *EncoderConfig) callerKey() string {
+	if c == nil || c.CallerKey == "" {
+		return DefaultCallerKey
+	}
+	return c.CallerKey
+}
+
+// nameKey 返回日志记录器名称键名, c 为 nil 时返回默认值
+func (c *EncoderConfig) nameKey() string {
+	if c == nil || c.NameKey == "" {
+		return DefaultNameKey
+	}
+	return c.NameKey
+}
+
+// fieldKey 返回字段输出时使用的键名, 堆栈字段替换为 StacktraceKey
+func (c *EncoderConfig) fieldKey(key string) string {
+	if c == nil || c.StacktraceKey == "" || key != DefaultStacktraceKey {
+		return key
+	}
+	return c.StacktraceKey
+}
+
+// encodeLevel 使用配置的级别编码器写入级别
+func (c *EncoderConfig) encodeLevel(l Level, enc PrimitiveArrayEncoder) {
+	if c == nil || c.EncodeLevel == nil {
+		enc.AppendString(l.String())
+		return
+	}
+	c.EncodeLevel(l, enc)
+}
+
+// encodeTime 使用配置的时间编码器写入时间, 默认按编码器的时间格式 (Config.TimeFormat)
+func (c *EncoderConfig) encodeTime(t time.Time, enc PrimitiveArrayEncoder) {
+	if c == nil || c.EncodeTime == nil {
+		enc.AppendTime(t)
+		return
+	}
+	c.EncodeTime(t, enc)
+}
+
+// encodeCaller 使用配置的调用者编码器写入调用者信息
+func (c *EncoderConfig) encodeCaller(e *Entry, enc PrimitiveArrayEncoder) {
+	if c == nil || c.EncodeCaller == nil {
+		enc.AppendString(e.Caller)
+		return
+	}
+	c.EncodeCaller(e, enc)
+}
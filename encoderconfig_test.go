@@ -0,0 +1,149 @@
+package fastlog
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEncoderConfigKeys(t *testing.T) {
+	ec := &EncoderConfig{
+		TimeKey:       "@timestamp",
+		LevelKey:      "severity",
+		MessageKey:    "msg",
+		CallerKey:     "src",
+		NameKey:       "component",
+		StacktraceKey: "stacktrace",
+	}
+	entry := makeEntry("hello", "main.go:main:10", String("stack", "trace"), String("k", "v"))
+	entry.Logger = "app.db"
+
+	t.Run("JSON", func(t *testing.T) {
+		b, err := JSON{EncoderConfig: ec}.Format(entry)
+		if err != nil {
+			t.Fatalf("Format() error = %v", err)
+		}
+		want := `{"@timestamp":"2026-01-15 10:30:45","severity":"INFO","msg":"hello","src":"main.go:main:10","component":"app.db","stacktrace":"trace","k":"v"}` + "\n"
+		if string(b) != want {
+			t.Errorf("JSON.Format() =\n%s\nwant\n%s", b, want)
+		}
+	})
+
+	t.Run("KV", func(t *testing.T) {
+		b, _ := KV{EncoderConfig: ec}.Format(entry)
+		want := "@timestamp=2026-01-15 10:30:45 severity=INFO msg=hello src=main.go:main:10 component=app.db stacktrace=trace k=v\n"
+		if string(b) != want {
+			t.Errorf("KV.Format() = %q, want %q", b, want)
+		}
+	})
+
+	t.Run("zero value keeps defaults", func(t *testing.T) {
+		got, _ := JSON{EncoderConfig: &EncoderConfig{}}.Format(entry)
+		want, _ := JSON{}.Format(entry)
+		if string(got) != string(want) {
+			t.Errorf("empty EncoderConfig output = %s, want %s", got, want)
+		}
+	})
+}
+
+func TestLevelEncoders(t *testing.T) {
+	tests := []struct {
+		name string
+		enc  LevelEncoder
+		lvl  Level
+		want string
+	}{
+		{"capital", CapitalLevelEncoder, WARN, "WARN"},
+		{"lowercase", LowercaseLevelEncoder, ERROR, "error"},
+		{"lowercase unknown", LowercaseLevelEncoder, Level(99), "level(99)"},
+		{"short", ShortLevelEncoder, DEBUG, "D"},
+		{"color", CapitalColorLevelEncoder, INFO, "\x1b[34;1mINFO\x1b[0m"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := makeEntry("m", "")
+			entry.Level = tt.lvl
+			b, _ := KV{EncoderConfig: &EncoderConfig{EncodeLevel: tt.enc}}.Format(entry)
+			if !strings.Contains(string(b), " level="+tt.want+" ") {
+				t.Errorf("KV output = %q, want level=%s", b, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimeEncoders(t *testing.T) {
+	ts := time.Date(2026, 1, 15, 10, 30, 45, 123456789, time.UTC)
+	tests := []struct {
+		name string
+		enc  TimeEncoder
+		want interface{}
+	}{
+		{"epoch seconds", EpochTimeEncoder, float64(ts.Unix())},
+		{"epoch millis", EpochMillisTimeEncoder, float64(ts.UnixMilli())},
+		{"epoch nanos", EpochNanosTimeEncoder, float64(ts.UnixNano())},
+		{"rfc3339nano", RFC3339NanoTimeEncoder, "2026-01-15T10:30:45.123456789Z"},
+		{"layout", LayoutTimeEncoder(time.TimeOnly), "10:30:45"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := makeEntry("m", "")
+			entry.Time = ts
+			b, err := JSON{EncoderConfig: &EncoderConfig{EncodeTime: tt.enc}}.Format(entry)
+			if err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+			var m map[string]interface{}
+			if err := json.Unmarshal(b, &m); err != nil {
+				t.Fatalf("invalid JSON %s: %v", b, err)
+			}
+			if tt.name == "epoch nanos" {
+				// float64 无法精确表示纳秒时间戳, 只比较字面值
+				if !strings.Contains(string(b), `"time":1768473045123456789`) {
+					t.Errorf("JSON = %s, want nanosecond timestamp", b)
+				}
+				return
+			}
+			if m["time"] != tt.want {
+				t.Errorf("time = %v, want %v", m["time"], tt.want)
+			}
+		})
+	}
+
+	t.Run("empty encoder", func(t *testing.T) {
+		noop := func(time.Time, PrimitiveArrayEncoder) {}
+		b, _ := JSON{EncoderConfig: &EncoderConfig{EncodeTime: noop}}.Format(makeEntry("m", ""))
+		if !json.Valid(b) || !strings.Contains(string(b), `"time":null`) {
+			t.Errorf("encoder writing nothing should produce null, got %s", b)
+		}
+	})
+}
+
+func TestCallerEncoders(t *testing.T) {
+	m := newMock()
+	l := New(&Config{Level: INFO, OutputConsole: true, Caller: true, Formatter: JSON{EncoderConfig: &EncoderConfig{
+		EncodeCaller: FullCallerEncoder,
+	}}})
+	l.writer = m
+
+	l.Info("full")
+	var out map[string]interface{}
+	if err := json.Unmarshal(m.Bytes(), &out); err != nil {
+		t.Fatalf("invalid JSON %q: %v", m.String(), err)
+	}
+	caller, _ := out["caller"].(string)
+	if !filepath.IsAbs(strings.SplitN(caller, ".go:", 2)[0]+".go") || !strings.Contains(caller, "encoderconfig_test.go:TestCallerEncoders:") {
+		t.Errorf("full caller = %q, want absolute path with function", caller)
+	}
+
+	entry := makeEntry("m", "main.go:main:10")
+	b, _ := KV{EncoderConfig: &EncoderConfig{EncodeCaller: FullCallerEncoder}}.Format(entry)
+	if !strings.Contains(string(b), "caller=main.go:main:10") {
+		t.Errorf("FullCallerEncoder without PC should fall back to short caller, got %q", b)
+	}
+	b, _ = KV{EncoderConfig: &EncoderConfig{EncodeCaller: ShortCallerEncoder}}.Format(entry)
+	if !strings.Contains(string(b), "caller=main.go:main:10") {
+		t.Errorf("ShortCallerEncoder output = %q", b)
+	}
+}
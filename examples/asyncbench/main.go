@@ -13,12 +13,16 @@ import (
 )
 
 func main() {
-	fmt.Println("FastLog Async 异步轮转/压缩性能测试")
+	fmt.Println("FastLog 异步日志 / 异步轮转压缩性能测试")
 	fmt.Println(repeat("=", 55))
 
 	// 清理上次测试的日志文件
 	_ = os.RemoveAll("logs")
 
+	// 阶段零: 同步写入与异步日志 (AsyncLog) 对比
+	compareSyncAsync(200_000, 8)
+	_ = os.RemoveAll("logs")
+
 	// ============================================================
 	// 配置说明:
 	//   使用 Prod 配置 (Async: true, Compress: true) 演示高频日志
@@ -101,6 +105,51 @@ func highFrequencyWrite(logger *fastlog.Logger, total int, concurrency int) {
 	fmt.Printf("  吞吐量: %.0f 条/秒\n", throughput)
 }
 
+// compareSyncAsync 对比同步写入和异步日志的调用方耗时
+//
+// 关闭缓冲写入, 每条日志都直接写文件, 放大慢速 I/O 对调用方的影响。
+// 调用方耗时为全部协程记录完日志的时间; 总耗时额外包含 Close 等待队列清空的时间。
+func compareSyncAsync(total, concurrency int) {
+	fmt.Printf("\n🔀 同步 vs 异步: %d 并发 × %d 条\n", concurrency, total/concurrency)
+	fmt.Println(repeat("-", 55))
+
+	for _, async := range []bool{false, true} {
+		cfg := fastlog.NewConfig("logs/compare.log")
+		cfg.BufferEnabled = false // 每条日志直接写文件
+		name := "同步写入"
+		if async {
+			cfg.AsyncLog = &fastlog.AsyncConfig{QueueSize: 65536}
+			cfg.LogPath = "logs/compare-async.log"
+			name = "异步日志"
+		}
+		logger := fastlog.New(cfg)
+
+		start := time.Now()
+		var wg sync.WaitGroup
+		for g := 0; g < concurrency; g++ {
+			wg.Add(1)
+			go func(g int) {
+				defer wg.Done()
+				for i := g; i < total; i += concurrency {
+					writeLogLine(logger, i)
+				}
+			}(g)
+		}
+		wg.Wait()
+		callerElapsed := time.Since(start)
+		_ = logger.Close()
+		totalElapsed := time.Since(start)
+
+		fmt.Printf("  %s: 调用方耗时 %v (%.0f 条/秒), 含关闭总耗时 %v\n",
+			name, callerElapsed.Round(time.Millisecond),
+			float64(total)/callerElapsed.Seconds(), totalElapsed.Round(time.Millisecond))
+		if async {
+			st := logger.AsyncStats()
+			fmt.Printf("  异步统计: 已写入 %d, 丢弃 %d\n", st.Processed, st.Dropped)
+		}
+	}
+}
+
 func writeLogLine(logger *fastlog.Logger, seq int) {
 	// 轮流写入不同业务类型的日志, 模拟真实高频场景
 	switch seq % 4 {
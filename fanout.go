@@ -0,0 +1,345 @@
+package fastlog
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 按键分发输出的默认值
+const (
+	DefaultFanOutMaxOpen     = 64              // 默认同时打开的文件数上限
+	DefaultFanOutIdleTimeout = 5 * time.Minute // 默认空闲文件的关闭时间
+	fanOutKeyMaxLen          = 128             // 编码后键的最大长度
+)
+
+// FanOutConfig 按字段值分发到不同文件的输出配置, 适用于多租户等场景
+//
+// 每个键的文件在首次出现时创建, 同时打开的文件数超过 MaxOpen 时关闭最久未使用的文件,
+// 空闲超过 IdleTimeout 的文件在后台关闭, 再次出现时重新打开并追加写入。
+//
+// 示例:
+//
+//	cfg.FanOut = &fastlog.FanOutConfig{
+//	    Field:   "tenant",
+//	    Path:    "logs/tenants/{key}/app.log",
+//	    MaxOpen: 100,
+//	}
+//
+//	logger.Infow("下单", fastlog.String("tenant", "acme"))  // 写入 logs/tenants/acme/app.log
+type FanOutConfig struct {
+	// Field 决定目标文件的字段键, 命名空间内的字段使用点号拼接的完整键名
+	Field string
+
+	// Path 文件路径模板, 必须包含 {key}, 替换为编码后的字段值 (见 sanitizeKey)
+	Path string
+
+	// DefaultKey 日志不含 Field 字段或净化后为空时使用的键, 为空时不写入
+	DefaultKey string
+
+	// Level 最低级别, 零值表示不额外过滤
+	Level Level
+
+	// MaxOpen 同时打开的文件数上限, 零值默认 DefaultFanOutMaxOpen
+	MaxOpen int
+
+	// IdleTimeout 空闲文件的关闭时间, 零值默认 DefaultFanOutIdleTimeout, 负数表示不按空闲时间关闭
+	IdleTimeout time.Duration
+
+	// Rotation 轮转配置, nil 时沿用 Config 的轮转配置
+	Rotation *Rotation
+
+	// Formatter 格式化器, nil 时使用 Config.Formatter
+	Formatter Formatter
+}
+
+// validate 验证按键分发配置
+//
+// 返回:
+//   - error: 验证通过时返回 nil, 否则返回错误信息
+func (c *FanOutConfig) validate() error {
+	if c.Field == "" {
+		return errors.New("fan-out field must be set")
+	}
+	if !strings.Contains(c.Path, "{key}") {
+		return fmt.Errorf("fan-out path %q must contain {key}", c.Path)
+	}
+	if c.MaxOpen < 0 {
+		return errors.New("fan-out max open must be >= 0")
+	}
+	if _, ok := LookupLevel(c.Level); c.Level != 0 && !ok {
+		return fmt.Errorf("fan-out: unknown level %d", c.Level)
+	}
+	if r := c.Rotation; r != nil && (r.MaxSize < 0 || r.MaxFiles < 0 || r.MaxAge < 0) {
+		return errors.New("fan-out rotation settings must be >= 0")
+	}
+	return nil
+}
+
+// sanitizeKey 将字段值转换为可安全用作路径片段的键
+//
+// 小写字母、数字、'-'、'_' 和 '.' (不能开头) 原样保留, 其他字节 (含大写字母、'%' 和路径分隔符)
+// 编码为 "%xx"。编码是单射的, 不同的字段值不会得到同一个键; 结果不含大写字母,
+// 在大小写不敏感的文件系统上 "Acme" 和 "acme" 同样对应不同的文件。
+// 编码后超过 128 字节时截断, 并追加 '~' 和原值的 64 位哈希, 区分前缀相同的长值。
+//
+// 参数:
+//   - s: 字段值
+//
+// 返回:
+//   - string: 净化后的键, s 为空时返回空字符串
+func sanitizeKey(s string) string {
+	safe := len(s) <= fanOutKeyMaxLen
+	for i := 0; safe && i < len(s); i++ {
+		safe = isKeySafe(s[i], i)
+	}
+	if safe {
+		return s
+	}
+
+	b := make([]byte, 0, len(s)+16)
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; isKeySafe(c, i) {
+			b = append(b, c)
+		} else {
+			b = append(b, '%', hexDigits[c>>4], hexDigits[c&0xf])
+		}
+	}
+	if len(b) > fanOutKeyMaxLen {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(s))
+		b = append(b[:fanOutKeyMaxLen-17], '~')
+		b = fmt.Appendf(b, "%016x", h.Sum64())
+	}
+	return string(b)
+}
+
+// isKeySafe 返回字节是否可以原样保留在键中
+func isKeySafe(c byte, i int) bool {
+	switch {
+	case c >= 'a' && c <= 'z', c >= '0' && c <= '9', c == '-', c == '_':
+		return true
+	case c == '.':
+		return i > 0
+	default:
+		return false
+	}
+}
+
+// fanOutFile 已打开的键文件（内部使用）
+type fanOutFile struct {
+	key      string         // 净化后的键
+	writer   io.WriteCloser // 文件写入器
+	lastUsed time.Time      // 最近一次写入时间
+}
+
+// fanOutHook 按字段值分发到不同文件的钩子（内部使用）
+//
+// 打开的文件按最近使用顺序保存在链表中, 表头为最近使用。
+type fanOutHook struct {
+	cfg       FanOutConfig                     // 分发配置
+	newWriter func(path string) io.WriteCloser // 创建文件写入器
+	slot      int                              // 格式化器下标, 0 表示 Config.Formatter
+
+	mu     sync.Mutex               // 保护 files、lru 和 closed
+	files  map[string]*list.Element // 键 → lru 中的元素
+	lru    *list.List               // *fanOutFile, 表头为最近使用
+	closed bool                     // 是否已关闭
+
+	stop chan struct{} // 停止空闲清理协程
+	done chan struct{} // 空闲清理协程已退出
+}
+
+// newFanOutHook 根据配置创建按键分发钩子, IdleTimeout > 0 时启动空闲清理协程
+//
+// 参数:
+//   - c: 日志配置, FanOut 非 nil
+//
+// 返回:
+//   - *fanOutHook: 按键分发钩子
+func newFanOutHook(c *Config) *fanOutHook {
+	cfg := *c.FanOut
+	if cfg.MaxOpen == 0 {
+		cfg.MaxOpen = DefaultFanOutMaxOpen
+	}
+	if cfg.IdleTimeout == 0 {
+		cfg.IdleTimeout = DefaultFanOutIdleTimeout
+	}
+	h := &fanOutHook{
+		cfg: cfg,
+		newWriter: func(path string) io.WriteCloser {
+			return c.newRouteWriter(&Route{Path: path, Rotation: cfg.Rotation})
+		},
+		files: make(map[string]*list.Element),
+		lru:   list.New(),
+	}
+	if cfg.IdleTimeout > 0 {
+		h.stop = make(chan struct{})
+		h.done = make(chan struct{})
+		go h.reapIdle()
+	}
+	return h
+}
+
+// key 返回日志条目对应的键
+//
+// 返回:
+//   - string: 净化后的键
+//   - bool: 日志需要写入时返回 true
+func (h *fanOutHook) key(entry *Entry) (string, bool) {
+	if !h.cfg.Level.Enabled(entry.Level) {
+		return "", false
+	}
+	var key string
+	if f, ok := lookupField(entry.Fields, h.cfg.Field); ok {
+		key = sanitizeKey(f.Value())
+	}
+	if key == "" {
+		key = sanitizeKey(h.cfg.DefaultKey)
+	}
+	return key, key != ""
+}
+
+// Fire 执行钩子
+// 按字段值写入对应的文件, 文件未打开时创建
+// 参数:
+//   - entry: 日志条目
+//   - data: 格式化后的日志数据
+//
+// 返回:
+//   - error: 写入或关闭被淘汰文件时的错误
+func (h *fanOutHook) Fire(entry *Entry, data []byte) error {
+	key, ok := h.key(entry)
+	if !ok {
+		return nil
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return nil
+	}
+
+	var evictErr error
+	el, ok := h.files[key]
+	if ok {
+		h.lru.MoveToFront(el)
+	} else {
+		// 达到上限时关闭最久未使用的文件
+		if h.lru.Len() >= h.cfg.MaxOpen {
+			evictErr = h.closeFile(h.lru.Back())
+		}
+		f := &fanOutFile{key: key, writer: h.newWriter(strings.ReplaceAll(h.cfg.Path, "{key}", key))}
+		el = h.lru.PushFront(f)
+		h.files[key] = el
+	}
+
+	f := el.Value.(*fanOutFile)
+	f.lastUsed = time.Now()
+	_, err := writeLevel(f.writer, entry.Level, data)
+	return errors.Join(err, evictErr)
+}
+
+// closeFile 关闭并移除一个文件, 调用方持有 h.mu
+func (h *fanOutHook) closeFile(el *list.Element) error {
+	f := h.lru.Remove(el).(*fanOutFile)
+	delete(h.files, f.key)
+	return f.writer.Close()
+}
+
+// reapIdle 空闲清理协程: 定期关闭空闲超过 IdleTimeout 的文件
+func (h *fanOutHook) reapIdle() {
+	defer close(h.done)
+	ticker := time.NewTicker(max(h.cfg.IdleTimeout/2, time.Second))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			h.closeIdle(time.Now().Add(-h.cfg.IdleTimeout))
+		case <-h.stop:
+			return
+		}
+	}
+}
+
+// closeIdle 关闭最近使用时间早于 before 的文件
+func (h *fanOutHook) closeIdle(before time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	// 表尾为最久未使用, 遇到未过期的文件即可停止
+	for el := h.lru.Back(); el != nil && el.Value.(*fanOutFile).lastUsed.Before(before); el = h.lru.Back() {
+		_ = h.closeFile(el)
+	}
+}
+
+// renderSlot 返回该日志需要的格式化器下标
+// 参数:
+//   - entry: 日志条目
+//
+// 返回:
+//   - int: 格式化器下标
+//   - bool: 日志不需要写入时返回 false
+func (h *fanOutHook) renderSlot(entry *Entry) (int, bool) {
+	_, ok := h.key(entry)
+	return h.slot, ok
+}
+
+// Levels 返回关心的级别
+// 返回:
+//   - []Level: 不低于最低级别的所有级别
+func (h *fanOutHook) Levels() []Level {
+	var levels []Level
+	for _, lvl := range AllLevels() {
+		if h.cfg.Level.Enabled(lvl) {
+			levels = append(levels, lvl)
+		}
+	}
+	return levels
+}
+
+// Sync 同步所有已打开的文件
+// 返回:
+//   - error: 同步过程中的错误
+func (h *fanOutHook) Sync() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var errs []error
+	for el := h.lru.Front(); el != nil; el = el.Next() {
+		if syncer, ok := el.Value.(*fanOutFile).writer.(interface{ Sync() error }); ok {
+			if err := syncer.Sync(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close 停止空闲清理协程并关闭所有已打开的文件
+// 返回:
+//   - error: 关闭过程中的错误
+func (h *fanOutHook) Close() error {
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		return nil
+	}
+	h.closed = true
+	var errs []error
+	for el := h.lru.Back(); el != nil; el = h.lru.Back() {
+		if err := h.closeFile(el); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	h.mu.Unlock()
+
+	if h.stop != nil {
+		close(h.stop)
+		<-h.done
+	}
+	return errors.Join(errs...)
+}
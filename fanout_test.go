@@ -0,0 +1,151 @@
+package fastlog
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSanitizeKey(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"acme", "acme"},
+		{"acme-corp_1.eu", "acme-corp_1.eu"},
+		{"acme_x", "acme_x"},
+		{"acme/x", "acme%2fx"},
+		{"acme x", "acme%20x"},
+		{"acme%2fx", "acme%252fx"},
+		{"Acme", "%41cme"},
+		{"../etc/passwd", "%2e.%2fetc%2fpasswd"},
+		{"..", "%2e."},
+		{".hidden", "%2ehidden"},
+		{`a\b:c d`, "a%5cb%3ac%20d"},
+		{"租户", "%e7%a7%9f%e6%88%b7"},
+		{"", ""},
+		{strings.Repeat("x", 128), strings.Repeat("x", 128)},
+	}
+	for _, tt := range tests {
+		if got := sanitizeKey(tt.in); got != tt.want {
+			t.Errorf("sanitizeKey(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	// 超长的值截断后追加哈希, 前缀相同的值仍然得到不同的键
+	long1, long2 := sanitizeKey(strings.Repeat("x", 200)), sanitizeKey(strings.Repeat("x", 201))
+	if len(long1) != 128 || !strings.HasPrefix(long1, strings.Repeat("x", 111)+"~") || long1 == long2 {
+		t.Errorf("long keys = %q, %q", long1, long2)
+	}
+}
+
+// fanOutOf 返回日志记录器的按键分发钩子
+func fanOutOf(t *testing.T, l *Logger) *fanOutHook {
+	t.Helper()
+	for _, h := range l.hooks {
+		if fh, ok := h.(*fanOutHook); ok {
+			return fh
+		}
+	}
+	t.Fatal("fan-out hook not found")
+	return nil
+}
+
+func TestFanOut(t *testing.T) {
+	dir := t.TempDir()
+	tenantLog := func(key string) string { return filepath.Join(dir, "tenants", key, "app.log") }
+	l := New(&Config{
+		Formatter:     MustPattern("%msg"),
+		BufferEnabled: false,
+		FanOut: &FanOutConfig{
+			Field:       "tenant",
+			Path:        filepath.Join(dir, "tenants", "{key}", "app.log"),
+			MaxOpen:     2,
+			IdleTimeout: -1,
+		},
+	})
+	h := fanOutOf(t, l)
+
+	l.Infow("a1", String("tenant", "acme"))
+	l.Infow("g1", String("tenant", "globex"))
+	l.Infow("a2", String("tenant", "acme"))
+	l.Infow("i1", String("tenant", "initech")) // 关闭最久未使用的 globex
+	if _, open := h.files["globex"]; open || h.lru.Len() != 2 {
+		t.Errorf("open files = %d, globex open = %v", h.lru.Len(), open)
+	}
+	l.Infow("g2", String("tenant", "globex")) // 重新打开并追加
+	l.Info("no tenant")
+	l.Infow("evil", String("tenant", "../../x"))
+	if err := l.Sync(); err != nil {
+		t.Errorf("Sync() error = %v", err)
+	}
+	if err := l.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	if h.lru.Len() != 0 {
+		t.Errorf("Close() left %d files open", h.lru.Len())
+	}
+
+	for key, want := range map[string]string{"acme": "a1\na2\n", "globex": "g1\ng2\n", "initech": "i1\n", "%2e.%2f..%2fx": "evil\n"} {
+		if got := readLogFile(t, tenantLog(key)); got != want {
+			t.Errorf("%s log = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestFanOutIdleAndDefaultKey(t *testing.T) {
+	dir := t.TempDir()
+	l := New(&Config{
+		Formatter:     MustPattern("%level %msg"),
+		BufferEnabled: false,
+		FanOut: &FanOutConfig{
+			Field:      "req.tenant",
+			Path:       filepath.Join(dir, "{key}.log"),
+			DefaultKey: "shared",
+			Level:      WARN,
+			Formatter:  MustPattern("%msg"),
+		},
+	})
+	h := fanOutOf(t, l)
+
+	l.Warnw("w1", Namespace("req"), String("tenant", "acme"))
+	l.Infow("i1", Namespace("req"), String("tenant", "acme")) // 低于 WARN
+	l.Error("e1")
+	if h.lru.Len() != 2 {
+		t.Errorf("open files = %d, want 2", h.lru.Len())
+	}
+	h.closeIdle(time.Now().Add(time.Minute))
+	if h.lru.Len() != 0 {
+		t.Errorf("closeIdle() left %d files open", h.lru.Len())
+	}
+	_ = l.Close()
+
+	if got := readLogFile(t, filepath.Join(dir, "acme.log")); got != "w1\n" {
+		t.Errorf("acme.log = %q", got)
+	}
+	if got := readLogFile(t, filepath.Join(dir, "shared.log")); got != "e1\n" {
+		t.Errorf("shared.log = %q", got)
+	}
+}
+
+func TestFanOutConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     FanOutConfig
+		wantErr bool
+	}{
+		{"valid", FanOutConfig{Field: "tenant", Path: "logs/{key}.log"}, false},
+		{"missing field", FanOutConfig{Path: "logs/{key}.log"}, true},
+		{"missing placeholder", FanOutConfig{Field: "tenant", Path: "logs/app.log"}, true},
+		{"negative max open", FanOutConfig{Field: "tenant", Path: "{key}", MaxOpen: -1}, true},
+		{"bad rotation", FanOutConfig{Field: "tenant", Path: "{key}", Rotation: &Rotation{MaxAge: -1}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&Config{FanOut: &tt.cfg}).Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
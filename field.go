@@ -12,20 +12,48 @@ type FieldType int8
 
 // 字段类型常量
 const (
-	UnknownType  FieldType = iota // 未知类型
-	StringType                    // 字符串类型
-	IntType                       // 整数类型
-	Int64Type                     // 64位整数类型
-	UintType                      // 无符号整数类型
-	Uint64Type                    // 64位无符号整数类型
-	Float64Type                   // 浮点数类型
-	BoolType                      // 布尔类型
-	TimeType                      // 时间类型
-	DurationType                  // 时间持续类型
-	ErrorType                     // 错误类型
-	AnyType                       // any类型
+	UnknownType   FieldType = iota // 未知类型
+	StringType                     // 字符串类型
+	IntType                        // 整数类型
+	Int64Type                      // 64位整数类型
+	UintType                       // 无符号整数类型
+	Uint64Type                     // 64位无符号整数类型
+	Float64Type                    // 浮点数类型
+	BoolType                       // 布尔类型
+	TimeType                       // 时间类型
+	DurationType                   // 时间持续类型
+	ErrorType                      // 错误类型
+	AnyType                        // any类型
+	NamespaceType                  // 命名空间类型, 为后续字段添加前缀
+	LogValuerType                  // 实现 LogValuer 的类型, 输出时才解析
+	ObjectType                     // 实现 ObjectMarshaler 的对象类型
+	ArrayType                      // 实现 ArrayMarshaler 的数组类型
 )
 
+// maxLogValuerDepth LogValuer 嵌套解析的最大深度, 防止 LogValue 互相返回导致死循环
+const maxLogValuerDepth = 8
+
+// LogValuer 自定义日志输出接口
+//
+// 通过 Any 传入实现了该接口的值时, 只在日志条目真正输出时调用 LogValue,
+// 被级别或采样过滤的日志不会产生解析开销。返回字段的键会被 Any 的键覆盖。
+//
+// 示例:
+//
+//	type User struct {
+//	    Name string
+//	    Age  int
+//	}
+//
+//	func (u User) LogValue() fastlog.Field {
+//	    return fastlog.String("", u.Name+"("+strconv.Itoa(u.Age)+")")
+//	}
+//
+//	logger.Infow("登录", fastlog.Any("user", user)) // 输出: user=bob(25)
+type LogValuer interface {
+	LogValue() Field
+}
+
 // Field 表示一个键值对字段, 包含所有可能的类型
 type Field struct {
 	key       string        // 字段键
@@ -67,7 +95,9 @@ func (f Field) Type() FieldType {
 //   - TimeType: 转为 DateTime 格式时间字符串 (2006-01-02 15:04:05)
 //   - DurationType: 转为持续时间字符串 (如 "1h30m")
 //   - AnyType: 使用 fmt.Sprintf("%v") 格式化
-//   - 其他类型: 返回空字符串
+//   - LogValuerType: 调用 LogValue 解析后再格式化
+//   - ObjectType/ArrayType: 渲染为 {k=v k2=v2} 或 [a b c]
+//   - 其他类型 (含 NamespaceType): 返回空字符串
 //
 // 返回:
 //   - string: 字段值的字符串表示
@@ -97,11 +127,40 @@ func (f Field) Value() string {
 	case AnyType:
 		return f.anyString()
 
+	case LogValuerType:
+		return f.resolve().Value()
+
+	case ObjectType, ArrayType:
+		return encodeText(f, DefaultTimeFormat)
+
 	default:
 		return ""
 	}
 }
 
+// resolve 解析 LogValuerType 字段, 返回实际输出的字段
+//
+// 键保持为原字段的键; 其他类型的字段原样返回。
+// 超过 maxLogValuerDepth 层仍未解析完成时, 按 AnyType 输出原始值。
+//
+// 返回:
+//   - Field: 解析后的字段, 类型不会是 LogValuerType
+func (f Field) resolve() Field {
+	r := f
+	for i := 0; i < maxLogValuerDepth && r.typ == LogValuerType; i++ {
+		v, ok := r.iface.(LogValuer)
+		if !ok {
+			break
+		}
+		r = v.LogValue()
+	}
+	if r.typ == LogValuerType {
+		r = Field{typ: AnyType, iface: r.iface}
+	}
+	r.key = f.key
+	return r
+}
+
 // anyString 将 iface 字段值转为字符串
 //
 // 返回:
@@ -194,6 +253,12 @@ func (f Field) toInterface() interface{} {
 	case AnyType:
 		return f.iface
 
+	case LogValuerType:
+		return f.resolve().toInterface()
+
+	case ObjectType, ArrayType:
+		return encodeJSON(f, DefaultTimeFormat)
+
 	default:
 		return nil
 	}
@@ -220,8 +285,14 @@ func (f Field) Format() string {
 // 返回:
 //   - string: 字段值的字符串表示
 func (f Field) valueWithTimeFormat(tf string) string {
-	if f.typ == TimeType {
+	if f.typ == LogValuerType {
+		f = f.resolve()
+	}
+	switch f.typ {
+	case TimeType:
 		return f.timeVal.Format(tf)
+	case ObjectType, ArrayType:
+		return encodeText(f, tf)
 	}
 	return f.Value()
 }
@@ -245,8 +316,14 @@ func (f Field) formatWithTimeFormat(tf string) string {
 // 返回:
 //   - interface{}: 字段值的 interface{} 表示
 func (f Field) toInterfaceWithTimeFormat(tf string) interface{} {
-	if f.typ == TimeType {
+	if f.typ == LogValuerType {
+		f = f.resolve()
+	}
+	switch f.typ {
+	case TimeType:
 		return f.timeVal.Format(tf)
+	case ObjectType, ArrayType:
+		return encodeJSON(f, tf)
 	}
 	return f.toInterface()
 }
@@ -405,6 +482,10 @@ func Err(key string, err error) Field {
 
 // Any 创建一个任意类型字段
 //
+// 如果 val 实现了 LogValuer 接口, 字段类型为 LogValuerType,
+// LogValue 延迟到日志条目真正输出时才调用。
+// 实现了 ObjectMarshaler 或 ArrayMarshaler 时, 等同于 Object 或 Array, 不使用反射。
+//
 // 参数:
 //   - key: 字段键
 //   - val: 任意类型的值
@@ -412,5 +493,39 @@ func Err(key string, err error) Field {
 // 返回:
 //   - Field: 字段实例
 func Any(key string, val interface{}) Field {
+	switch val.(type) {
+	case LogValuer:
+		return Field{key: key, typ: LogValuerType, iface: val}
+	case ObjectMarshaler:
+		return Field{key: key, typ: ObjectType, iface: val}
+	case ArrayMarshaler:
+		return Field{key: key, typ: ArrayType, iface: val}
+	}
 	return Field{key: key, typ: AnyType, iface: val}
 }
+
+// Namespace 创建一个命名空间字段
+//
+// 之后的所有字段都归入该命名空间, 多个 Namespace 逐层嵌套:
+// JSON 格式输出为嵌套对象, 文本格式以点号连接键名, 如 "user.name"。
+// 通过 With 传入时, 对子日志记录器的后续全部字段生效。
+//
+// 参数:
+//   - key: 命名空间名称
+//
+// 返回:
+//   - Field: 命名空间标记字段
+//
+// 示例:
+//
+//	logger.Infow("请求",
+//	    fastlog.String("method", "POST"),
+//	    fastlog.Namespace("user"),
+//	    fastlog.String("name", "alice"),
+//	    fastlog.Int("age", 30),
+//	)
+//	// Def 输出: ... 请求 method=POST, user.name=alice, user.age=30
+//	// JSON 输出: {..., "method":"POST", "user":{"name":"alice","age":30}}
+func Namespace(key string) Field {
+	return Field{key: key, typ: NamespaceType}
+}
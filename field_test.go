@@ -1,7 +1,9 @@
 package fastlog
 
 import (
+	"bytes"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 )
@@ -415,3 +417,78 @@ func TestFieldEmptyKey(t *testing.T) {
 		t.Errorf("Empty key field Value = %q, want 'value'", f.Value())
 	}
 }
+
+// testLogUser 实现 LogValuer 的测试类型, 记录解析次数
+type testLogUser struct {
+	name  string
+	calls *int
+}
+
+func (u testLogUser) LogValue() Field {
+	*u.calls++
+	return String("ignored", u.name)
+}
+
+// testLoopValuer LogValue 返回自身, 用于测试解析深度保护
+type testLoopValuer struct{}
+
+func (v testLoopValuer) LogValue() Field {
+	return Any("loop", v)
+}
+
+func TestFieldNamespace(t *testing.T) {
+	f := Namespace("user")
+	if f.Key() != "user" {
+		t.Errorf("Namespace().Key() = %q, want %q", f.Key(), "user")
+	}
+	if f.Type() != NamespaceType {
+		t.Errorf("Namespace().Type() = %v, want NamespaceType", f.Type())
+	}
+	if f.Value() != "" {
+		t.Errorf("Namespace().Value() = %q, want empty", f.Value())
+	}
+}
+
+func TestFieldLogValuer(t *testing.T) {
+	t.Run("lazy resolve", func(t *testing.T) {
+		calls := 0
+		f := Any("user", testLogUser{name: "alice", calls: &calls})
+		if f.Type() != LogValuerType {
+			t.Errorf("Any(LogValuer).Type() = %v, want LogValuerType", f.Type())
+		}
+		if calls != 0 {
+			t.Errorf("LogValue should not be called at construction, calls = %d", calls)
+		}
+		if got := f.Value(); got != "alice" {
+			t.Errorf("Value() = %q, want %q", got, "alice")
+		}
+		if got := f.toInterface(); got != "alice" {
+			t.Errorf("toInterface() = %v, want %q", got, "alice")
+		}
+		if got := f.Format(); got != "user=alice" {
+			t.Errorf("Format() = %q, want key from Any", got)
+		}
+	})
+
+	t.Run("not resolved when filtered", func(t *testing.T) {
+		calls := 0
+		buf := &bytes.Buffer{}
+		l := New(&Config{Level: WARN, OutputConsole: true, Formatter: &testFormatter{buf: buf}})
+
+		l.Infow("suppressed", Any("user", testLogUser{name: "bob", calls: &calls}))
+		if calls != 0 {
+			t.Errorf("LogValue called %d times for filtered entry, want 0", calls)
+		}
+		l.Warnw("written", Any("user", testLogUser{name: "bob", calls: &calls}))
+		if calls == 0 || !strings.Contains(buf.String(), "user=bob") {
+			t.Errorf("LogValue should resolve when written, calls = %d, output = %q", calls, buf.String())
+		}
+	})
+
+	t.Run("depth limit", func(t *testing.T) {
+		f := Any("v", testLoopValuer{})
+		if r := f.resolve(); r.Type() != AnyType || r.Key() != "v" {
+			t.Errorf("resolve() of self-returning LogValuer = (%v, %q), want AnyType with key v", r.Type(), r.Key())
+		}
+	})
+}
@@ -0,0 +1,109 @@
+package fastlog
+
+// fieldPrefix 累积字段按某个格式化器编码的结果（内部使用）
+type fieldPrefix struct {
+	data  []byte // 编码后的字段, 前导分隔符的处理由格式化器决定
+	ns    string // 编码结束时所在的命名空间前缀 (KV、Logfmt)
+	depth int    // 编码结束时未闭合的命名空间层数 (JSON)
+}
+
+// prefixEncoder 支持预先编码累积字段的格式化器（内部使用）
+type prefixEncoder interface {
+	// encodePrefix 按格式化器编码字段
+	//
+	// 参数:
+	//   - fields: 要编码的字段
+	//   - tf: 时间格式
+	//
+	// 返回:
+	//   - fieldPrefix: 编码结果
+	encodePrefix(fields []Field, tf string) fieldPrefix
+}
+
+// fieldCache 日志记录器累积字段的编码缓存（内部使用）, 创建后只读
+//
+// 每个支持预先编码的格式化器各保存一份, 格式化时直接追加编码结果, 只需编码调用字段。
+// 条目仍携带完整的 []Field, 供 hooks、路由规则、按字段分发和 %field 读取。
+type fieldCache struct {
+	n          int           // 缓存覆盖的字段数, 即条目开头的 n 个字段
+	formatters []Formatter   // 已编码的格式化器
+	prefixes   []fieldPrefix // 与 formatters 一一对应
+}
+
+// newFieldCache 按各个格式化器预先编码累积字段
+//
+// 只编码开头连续的值类型字段: Any、Object、Array 和 LogValuer 字段引用外部的值,
+// 须在输出时编码, 它们及之后的字段仍逐条编码。
+//
+// 参数:
+//   - fields: 累积字段
+//   - tf: 时间格式
+//   - formatters: 日志记录器使用的格式化器, 不支持预先编码的格式化器被忽略
+//
+// 返回:
+//   - *fieldCache: 编码缓存, 没有可缓存的字段或格式化器时返回 nil
+func newFieldCache(fields []Field, tf string, formatters []Formatter) *fieldCache {
+	n := 0
+	for n < len(fields) && cacheableField(fields[n]) {
+		n++
+	}
+	if n == 0 {
+		return nil
+	}
+
+	c := &fieldCache{n: n}
+	for _, f := range formatters {
+		pe, ok := f.(prefixEncoder)
+		if !ok || c.index(f) >= 0 {
+			continue
+		}
+		c.formatters = append(c.formatters, f)
+		c.prefixes = append(c.prefixes, pe.encodePrefix(fields[:n], tf))
+	}
+	if len(c.formatters) == 0 {
+		return nil
+	}
+	return c
+}
+
+// cacheableField 返回字段的编码结果是否在创建后保持不变
+func cacheableField(f Field) bool {
+	switch f.typ {
+	case AnyType, ObjectType, ArrayType, LogValuerType:
+		return false
+	default:
+		return true
+	}
+}
+
+// index 返回格式化器在缓存中的下标, 未缓存时返回 -1
+//
+// 缓存中只有支持预先编码的格式化器, 它们的类型均可比较, 比较不会 panic。
+func (c *fieldCache) index(f Formatter) int {
+	for i, g := range c.formatters {
+		if g == f {
+			return i
+		}
+	}
+	return -1
+}
+
+// cachedFields 返回格式化器可直接使用的累积字段编码, 以及其后仍需逐条编码的字段
+//
+// 参数:
+//   - entry: 日志条目
+//   - f: 格式化器
+//
+// 返回:
+//   - *fieldPrefix: 编码缓存, 条目没有缓存或该格式化器未缓存时返回 nil
+//   - []Field: 仍需编码的字段
+func cachedFields(entry *Entry, f Formatter) (*fieldPrefix, []Field) {
+	c := entry.prefix
+	if c == nil || len(entry.Fields) < c.n {
+		return nil, entry.Fields
+	}
+	if i := c.index(f); i >= 0 {
+		return &c.prefixes[i], entry.Fields[c.n:]
+	}
+	return nil, entry.Fields
+}
@@ -0,0 +1,94 @@
+package fastlog
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFieldCacheMatchesFullEncoding(t *testing.T) {
+	ec := &EncoderConfig{StacktraceKey: "trace"}
+	formatters := []Formatter{JSON{}, JSON{EncoderConfig: ec}, KV{}, KV{EncoderConfig: ec}, Logfmt{}, Logfmt{EncoderConfig: ec}}
+	ts := time.Date(2025, 1, 15, 10, 30, 45, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		accumulated []Field
+		call        []Field
+		cached      int
+	}{
+		{"plain", []Field{String("app", "svc"), String(DefaultStacktraceKey, "s")}, []Field{String("path", "/api")}, 2},
+		{"no call fields", []Field{String("app", "svc"), Bool("ok", true)}, nil, 2},
+		{"namespace wraps call fields", []Field{String("app", "svc"), Namespace("req"), Time("at", ts)}, []Field{Int("n", 1)}, 3},
+		{"trailing namespace", []Field{Namespace("req")}, []Field{String("id", "x y")}, 1},
+		{"stops at reference fields", []Field{String("app", "svc"), Any("m", map[string]int{"a": 1}), String("k", "v")}, []Field{Int("n", 1)}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newFieldCache(tt.accumulated, time.DateTime, append(formatters, Def{}))
+			if c == nil || c.n != tt.cached || len(c.formatters) != len(formatters) {
+				t.Fatalf("newFieldCache() = %+v", c)
+			}
+			fields := mergeFields(tt.accumulated, tt.call)
+			for _, f := range formatters {
+				plain := &Entry{Time: ts, Level: INFO, Message: "m", Fields: fields, TimeFormat: time.DateTime, Logger: "api"}
+				cached := *plain
+				cached.prefix = c
+				want, _ := f.Format(plain)
+				got, _ := f.Format(&cached)
+				if string(got) != string(want) {
+					t.Errorf("%T%+v: cached = %q, want %q", f, f, got, want)
+				}
+			}
+		})
+	}
+
+	if c := newFieldCache([]Field{Any("m", 1)}, time.DateTime, formatters); c != nil {
+		t.Errorf("newFieldCache() should not cache reference fields, got %+v", c)
+	}
+	if c := newFieldCache([]Field{String("k", "v")}, time.DateTime, []Formatter{Def{}}); c != nil {
+		t.Errorf("newFieldCache() should ignore formatters without prefix support, got %+v", c)
+	}
+}
+
+func TestWithUsesFieldCache(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := New(&Config{Level: DEBUG, Outputs: []Output{
+		{Writer: buf, Formatter: Logfmt{}},
+		{Writer: buf, Formatter: MustPattern("%msg %fields")},
+	}})
+	child := l.With(String("app", "svc")).Named("api")
+	if child.prefix == nil || child.prefix.index(Logfmt{}) < 0 {
+		t.Fatalf("With() should pre-encode fields for Logfmt, prefix = %+v", child.prefix)
+	}
+
+	// 替换缓存内容, 确认格式化时使用的是预先编码的结果, 而 %fields 仍读取字段
+	child.prefix.prefixes[child.prefix.index(Logfmt{})].data = []byte(" app=cached")
+	child.Infow("m", Int("n", 1))
+	_ = l.Close()
+
+	got := buf.String()
+	if !strings.Contains(got, "logger=api app=cached n=1\n") || !strings.Contains(got, "m app=svc n=1\n") {
+		t.Errorf("output = %q", got)
+	}
+}
+
+func TestWithFieldCacheSkippedForSyncHooks(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := New(&Config{Level: DEBUG, Outputs: []Output{{Writer: buf, Formatter: JSON{}}}})
+	child := l.With(String("app", "svc"))
+	child.AddHook(&funcHook{fire: func(_ context.Context, e *Entry) error {
+		e.Fields[0] = String("app", "changed")
+		return nil
+	}}, nil)
+
+	child.Info("m")
+	_ = l.Close()
+
+	// 同步 hook 可能修改累积字段, 此时不能使用预先编码的结果
+	if got := buf.String(); !strings.Contains(got, `"app":"changed"`) {
+		t.Errorf("output = %q", got)
+	}
+}
@@ -3,8 +3,6 @@ package fastlog
 import (
 	"bytes"
 	"fmt"
-
-	"github.com/goccy/go-json"
 )
 
 // Formatter 定义日志格式化器接口
@@ -13,9 +11,21 @@ type Formatter interface {
 	Format(entry *Entry) ([]byte, error)
 }
 
+// AppendFormatter 可选的追加式格式化接口
+//
+// 格式化器实现该接口时, Logger 会传入池化缓冲区, 格式化结果直接追加到其后,
+// 避免每条日志分配新的字节数组。返回的切片在写入完成后归还缓冲池, 实现方不得持有。
+type AppendFormatter interface {
+	// AppendFormat 将日志条目格式化后追加到 dst, 返回追加后的切片
+	AppendFormat(dst []byte, entry *Entry) ([]byte, error)
+}
+
 // Def 默认格式
 // 格式: 2025-01-15 10:30:45 | INFO    | main.go:main:15 - 用户登录成功
-type Def struct{}
+type Def struct {
+	// Sanitize 消息和字段的净化模式, 零值不做处理
+	Sanitize SanitizeMode
+}
 
 // Format 实现默认格式化器
 //
@@ -43,17 +53,12 @@ func (f Def) Format(entry *Entry) ([]byte, error) {
 	}
 
 	// 消息
-	buf.WriteString(entry.Message)
+	writeSanitized(&buf, entry.Message, f.Sanitize)
 
 	// 字段
-	if len(entry.Fields) > 0 {
+	if hasFields(entry) {
 		buf.WriteByte(' ')
-		for i, field := range entry.Fields {
-			if i > 0 {
-				buf.WriteString(", ")
-			}
-			buf.WriteString(field.formatWithTimeFormat(entry.TimeFormat))
-		}
+		writeFields(&buf, entry, ", ", f.Sanitize)
 	}
 
 	buf.WriteByte('\n')
@@ -61,7 +66,13 @@ func (f Def) Format(entry *Entry) ([]byte, error) {
 }
 
 // JSON JSON 格式
-type JSON struct{}
+//
+// 按固定顺序流式写入: time, level, message, caller, logger, 然后按调用顺序写入字段。
+// 重复的键原样保留, 命名空间字段开启一层嵌套对象。
+type JSON struct {
+	// EncoderConfig 编码器配置, 为 nil 时使用默认键名和编码方式
+	EncoderConfig *EncoderConfig
+}
 
 // Format 实现 JSON 格式
 //
@@ -72,38 +83,99 @@ type JSON struct{}
 //   - []byte: 格式化后的字节数组
 //   - error: 如果格式化失败
 func (f JSON) Format(entry *Entry) ([]byte, error) {
-	// 预分配容量, 避免 rehash
-	cap := 4 + len(entry.Fields) // time + level + message + caller + fields
-	data := make(map[string]interface{}, cap)
+	return f.AppendFormat(make([]byte, 0, 256), entry)
+}
+
+// AppendFormat 实现 AppendFormatter 接口, 将 JSON 追加到 dst
+//
+// 参数:
+//   - dst: 目标缓冲区
+//   - entry: 日志条目
+//
+// 返回:
+//   - []byte: 追加后的字节数组
+//   - error: 如果格式化失败
+func (f JSON) AppendFormat(dst []byte, entry *Entry) ([]byte, error) {
+	ec := f.EncoderConfig
+	enc := getJSONEncoder(append(dst, '{'), entry.TimeFormat)
 
 	// 添加基础字段
-	data["time"] = entry.Time.Format(entry.TimeFormat)
-	data["level"] = entry.Level.String()
-	data["message"] = entry.Message
+	enc.addKey(ec.timeKey())
+	ec.encodeTime(entry.Time, enc)
+	enc.fillEmpty()
+	enc.addKey(ec.levelKey())
+	ec.encodeLevel(entry.Level, enc)
+	enc.fillEmpty()
+	enc.AddString(ec.messageKey(), entry.Message)
 
 	// 添加调用者信息
 	if entry.Caller != "" {
-		data["caller"] = entry.Caller
+		enc.addKey(ec.callerKey())
+		ec.encodeCaller(entry, enc)
+		enc.fillEmpty()
 	}
 
-	// 添加字段
-	for _, field := range entry.Fields {
-		data[field.Key()] = field.toInterfaceWithTimeFormat(entry.TimeFormat)
+	// 添加日志记录器名称
+	if entry.Logger != "" {
+		enc.AddString(ec.nameKey(), entry.Logger)
 	}
 
-	b, err := json.Marshal(data)
-	if err != nil {
-		return nil, err
+	// 添加字段: 累积字段使用子日志记录器预先编码的结果, 命名空间字段开启一层嵌套对象
+	depth := 0
+	p, fields := cachedFields(entry, f)
+	if p != nil {
+		if len(p.data) > 0 {
+			enc.sep()
+			enc.buf = append(enc.buf, p.data...)
+		}
+		depth = p.depth
+	}
+	depth = appendJSONFields(enc, ec, fields, depth)
+	for ; depth > 0; depth-- {
+		enc.buf = append(enc.buf, '}')
 	}
 
 	// 添加换行符
-	b = append(b, '\n')
-	return b, nil
+	return append(putJSONEncoder(enc), '}', '\n'), nil
+}
+
+// encodePrefix 实现 prefixEncoder, 编码结果不含前导逗号
+func (f JSON) encodePrefix(fields []Field, tf string) fieldPrefix {
+	enc := getJSONEncoder(nil, tf)
+	depth := appendJSONFields(enc, f.EncoderConfig, fields, 0)
+	return fieldPrefix{data: putJSONEncoder(enc), depth: depth}
+}
+
+// appendJSONFields 追加 JSON 字段, 命名空间字段开启一层嵌套对象
+//
+// 参数:
+//   - enc: JSON 编码器
+//   - ec: 编码器配置
+//   - fields: 字段
+//   - depth: 当前未闭合的命名空间层数
+//
+// 返回:
+//   - int: 追加后未闭合的命名空间层数
+func appendJSONFields(enc *jsonEncoder, ec *EncoderConfig, fields []Field, depth int) int {
+	for _, field := range fields {
+		if field.typ == NamespaceType {
+			enc.addKey(field.key)
+			enc.buf = append(enc.buf, '{')
+			depth++
+			continue
+		}
+		enc.addKey(ec.fieldKey(field.key))
+		enc.appendValue(field)
+	}
+	return depth
 }
 
 // Simple 简单格式
 // 格式: 2025-01-15 10:30:45 INFO  用户登录成功
-type Simple struct{}
+type Simple struct {
+	// Sanitize 消息和字段的净化模式, 零值不做处理
+	Sanitize SanitizeMode
+}
 
 // Format 实现简单格式
 //
@@ -120,16 +192,11 @@ func (f Simple) Format(entry *Entry) ([]byte, error) {
 	buf.WriteByte(' ')
 	buf.WriteString(entry.Level.String())
 	buf.WriteByte(' ')
-	buf.WriteString(entry.Message)
+	writeSanitized(&buf, entry.Message, f.Sanitize)
 
-	if len(entry.Fields) > 0 {
+	if hasFields(entry) {
 		buf.WriteByte(' ')
-		for i, field := range entry.Fields {
-			if i > 0 {
-				buf.WriteString(", ")
-			}
-			buf.WriteString(field.formatWithTimeFormat(entry.TimeFormat))
-		}
+		writeFields(&buf, entry, ", ", f.Sanitize)
 	}
 
 	buf.WriteByte('\n')
@@ -138,7 +205,10 @@ func (f Simple) Format(entry *Entry) ([]byte, error) {
 
 // KV 键值对格式
 // 格式: time=2025-01-15 10:30:45 level=INFO message=用户登录成功
-type KV struct{}
+type KV struct {
+	// EncoderConfig 编码器配置, 为 nil 时使用默认键名和编码方式
+	EncoderConfig *EncoderConfig
+}
 
 // Format 实现键值对格式
 //
@@ -149,34 +219,80 @@ type KV struct{}
 //   - []byte: 格式化后的字节数组
 //   - error: 如果格式化失败
 func (f KV) Format(entry *Entry) ([]byte, error) {
-	var buf bytes.Buffer
+	ec := f.EncoderConfig
+	enc := &textEncoder{buf: make([]byte, 0, 256), tf: entry.TimeFormat}
 
-	buf.WriteString("time=")
-	buf.WriteString(entry.Time.Format(entry.TimeFormat))
-	buf.WriteString(" level=")
-	buf.WriteString(entry.Level.String())
-	buf.WriteString(" message=")
-	buf.WriteString(entry.Message)
+	enc.addEncodedKey(ec.timeKey())
+	ec.encodeTime(entry.Time, enc)
+	enc.addEncodedKey(ec.levelKey())
+	ec.encodeLevel(entry.Level, enc)
+	enc.AddString(ec.messageKey(), entry.Message)
 
 	if entry.Caller != "" {
-		buf.WriteString(" caller=")
-		buf.WriteString(entry.Caller)
+		enc.addEncodedKey(ec.callerKey())
+		ec.encodeCaller(entry, enc)
 	}
 
-	for _, field := range entry.Fields {
-		buf.WriteByte(' ')
-		buf.WriteString(field.formatWithTimeFormat(entry.TimeFormat))
+	if entry.Logger != "" {
+		enc.AddString(ec.nameKey(), entry.Logger)
 	}
 
-	buf.WriteByte('\n')
-	return buf.Bytes(), nil
+	// 累积字段使用子日志记录器预先编码的结果
+	var ns string // 当前命名空间前缀
+	p, fields := cachedFields(entry, f)
+	if p != nil {
+		if len(p.data) > 0 {
+			enc.sep()
+			enc.buf = append(enc.buf, p.data...)
+		}
+		ns = p.ns
+	}
+	appendKVFields(enc, ec, fields, ns)
+
+	enc.buf = append(enc.buf, '\n')
+	return enc.buf, nil
+}
+
+// encodePrefix 实现 prefixEncoder, 编码结果不含前导空格
+func (f KV) encodePrefix(fields []Field, tf string) fieldPrefix {
+	enc := &textEncoder{tf: tf}
+	ns := appendKVFields(enc, f.EncoderConfig, fields, "")
+	return fieldPrefix{data: enc.buf, ns: ns}
+}
+
+// appendKVFields 以 key=value 形式追加字段, 命名空间以点号拼接为键前缀
+//
+// 参数:
+//   - enc: 文本编码器
+//   - ec: 编码器配置
+//   - fields: 字段
+//   - ns: 当前命名空间前缀
+//
+// 返回:
+//   - string: 追加后的命名空间前缀
+func appendKVFields(enc *textEncoder, ec *EncoderConfig, fields []Field, ns string) string {
+	for _, field := range fields {
+		if field.typ == NamespaceType {
+			ns = joinNamespace(ns, field.key)
+			continue
+		}
+		key := ec.fieldKey(field.key)
+		if ns != "" {
+			key = ns + "." + key
+		}
+		enc.AddString(key, field.valueWithTimeFormat(enc.tf))
+	}
+	return ns
 }
 
 // Compact 极简格式
 // 格式: [I] 2025-01-15 10:30:45 用户登录成功
 // 特点: 级别首字母 + 时间戳，简洁易读，适合容器环境
 // 时间格式遵循 Config.TimeFormat，默认 time.DateTime (2006-01-02 15:04:05)
-type Compact struct{}
+type Compact struct {
+	// Sanitize 消息和字段的净化模式, 零值不做处理
+	Sanitize SanitizeMode
+}
 
 // Format 实现 Compact 格式
 //
@@ -199,19 +315,108 @@ func (f Compact) Format(entry *Entry) ([]byte, error) {
 	buf.WriteByte(' ')
 
 	// 消息
-	buf.WriteString(entry.Message)
+	writeSanitized(&buf, entry.Message, f.Sanitize)
 
 	// 字段（简化为 key=value 形式）
-	if len(entry.Fields) > 0 {
+	if hasFields(entry) {
 		buf.WriteString(" | ")
-		for i, field := range entry.Fields {
-			if i > 0 {
-				buf.WriteByte(' ')
-			}
-			buf.WriteString(field.formatWithTimeFormat(entry.TimeFormat))
-		}
+		writeFields(&buf, entry, " ", f.Sanitize)
 	}
 
 	buf.WriteByte('\n')
 	return buf.Bytes(), nil
 }
+
+// hasFields 判断条目是否有需要输出的字段 (含日志记录器名称, 不含命名空间标记)
+//
+// 参数:
+//   - entry: 日志条目
+//
+// 返回:
+//   - bool: 是否有字段
+func hasFields(entry *Entry) bool {
+	if entry.Logger != "" {
+		return true
+	}
+	for _, field := range entry.Fields {
+		if field.typ != NamespaceType {
+			return true
+		}
+	}
+	return false
+}
+
+// writeFields 以 key=value 形式写入日志记录器名称和字段 (内部辅助函数)
+//
+// 日志记录器名称不为空时作为首个字段 logger=name 输出。
+// 命名空间字段本身不输出, 其后字段的键以点号拼接命名空间前缀。
+//
+// 参数:
+//   - buf: 输出缓冲区
+//   - entry: 日志条目
+//   - sep: 字段分隔符
+//   - mode: 键和值的净化模式
+func writeFields(buf *bytes.Buffer, entry *Entry, sep string, mode SanitizeMode) {
+	n := 0 // 已写入的字段数
+	if entry.Logger != "" {
+		buf.WriteString("logger=")
+		buf.WriteString(entry.Logger)
+		n++
+	}
+
+	var ns string // 当前命名空间前缀
+	for _, field := range entry.Fields {
+		if field.typ == NamespaceType {
+			ns = joinNamespace(ns, field.key)
+			continue
+		}
+		if n > 0 {
+			buf.WriteString(sep)
+		}
+		writeField(buf, ns, field, entry.TimeFormat, mode)
+		n++
+	}
+}
+
+// writeField 以 key=value 形式写入单个字段, 键带命名空间前缀 (内部辅助函数)
+//
+// 参数:
+//   - buf: 输出缓冲区
+//   - ns: 命名空间前缀, 为空表示无命名空间
+//   - field: 字段
+//   - tf: 时间格式
+//   - mode: 键和值的净化模式
+func writeField(buf *bytes.Buffer, ns string, field Field, tf string, mode SanitizeMode) {
+	if mode == SanitizeOff {
+		if ns != "" {
+			buf.WriteString(ns)
+			buf.WriteByte('.')
+		}
+		buf.WriteString(field.formatWithTimeFormat(tf))
+		return
+	}
+	// 键不允许换行, 始终转义
+	keyMode := mode&^SanitizeIndent | SanitizeEscape
+	if ns != "" {
+		writeSanitized(buf, ns, keyMode)
+		buf.WriteByte('.')
+	}
+	writeSanitized(buf, field.key, keyMode)
+	buf.WriteByte('=')
+	writeSanitized(buf, field.valueWithTimeFormat(tf), mode)
+}
+
+// joinNamespace 拼接嵌套命名空间
+//
+// 参数:
+//   - ns: 当前命名空间前缀
+//   - key: 新的命名空间名称
+//
+// 返回:
+//   - string: 拼接后的前缀, 如 "outer.inner"
+func joinNamespace(ns, key string) string {
+	if ns == "" {
+		return key
+	}
+	return ns + "." + key
+}
@@ -459,3 +459,220 @@ func TestCompactFormatWithCustomTimeFormat(t *testing.T) {
 		t.Errorf("Compact format with UnixDate should contain time, got: %s", output)
 	}
 }
+
+func TestFormatterLoggerName(t *testing.T) {
+	entry := makeEntry("hello", "", String("k", "v"))
+	entry.Logger = "app.db"
+
+	tests := []struct {
+		name      string
+		formatter Formatter
+		want      string
+	}{
+		{"Def", Def{}, "2026-01-15 10:30:45 | INFO   | hello logger=app.db, k=v\n"},
+		{"Simple", Simple{}, "2026-01-15 10:30:45 INFO hello logger=app.db, k=v\n"},
+		{"KV", KV{}, "time=2026-01-15 10:30:45 level=INFO message=hello logger=app.db k=v\n"},
+		{"Compact", Compact{}, "[I] 2026-01-15 10:30:45 hello | logger=app.db k=v\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := tt.formatter.Format(entry)
+			if err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+			if got := string(b); got != tt.want {
+				t.Errorf("%s.Format() = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("JSON", func(t *testing.T) {
+		b, err := JSON{}.Format(entry)
+		if err != nil {
+			t.Fatalf("Format() error = %v", err)
+		}
+		var m map[string]interface{}
+		if err := json.Unmarshal(b, &m); err != nil {
+			t.Fatalf("json.Unmarshal() error = %v", err)
+		}
+		if m["logger"] != "app.db" {
+			t.Errorf("JSON logger = %v, want app.db", m["logger"])
+		}
+	})
+
+	t.Run("no name no field", func(t *testing.T) {
+		b, _ := Def{}.Format(makeEntry("hello", ""))
+		if strings.Contains(string(b), "logger=") {
+			t.Errorf("Def.Format() without name should not emit logger, got %q", b)
+		}
+	})
+}
+
+func TestFormatterNamespace(t *testing.T) {
+	fields := []Field{
+		String("method", "POST"),
+		Namespace("user"),
+		String("name", "alice"),
+		Namespace("stats"),
+		Int("logins", 3),
+	}
+
+	tests := []struct {
+		name      string
+		formatter Formatter
+		want      string
+	}{
+		{"Def", Def{}, "2026-01-15 10:30:45 | INFO   | hello method=POST, user.name=alice, user.stats.logins=3\n"},
+		{"Simple", Simple{}, "2026-01-15 10:30:45 INFO hello method=POST, user.name=alice, user.stats.logins=3\n"},
+		{"KV", KV{}, "time=2026-01-15 10:30:45 level=INFO message=hello method=POST user.name=alice user.stats.logins=3\n"},
+		{"Compact", Compact{}, "[I] 2026-01-15 10:30:45 hello | method=POST user.name=alice user.stats.logins=3\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := tt.formatter.Format(makeEntry("hello", "", fields...))
+			if err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+			if got := string(b); got != tt.want {
+				t.Errorf("%s.Format() = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("JSON nested", func(t *testing.T) {
+		b, err := JSON{}.Format(makeEntry("hello", "", fields...))
+		if err != nil {
+			t.Fatalf("Format() error = %v", err)
+		}
+		var m map[string]interface{}
+		if err := json.Unmarshal(b, &m); err != nil {
+			t.Fatalf("json.Unmarshal() error = %v", err)
+		}
+		user, ok := m["user"].(map[string]interface{})
+		if !ok {
+			t.Fatalf("JSON user should be nested object, got %v", m["user"])
+		}
+		stats, ok := user["stats"].(map[string]interface{})
+		if !ok || user["name"] != "alice" || stats["logins"] != float64(3) {
+			t.Errorf("JSON nested namespace = %v", user)
+		}
+		if m["method"] != "POST" {
+			t.Errorf("JSON method = %v, want POST", m["method"])
+		}
+	})
+
+	t.Run("namespace only", func(t *testing.T) {
+		b, _ := Def{}.Format(makeEntry("hello", "", Namespace("empty")))
+		if got := string(b); got != "2026-01-15 10:30:45 | INFO   | hello\n" {
+			t.Errorf("Def.Format() with only namespace = %q", got)
+		}
+	})
+}
+
+func TestLoggerWithNamespace(t *testing.T) {
+	m := newMock()
+	l := New(&Config{Level: INFO, OutputConsole: true, Formatter: Simple{}})
+	l.writer = m
+
+	l.With(String("app", "svc"), Namespace("req")).Infow("handled", String("id", "r-1"))
+	if !strings.Contains(m.String(), "handled app=svc, req.id=r-1") {
+		t.Errorf("With namespace should prefix call fields, got: %q", m.String())
+	}
+}
+
+func TestJSONFormatStreaming(t *testing.T) {
+	t.Run("key order and duplicates", func(t *testing.T) {
+		entry := makeEntry("hello", "main.go:main:10", String("b", "1"), Int("a", 2), String("b", "3"))
+		entry.Logger = "app.db"
+		b, err := JSON{}.Format(entry)
+		if err != nil {
+			t.Fatalf("Format() error = %v", err)
+		}
+		want := `{"time":"2026-01-15 10:30:45","level":"INFO","message":"hello","caller":"main.go:main:10","logger":"app.db","b":"1","a":2,"b":"3"}` + "\n"
+		if string(b) != want {
+			t.Errorf("JSON.Format() =\n%s\nwant\n%s", b, want)
+		}
+	})
+
+	t.Run("escaping", func(t *testing.T) {
+		msg := "line1\n\"quoted\"\t\x1b[31mred\\"
+		b, _ := JSON{}.Format(makeEntry(msg, "", String("k\"ey", "v\x00")))
+		var m map[string]interface{}
+		if err := json.Unmarshal(b, &m); err != nil {
+			t.Fatalf("invalid JSON %q: %v", b, err)
+		}
+		if m["message"] != msg || m["k\"ey"] != "v\x00" {
+			t.Errorf("escaped values did not round-trip: %v", m)
+		}
+	})
+
+	t.Run("append to dst", func(t *testing.T) {
+		dst := []byte("prefix:")
+		b, err := JSON{}.AppendFormat(dst, makeEntry("hello", ""))
+		if err != nil {
+			t.Fatalf("AppendFormat() error = %v", err)
+		}
+		if !strings.HasPrefix(string(b), `prefix:{"time":`) {
+			t.Errorf("AppendFormat() should append after dst, got %q", b)
+		}
+	})
+
+	t.Run("unsupported any value", func(t *testing.T) {
+		b, err := JSON{}.Format(makeEntry("hello", "", Any("ch", make(chan int)), String("k", "v")))
+		if err != nil {
+			t.Fatalf("Format() error = %v", err)
+		}
+		var m map[string]interface{}
+		if err := json.Unmarshal(b, &m); err != nil {
+			t.Fatalf("invalid JSON %q: %v", b, err)
+		}
+		if m["k"] != "v" {
+			t.Errorf("fields after unsupported value should be kept, got %v", m)
+		}
+	})
+}
+
+func TestJSONAppendFormatAllocs(t *testing.T) {
+	entry := makeEntry("hello", "main.go:main:10",
+		String("user", "alice"), Int("age", 30), Float64("score", 9.5), Bool("ok", true),
+		Time("at", time.Date(2026, 1, 15, 10, 30, 45, 0, time.UTC)))
+	buf := make([]byte, 0, 1024)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		buf, _ = JSON{}.AppendFormat(buf[:0], entry)
+	})
+	if allocs != 0 {
+		t.Errorf("JSON.AppendFormat() allocs = %v, want 0", allocs)
+	}
+}
+
+func benchEntry() *Entry {
+	return makeEntry("用户登录成功", "main.go:main:10",
+		String("user", "alice"), Int("age", 30), Float64("score", 9.5),
+		Bool("admin", false), Duration("took", 1500*time.Millisecond))
+}
+
+func BenchmarkJSONFormat(b *testing.B) {
+	entry := benchEntry()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = JSON{}.Format(entry)
+	}
+}
+
+func BenchmarkJSONAppendFormat(b *testing.B) {
+	entry := benchEntry()
+	buf := make([]byte, 0, 1024)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf, _ = JSON{}.AppendFormat(buf[:0], entry)
+	}
+}
+
+func BenchmarkDefFormat(b *testing.B) {
+	entry := benchEntry()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = Def{}.Format(entry)
+	}
+}
@@ -3,16 +3,15 @@ module gitee.com/MM-Q/fastlog
 go 1.25.0
 
 require (
-	gitee.com/MM-Q/color v1.0.4
 	gitee.com/MM-Q/comprx v0.1.7
 	gitee.com/MM-Q/logrotatex v1.2.5
 	github.com/goccy/go-json v0.10.6
+	github.com/mattn/go-colorable v0.1.14
+	github.com/mattn/go-isatty v0.0.22
 )
 
 require (
 	gitee.com/MM-Q/go-kit v0.0.20 // indirect
-	github.com/mattn/go-colorable v0.1.14 // indirect
-	github.com/mattn/go-isatty v0.0.22 // indirect
 	github.com/mitchellh/colorstring v0.0.0-20190213212951-d06e56a500db // indirect
 	github.com/rivo/uniseg v0.4.7 // indirect
 	github.com/schollz/progressbar/v3 v3.19.0 // indirect
@@ -1,9 +1,19 @@
 package fastlog
 
-import "io"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
 
 // hook 日志钩子接口（内部使用，小写不导出）
-// 用于在日志输出时执行额外操作，如按级别分发到不同文件
+// 用于在日志输出时执行额外操作，如按路由规则分发到不同文件
 type hook interface {
 	// Fire 日志触发时调用
 	// 参数:
@@ -28,55 +38,353 @@ type hook interface {
 	Close() error
 }
 
-// levelHook 按级别分发的钩子（内部使用）
-// 将特定级别的日志写入指定目标
-type levelHook struct {
-	level  Level          // 关心的级别
-	writer io.WriteCloser // 写入目标
+// Hook 日志钩子接口
+//
+// 通过 Logger.AddHook 注册。同步 hook 在日志格式化之前于记录日志的协程中执行,
+// 可以修改日志条目 (如追加字段、改写消息或级别) 或返回 ErrDropEntry 丢弃该条日志;
+// 异步 hook 在后台协程中收到日志条目的副本, 适合发送告警等慢速操作, 不能修改或丢弃日志。
+//
+// 示例:
+//
+//	type hostHook struct{ host string }
+//
+//	func (h hostHook) Levels() []fastlog.Level { return nil }
+//
+//	func (h hostHook) Fire(ctx context.Context, entry *fastlog.Entry) error {
+//		entry.Fields = append(entry.Fields, fastlog.String("host", h.host))
+//		return nil
+//	}
+type Hook interface {
+	// Levels 返回关心的日志级别, 为空表示所有级别
+	Levels() []Level
+
+	// Fire 日志触发时调用
+	// 参数:
+	//   - ctx: 设置了 HookConfig.Timeout 时携带截止时间, hook 应自行检查并在超时后尽快返回
+	//   - entry: 日志条目, 同步 hook 可修改, Fire 返回后不得再持有
+	// 返回:
+	//   - error: 执行过程中的错误, 返回 ErrDropEntry 表示丢弃该条日志 (仅同步 hook)
+	Fire(ctx context.Context, entry *Entry) error
 }
 
-// Fire 执行钩子
-// 如果 entry.Level 匹配，则写入专属文件
-// 参数:
-//   - entry: 日志条目
-//   - data: 格式化后的日志数据
+var (
+	// ErrDropEntry 由同步 hook 返回, 表示丢弃该条日志, 不会报告为错误
+	ErrDropEntry = errors.New("log entry dropped by hook")
+
+	// ErrHookQueueFull 异步 hook 的队列已满, 该条日志未交给 hook
+	ErrHookQueueFull = errors.New("hook queue is full")
+
+	// ErrHookTimeout hook 执行时间超过 HookConfig.Timeout, 或 Sync、Close 等待异步 hook 超过 HookConfig.DrainTimeout
+	ErrHookTimeout = errors.New("hook timed out")
+)
+
+// 异步 hook 默认值
+const (
+	DefaultHookQueueSize    = 1024            // 默认异步 hook 队列容量
+	DefaultHookDrainTimeout = 5 * time.Second // 默认 Sync/Close 等待异步 hook 的最长时间
+)
+
+// HookConfig hook 执行配置
+type HookConfig struct {
+	// Async 为 true 时在后台协程中执行 hook, 不阻塞日志记录
+	Async bool
+
+	// QueueSize 异步 hook 的队列容量, 零值默认 DefaultHookQueueSize, 队列满时丢弃并报告 ErrHookQueueFull
+	QueueSize int
+
+	// Timeout 单次 Fire 的 ctx 截止时间, 零值表示不设置
+	// 仅是协作式的: 不会中断 Fire, 调用方始终等待 Fire 返回, 忽略 ctx 的同步 hook 会一直阻塞日志记录;
+	// Fire 在截止时间之后才返回 (且未返回其他错误) 时报告 ErrHookTimeout
+	Timeout time.Duration
+
+	// DrainTimeout Sync、Close 和 RemoveHook 等待异步 hook 执行完已入队日志的最长时间, 零值默认 5 秒
+	// 超时后 Sync 和 Close 返回 ErrHookTimeout, 后台协程继续执行剩余日志后退出
+	DrainTimeout time.Duration
+
+	// OnError hook 返回错误、超时或队列满时调用, nil 时输出到标准错误
+	// 异步 hook 的 OnError 在后台协程中调用, entry 仅在调用期间有效
+	OnError func(h Hook, entry *Entry, err error)
+}
+
+// userHook 已注册的 hook（内部使用）
+type userHook struct {
+	hook    Hook          // 用户 hook
+	levels  []Level       // 关心的级别, 为空表示所有级别
+	timeout time.Duration // 单次 Fire 的超时时间
+	onError func(h Hook, entry *Entry, err error)
+
+	// 以下字段仅异步 hook 使用
+	drainTimeout time.Duration // 等待队列执行完毕的最长时间
+	mu           sync.RWMutex  // 保护 closed 与 jobs 的关闭
+	jobs         chan hookJob  // 待执行的日志条目
+	closed       bool          // 是否已停止接收
+	done         chan struct{} // 后台协程已退出
+}
+
+// hookJob 异步 hook 的任务: 日志条目或同步标记
+type hookJob struct {
+	entry *Entry        // 日志条目副本
+	flush chan struct{} // 非 nil 时为同步标记, 之前的任务执行完毕后关闭
+}
+
+// newUserHook 根据配置创建 hook, 异步 hook 同时启动后台协程
+func newUserHook(h Hook, cfg *HookConfig) *userHook {
+	if cfg == nil {
+		cfg = &HookConfig{}
+	}
+	uh := &userHook{hook: h, levels: h.Levels(), timeout: cfg.Timeout, onError: cfg.OnError}
+	if cfg.Async {
+		size := cfg.QueueSize
+		if size <= 0 {
+			size = DefaultHookQueueSize
+		}
+		uh.drainTimeout = cfg.DrainTimeout
+		if uh.drainTimeout <= 0 {
+			uh.drainTimeout = DefaultHookDrainTimeout
+		}
+		uh.jobs = make(chan hookJob, size)
+		uh.done = make(chan struct{})
+		go uh.run()
+	}
+	return uh
+}
+
+// wants 返回 hook 是否关心该级别
+func (h *userHook) wants(level Level) bool {
+	return len(h.levels) == 0 || slices.Contains(h.levels, level)
+}
+
+// fire 执行 hook 并处理超时和错误
+//
+// 返回:
+//   - bool: hook 返回 ErrDropEntry 时返回 true
+func (h *userHook) fire(entry *Entry) bool {
+	ctx := context.Background()
+	if h.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.timeout)
+		defer cancel()
+	}
+	err := h.hook.Fire(ctx, entry)
+	if errors.Is(err, ErrDropEntry) {
+		return true
+	}
+	if err == nil && ctx.Err() != nil {
+		err = ErrHookTimeout
+	}
+	if err != nil {
+		h.report(entry, err)
+	}
+	return false
+}
+
+// report 报告 hook 错误
+func (h *userHook) report(entry *Entry, err error) {
+	if h.onError != nil {
+		h.onError(h.hook, entry, err)
+		return
+	}
+	_, _ = fmt.Fprintf(os.Stderr, "hook %T error: %v\n", h.hook, err)
+}
+
+// dispatch 将日志条目的副本交给异步 hook, 队列满时报告 ErrHookQueueFull
+func (h *userHook) dispatch(entry *Entry) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if h.closed {
+		return
+	}
+	e := copyEntry(entry)
+	select {
+	case h.jobs <- hookJob{entry: e}:
+	default:
+		PutEntry(e)
+		h.report(entry, ErrHookQueueFull)
+	}
+}
+
+// run 异步 hook 的后台协程: 依次执行队列中的任务, 队列关闭后退出
+func (h *userHook) run() {
+	defer close(h.done)
+	for job := range h.jobs {
+		if job.flush != nil {
+			close(job.flush)
+			continue
+		}
+		h.fire(job.entry)
+		PutEntry(job.entry)
+	}
+}
+
+// flush 等待异步 hook 执行完此前入队的日志, 最长等待 drainTimeout
 //
 // 返回:
-//   - error: 写入过程中的错误
-func (h *levelHook) Fire(entry *Entry, data []byte) error {
-	if entry.Level != h.level {
-		return nil // 级别不匹配，忽略
+//   - error: 超时返回 ErrHookTimeout
+func (h *userHook) flush() error {
+	if h.jobs == nil {
+		return nil
+	}
+	timer := time.NewTimer(h.drainTimeout)
+	defer timer.Stop()
+
+	h.mu.RLock()
+	if h.closed {
+		h.mu.RUnlock()
+		return nil
+	}
+	done := make(chan struct{})
+	select {
+	case h.jobs <- hookJob{flush: done}:
+	case <-timer.C:
+		h.mu.RUnlock()
+		return h.timeoutError()
+	}
+	h.mu.RUnlock()
+
+	select {
+	case <-done:
+		return nil
+	case <-timer.C:
+		return h.timeoutError()
 	}
-	_, err := h.writer.Write(data)
-	return err
 }
 
-// Levels 返回关心的级别
+// stop 停止接收新日志, 等待异步 hook 执行完队列中的日志, 最长等待 drainTimeout
+//
 // 返回:
-//   - []Level: 只包含一个级别
-func (h *levelHook) Levels() []Level {
-	return []Level{h.level}
+//   - error: 超时返回 ErrHookTimeout, 后台协程继续执行剩余日志后退出
+func (h *userHook) stop() error {
+	if h.jobs == nil {
+		return nil
+	}
+	timer := time.NewTimer(h.drainTimeout)
+	defer timer.Stop()
+
+	h.mu.Lock()
+	if !h.closed {
+		h.closed = true
+		close(h.jobs)
+	}
+	h.mu.Unlock()
+
+	select {
+	case <-h.done:
+		return nil
+	case <-timer.C:
+		return h.timeoutError()
+	}
+}
+
+// timeoutError 返回带 hook 类型的 ErrHookTimeout
+func (h *userHook) timeoutError() error {
+	return fmt.Errorf("hook %T: %w", h.hook, ErrHookTimeout)
+}
+
+// hookSet 通过 AddHook 注册的 hooks（内部使用）, 与子日志记录器共享
+//
+// 注册和移除时整体替换列表, 记录日志时无锁读取。
+type hookSet struct {
+	mu      sync.Mutex                  // 串行化注册和移除
+	hooks   atomic.Pointer[[]*userHook] // 当前 hooks, 只读
+	hasSync atomic.Bool                 // 是否存在同步 hook, 为 false 时字段无需复制
+}
+
+// load 返回当前 hooks
+func (s *hookSet) load() []*userHook {
+	if p := s.hooks.Load(); p != nil {
+		return *p
+	}
+	return nil
 }
 
-// Sync 同步日志到存储
-// 如果写入器支持 Sync 方法则调用，否则返回 nil
+// store 替换 hooks 列表, 调用方持有 s.mu
+func (s *hookSet) store(hooks []*userHook) {
+	hasSync := false
+	for _, h := range hooks {
+		hasSync = hasSync || h.jobs == nil
+	}
+	s.hooks.Store(&hooks)
+	s.hasSync.Store(hasSync)
+}
+
+// run 依次执行关心该级别的 hooks
+//
+// 同步 hook 按注册顺序执行并可修改 entry, 任一 hook 返回 ErrDropEntry 时停止并丢弃日志;
+// 所有同步 hook 通过后再将条目副本交给异步 hook。
+//
+// 参数:
+//   - entry: 日志条目, 字段不得与其他日志共享
+//
 // 返回:
-//   - error: 同步过程中的错误
-func (h *levelHook) Sync() error {
-	if h.writer != nil {
-		if syncer, ok := h.writer.(interface{ Sync() error }); ok {
-			return syncer.Sync()
+//   - bool: 日志被丢弃时返回 false
+func (s *hookSet) run(entry *Entry) bool {
+	hooks := s.load()
+	for _, h := range hooks {
+		if h.jobs == nil && h.wants(entry.Level) && h.fire(entry) {
+			return false
 		}
 	}
-	return nil
+	for _, h := range hooks {
+		if h.jobs != nil && h.wants(entry.Level) {
+			h.dispatch(entry)
+		}
+	}
+	return true
+}
+
+// AddHook 注册日志钩子, 与父、子日志记录器共享
+//
+// 同步 hook 在级别和采样检查之后、格式化之前执行, 可修改或丢弃日志;
+// 异步 hook 收到通过同步 hook 后的日志副本。Sync 等待异步 hook 执行完已入队的日志,
+// Close 停止所有异步 hook, 两者最长等待 HookConfig.DrainTimeout。
+//
+// HookConfig.Timeout 只是传给 Fire 的 ctx 截止时间, 不会中断 Fire 也不会提前结束等待,
+// 可能长时间阻塞的 hook 应检查 ctx 或使用异步执行。
+//
+// hooks 与写入器一样由 With、Named 等创建的所有日志记录器共享, 在其中任意一个上调用 Close
+// 都会停止这些 hook, 因此只应关闭根日志记录器。
+//
+// 参数:
+//   - h: 日志钩子
+//   - cfg: 执行配置, nil 表示同步执行且不超时
+//
+// 示例:
+//
+//	// 丢弃健康检查日志
+//	logger.AddHook(filterHook{}, nil)
+//	// 后台发送告警, 通过 ctx 要求单次最长 2 秒
+//	logger.AddHook(alertHook{}, &fastlog.HookConfig{Async: true, Timeout: 2 * time.Second})
+func (l *Logger) AddHook(h Hook, cfg *HookConfig) {
+	uh := newUserHook(h, cfg)
+	s := l.userHooks
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.store(append(slices.Clone(s.load()), uh))
 }
 
-// Close 关闭写入器
+// RemoveHook 移除通过 AddHook 注册的钩子, 异步 hook 会先执行完已入队的日志 (最长等待 HookConfig.DrainTimeout)
+//
+// 参数:
+//   - h: 注册时传入的日志钩子, 须为可比较类型 (如指针)
+//
 // 返回:
-//   - error: 关闭过程中的错误
-func (h *levelHook) Close() error {
-	if h.writer != nil {
-		return h.writer.Close()
+//   - bool: 找到并移除时返回 true, h 为 nil 时返回 false
+func (l *Logger) RemoveHook(h Hook) bool {
+	if h == nil || !reflect.TypeOf(h).Comparable() {
+		return false
 	}
-	return nil
+	s := l.userHooks
+	s.mu.Lock()
+	hooks := s.load()
+	i := slices.IndexFunc(hooks, func(uh *userHook) bool { return uh.hook == h })
+	if i < 0 {
+		s.mu.Unlock()
+		return false
+	}
+	removed := hooks[i]
+	s.store(slices.Delete(slices.Clone(hooks), i, i+1))
+	s.mu.Unlock()
+
+	_ = removed.stop() // 超时后不再等待, 后台协程执行完剩余日志后退出
+	return true
 }
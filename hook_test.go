@@ -0,0 +1,198 @@
+package fastlog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// funcHook 由函数实现的测试 hook
+type funcHook struct {
+	levels []Level
+	fire   func(ctx context.Context, entry *Entry) error
+}
+
+func (h *funcHook) Levels() []Level { return h.levels }
+
+func (h *funcHook) Fire(ctx context.Context, entry *Entry) error { return h.fire(ctx, entry) }
+
+// newHookLogger 创建输出 "%level %msg %fields" 的同步日志记录器
+func newHookLogger(buf *bytes.Buffer) *Logger {
+	return New(&Config{Level: DEBUG, Outputs: []Output{{Writer: buf, Formatter: MustPattern("%level %msg%[ %fields%]")}}})
+}
+
+func TestAddHookModifyAndVeto(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := newHookLogger(buf).With(String("app", "svc"))
+
+	l.AddHook(&funcHook{fire: func(_ context.Context, e *Entry) error {
+		if e.Message == "healthz" {
+			return ErrDropEntry
+		}
+		e.Fields = append(e.Fields, String("host", "h1"))
+		return nil
+	}}, nil)
+	l.AddHook(&funcHook{levels: []Level{ERROR}, fire: func(_ context.Context, e *Entry) error {
+		e.Level, e.Message = WARN, "downgraded "+e.Message
+		return nil
+	}}, nil)
+
+	l.Info("healthz")
+	l.Info("ready")
+	l.Error("cache miss")
+	l.Info("again")
+	_ = l.Close()
+
+	want := "INFO ready app=svc host=h1\nWARN downgraded cache miss app=svc host=h1\nINFO again app=svc host=h1\n"
+	if got := buf.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestAddHookErrors(t *testing.T) {
+	var mu sync.Mutex
+	var errs []error
+	onError := func(_ Hook, _ *Entry, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		errs = append(errs, err)
+	}
+
+	buf := &bytes.Buffer{}
+	l := newHookLogger(buf)
+	boom := errors.New("boom")
+	l.AddHook(&funcHook{fire: func(context.Context, *Entry) error { return boom }}, &HookConfig{OnError: onError})
+	l.AddHook(&funcHook{fire: func(ctx context.Context, _ *Entry) error {
+		<-ctx.Done()
+		return nil
+	}}, &HookConfig{Timeout: 10 * time.Millisecond, OnError: onError})
+
+	l.Info("logged anyway")
+	_ = l.Close()
+
+	if !strings.Contains(buf.String(), "logged anyway") {
+		t.Errorf("hook errors should not drop the entry, output = %q", buf.String())
+	}
+	if len(errs) != 2 || !errors.Is(errs[0], boom) || !errors.Is(errs[1], ErrHookTimeout) {
+		t.Errorf("reported errors = %v", errs)
+	}
+}
+
+func TestAddHookTimeoutIgnored(t *testing.T) {
+	var errs []error
+	buf := &bytes.Buffer{}
+	l := newHookLogger(buf)
+
+	// Timeout 只是协作式的 ctx 截止时间: 忽略 ctx 的 hook 仍执行完毕, 修改照常生效
+	l.AddHook(&funcHook{fire: func(_ context.Context, e *Entry) error {
+		time.Sleep(50 * time.Millisecond)
+		e.Message = "late " + e.Message
+		return nil
+	}}, &HookConfig{Timeout: 5 * time.Millisecond, OnError: func(_ Hook, _ *Entry, err error) {
+		errs = append(errs, err)
+	}})
+
+	start := time.Now()
+	l.Info("entry")
+	if d := time.Since(start); d < 50*time.Millisecond {
+		t.Errorf("Info() returned after %v, before the hook finished", d)
+	}
+	_ = l.Close()
+
+	if got := buf.String(); got != "INFO late entry\n" {
+		t.Errorf("output = %q", got)
+	}
+	if len(errs) != 1 || !errors.Is(errs[0], ErrHookTimeout) {
+		t.Errorf("reported errors = %v", errs)
+	}
+}
+
+func TestAddHookAsync(t *testing.T) {
+	release := make(chan struct{})
+	var mu sync.Mutex
+	var got []string
+	h := &funcHook{fire: func(_ context.Context, e *Entry) error {
+		<-release
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, e.Message)
+		return ErrDropEntry // 异步 hook 不能丢弃日志
+	}}
+
+	var full int
+	buf := &bytes.Buffer{}
+	l := newHookLogger(buf)
+	l.AddHook(h, &HookConfig{Async: true, QueueSize: 2, OnError: func(_ Hook, _ *Entry, err error) {
+		if errors.Is(err, ErrHookQueueFull) {
+			full++
+		}
+	}})
+
+	// 第一条由后台协程取出并阻塞, 之后两条填满队列, 其余报告队列已满
+	l.Info("a")
+	waitFor(t, "hook worker to take the first entry", func() bool { return len(hookQueue(l, h)) == 0 })
+	for _, msg := range []string{"b", "c", "d"} {
+		l.Info(msg)
+	}
+	close(release)
+	_ = l.Sync()
+
+	mu.Lock()
+	if strings.Join(got, "") != "abc" || full != 1 {
+		t.Errorf("async hook got %q, queue full reported %d times", got, full)
+	}
+	mu.Unlock()
+	if buf.String() != "INFO a\nINFO b\nINFO c\nINFO d\n" {
+		t.Errorf("output = %q", buf.String())
+	}
+
+	if !l.RemoveHook(h) || l.RemoveHook(h) {
+		t.Error("RemoveHook() should remove the hook exactly once")
+	}
+	l.Info("e")
+	_ = l.Close()
+	if len(got) != 3 {
+		t.Errorf("removed hook fired for %q", got)
+	}
+}
+
+func TestAddHookDrainTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	h := &funcHook{fire: func(context.Context, *Entry) error {
+		<-release
+		return nil
+	}}
+
+	l := newHookLogger(&bytes.Buffer{})
+	l.AddHook(h, &HookConfig{Async: true, DrainTimeout: 20 * time.Millisecond})
+	l.Info("stuck")
+
+	start := time.Now()
+	if err := l.Sync(); !errors.Is(err, ErrHookTimeout) {
+		t.Errorf("Sync() error = %v, want ErrHookTimeout", err)
+	}
+	if err := l.Close(); !errors.Is(err, ErrHookTimeout) {
+		t.Errorf("Close() error = %v, want ErrHookTimeout", err)
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("Sync() and Close() took %v", d)
+	}
+	if l.RemoveHook(nil) {
+		t.Error("RemoveHook(nil) should return false")
+	}
+}
+
+// hookQueue 返回 hook 的异步队列, 用于等待后台协程取出任务
+func hookQueue(l *Logger, h Hook) chan hookJob {
+	for _, uh := range l.userHooks.load() {
+		if uh.hook == h {
+			return uh.jobs
+		}
+	}
+	return nil
+}
@@ -42,6 +42,14 @@ func (lw *logWriter) WriteHeader(code int) {
 
 // LogRequest 日志中间件，用于记录HTTP请求日志
 //
+// 中间件按 W3C Trace Context 关联链路：
+//   - 请求头 traceparent 合法时沿用其链路 ID，并为本次请求生成新的 Span ID
+//   - 请求头缺失或非法时生成新的链路
+//   - 请求上下文中已有链路信息（如外层中间件设置）时直接使用
+//
+// 链路信息通过 ContextWithTrace 存入请求上下文，处理器使用 r.Context() 调用 *Ctx 方法时
+// 自动携带 trace_id 和 span_id 字段；响应头 traceparent 返回本次请求的链路信息。
+//
 // 参数:
 //   - log: 日志实例
 //   - next: 下一个处理器
@@ -58,6 +66,22 @@ func LogRequest(log *Logger, next http.Handler) http.Handler {
 		// 日志前置操作：记录请求开始时间
 		startTime := time.Now()
 
+		// 关联链路：优先使用上下文中已有的链路，其次解析 traceparent 请求头
+		ctx := r.Context()
+		var parentSpan string
+		tc, ok := TraceFromContext(ctx)
+		if !ok {
+			if parent, err := ParseTraceparent(r.Header.Get(TraceparentHeader)); err == nil {
+				tc = parent.NewSpan()
+				parentSpan = parent.SpanIDString()
+			} else {
+				tc = NewTraceContext()
+			}
+			ctx = ContextWithTrace(ctx, tc)
+			r = r.WithContext(ctx)
+		}
+		w.Header().Set(TraceparentHeader, tc.String()) // 须在处理器写入响应前设置
+
 		// 从对象池获取 logWriter 实例
 		lw := getLogWriter(w)
 		defer putLogWriter(lw) // 确保请求处理完毕后将实例归还池中
@@ -68,8 +92,8 @@ func LogRequest(log *Logger, next http.Handler) http.Handler {
 		// 日志后置操作：计算耗时并打印日志
 		duration := time.Since(startTime)
 
-		// 打印HTTP日志
-		log.Infow("[HTTP LOG]",
+		// 打印HTTP日志，链路字段由上下文自动加入
+		fields := []Field{
 			String("method", r.Method),            // 请求方法
 			String("path", r.URL.Path),            // 请求路径
 			Int("status", lw.statusCode),          // HTTP状态码
@@ -77,6 +101,10 @@ func LogRequest(log *Logger, next http.Handler) http.Handler {
 			String("remote_addr", r.RemoteAddr),   // 客户端IP
 			String("user_agent", r.UserAgent()),   // User-Agent
 			Int64("content_len", r.ContentLength), // 请求体大小
-		)
+		}
+		if parentSpan != "" {
+			fields = append(fields, String("parent_span_id", parentSpan)) // 上游 Span ID
+		}
+		log.InfoCtx(ctx, "[HTTP LOG]", fields...)
 	})
 }
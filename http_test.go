@@ -1,9 +1,11 @@
 package fastlog
 
 import (
+	"bytes"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 )
@@ -78,3 +80,60 @@ func TestWithLog(t *testing.T) {
 
 	_ = log.Close()
 }
+
+func TestLogRequestTraceparent(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := New(&Config{OutputConsole: true, Formatter: &testFormatter{buf: buf}})
+
+	var inner TraceContext
+	handler := LogRequest(log, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		inner, _ = TraceFromContext(r.Context())
+		log.InfoCtx(r.Context(), "inside")
+	}))
+
+	t.Run("continue upstream trace", func(t *testing.T) {
+		buf.Reset()
+		req := httptest.NewRequest("GET", "/orders", nil)
+		req.Header.Set(TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		if inner.TraceIDString() != "4bf92f3577b34da6a3ce929d0e0e4736" || inner.SpanIDString() == "00f067aa0ba902b7" {
+			t.Errorf("handler trace = %s", inner)
+		}
+		if got := rr.Header().Get(TraceparentHeader); got != inner.String() {
+			t.Errorf("response traceparent = %q, want %q", got, inner.String())
+		}
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("log lines = %q", lines)
+		}
+		ids := "trace_id=4bf92f3577b34da6a3ce929d0e0e4736 span_id=" + inner.SpanIDString()
+		for _, line := range lines {
+			if !strings.Contains(line, ids) {
+				t.Errorf("line %q missing %q", line, ids)
+			}
+		}
+		if !strings.Contains(lines[1], "parent_span_id=00f067aa0ba902b7") {
+			t.Errorf("request log = %q", lines[1])
+		}
+	})
+
+	t.Run("start new trace", func(t *testing.T) {
+		buf.Reset()
+		req := httptest.NewRequest("GET", "/orders", nil)
+		req.Header.Set(TraceparentHeader, "garbage")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		if !inner.IsValid() || inner.TraceIDString() == "4bf92f3577b34da6a3ce929d0e0e4736" {
+			t.Errorf("handler trace = %s", inner)
+		}
+		if got, err := ParseTraceparent(rr.Header().Get(TraceparentHeader)); err != nil || got != inner {
+			t.Errorf("response traceparent = %v, %v", got, err)
+		}
+		if strings.Contains(buf.String(), "parent_span_id") {
+			t.Errorf("new trace should not log a parent span: %q", buf.String())
+		}
+	})
+}
@@ -0,0 +1,885 @@
+package fastlog
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// HTTPSinkFormat HTTP 批量发送的请求体格式
+type HTTPSinkFormat uint8
+
+const (
+	// HTTPJSONArray JSON 数组 (默认), 每条日志为数组中的一个元素, 要求格式化器输出 JSON 对象
+	HTTPJSONArray HTTPSinkFormat = iota
+
+	// HTTPLoki Loki push API 格式, 按标签分组为多个流
+	HTTPLoki
+
+	// HTTPElasticsearch Elasticsearch _bulk API 格式 (NDJSON), 每条日志前附加 index 操作行
+	HTTPElasticsearch
+
+	// HTTPOTLPJSON OpenTelemetry OTLP/HTTP 日志导出 (JSON 编码), URL 通常为 http://collector:4318/v1/logs
+	HTTPOTLPJSON
+
+	// HTTPOTLPProtobuf OpenTelemetry OTLP/HTTP 日志导出 (protobuf 编码)
+	HTTPOTLPProtobuf
+)
+
+// HTTP 批量发送默认值
+const (
+	DefaultHTTPBatchSize     = 500                    // 默认每批最大条数
+	DefaultHTTPBatchBytes    = 1 << 20                // 默认每批最大字节数 (1MB)
+	DefaultHTTPFlushInterval = time.Second            // 默认发送间隔
+	DefaultHTTPQueueSize     = 16                     // 默认待发送批次队列长度
+	DefaultHTTPMaxRetries    = 3                      // 默认最大重试次数
+	DefaultHTTPRetryBackoff  = 500 * time.Millisecond // 默认首次重试间隔
+	DefaultHTTPTimeout       = 10 * time.Second       // 默认请求超时
+	DefaultHTTPDrainTimeout  = 10 * time.Second       // 默认 Flush 和 Close 等待批次发送的最长时间
+)
+
+// errHTTPSinkClosed 写入已关闭的 HTTP 批量发送器
+var errHTTPSinkClosed = errors.New("http sink closed")
+
+// errHTTPDrainTimeout 在期限内未能发送完剩余批次
+var errHTTPDrainTimeout = errors.New("http sink batches not sent before drain timeout")
+
+// HTTPSinkConfig HTTP 批量发送配置
+//
+// 示例:
+//
+//	// Loki: 按级别和服务名分流
+//	cfg.HTTP = &fastlog.HTTPSinkConfig{
+//	    URL:          "http://loki:3100/loki/api/v1/push",
+//	    Format:       fastlog.HTTPLoki,
+//	    Formatter:    fastlog.Logfmt{},
+//	    LokiLabels:   []string{"level", "logger"},
+//	    StaticLabels: map[string]string{"app": "order"},
+//	}
+//
+//	// Elasticsearch: 写入 logs-app 索引
+//	cfg.HTTP = &fastlog.HTTPSinkConfig{
+//	    URL:     "http://es:9200/_bulk",
+//	    Format:  fastlog.HTTPElasticsearch,
+//	    ESIndex: "logs-app",
+//	    Gzip:    true,
+//	}
+//
+//	// OpenTelemetry Collector: OTLP/HTTP protobuf
+//	cfg.HTTP = &fastlog.HTTPSinkConfig{
+//	    URL:                "http://otel-collector:4318/v1/logs",
+//	    Format:             fastlog.HTTPOTLPProtobuf,
+//	    ServiceName:        "order",
+//	    ResourceAttributes: map[string]string{"deployment.environment": "prod"},
+//	}
+type HTTPSinkConfig struct {
+	// URL 接收日志的 HTTP 地址
+	URL string
+
+	// Format 请求体格式, 零值默认 HTTPJSONArray
+	Format HTTPSinkFormat
+
+	// Formatter 单条日志的格式化器, 零值默认 JSON{}
+	// HTTPJSONArray 和 HTTPElasticsearch 要求每条日志格式化为一个 JSON 对象; OTLP 格式忽略该配置
+	Formatter Formatter
+
+	// Level 发送的最低级别, 零值表示不额外过滤
+	Level Level
+
+	// ======== 批量配置 ========
+
+	// BatchSize 每批最大条数, 零值默认 500
+	BatchSize int
+
+	// BatchBytes 每批最大字节数 (未压缩), 零值默认 1MB
+	BatchBytes int
+
+	// FlushInterval 未满批次的发送间隔, 零值默认 1 秒
+	FlushInterval time.Duration
+
+	// QueueSize 待发送批次的队列长度, 队列已满时丢弃新批次, 零值默认 16
+	QueueSize int
+
+	// DrainTimeout Flush 和 Close 等待批次发送完成的最长时间, Close 超时后未发送的批次计入丢弃, 零值默认 10 秒
+	DrainTimeout time.Duration
+
+	// ======== 请求配置 ========
+
+	// Gzip 是否以 gzip 压缩请求体
+	Gzip bool
+
+	// Headers 自定义请求头, 如租户标识 X-Scope-OrgID
+	Headers map[string]string
+
+	// Username 和 Password 设置后使用 Basic 认证
+	Username string
+	Password string
+
+	// BearerToken 设置后使用 Bearer 认证, 与 Basic 认证同时设置时优先
+	BearerToken string
+
+	// MaxRetries 网络错误、429 和 5xx 响应的最大重试次数, 零值默认 3, 负数表示不重试
+	MaxRetries int
+
+	// RetryBackoff 首次重试前的等待时间, 之后每次翻倍, 零值默认 500 毫秒
+	RetryBackoff time.Duration
+
+	// Timeout 单次请求超时时间, 零值默认 10 秒, 设置 Client 时忽略
+	Timeout time.Duration
+
+	// Client 自定义 HTTP 客户端, 零值按 Timeout 创建
+	Client *http.Client
+
+	// ======== 格式专属配置 ========
+
+	// LokiLabels 作为 Loki 流标签的键: "level"、"logger" 或字段键名 (命名空间字段写作 ns.key)
+	// 条目缺少对应值时不设置该标签; 非法标签名字符替换为 '_'
+	LokiLabels []string
+
+	// StaticLabels Loki 流的固定标签, 如 app、env
+	StaticLabels map[string]string
+
+	// ESIndex Elasticsearch 索引名, 为空时由 URL 中的索引决定 (如 /logs-app/_bulk)
+	ESIndex string
+
+	// ServiceName OTLP 资源属性 service.name, 为空时使用程序名
+	ServiceName string
+
+	// ResourceAttributes OTLP 资源的其他属性, 如 service.version、deployment.environment
+	ResourceAttributes map[string]string
+
+	// TraceIDKey 和 SpanIDKey 映射到 OTLP 链路上下文的字段键名, 零值默认 "trace_id" 和 "span_id"
+	// 字段值须为十六进制字符串 (32 位和 16 位), 否则按普通属性输出
+	TraceIDKey string
+	SpanIDKey  string
+}
+
+// validate 验证 HTTP 批量发送配置
+//
+// 返回:
+//   - error: 验证通过时返回 nil, 否则返回错误信息
+func (c *HTTPSinkConfig) validate() error {
+	if c.URL == "" {
+		return errors.New("http sink url must be set")
+	}
+	if c.Format > HTTPOTLPProtobuf {
+		return fmt.Errorf("unsupported http sink format %d", c.Format)
+	}
+	if c.BatchSize < 0 || c.BatchBytes < 0 || c.QueueSize < 0 {
+		return errors.New("http sink batch and queue sizes must be >= 0")
+	}
+	if c.FlushInterval < 0 || c.RetryBackoff < 0 || c.Timeout < 0 || c.DrainTimeout < 0 {
+		return errors.New("http sink intervals must be >= 0")
+	}
+	return nil
+}
+
+// HTTPSinkStats HTTP 批量发送统计信息
+type HTTPSinkStats struct {
+	Sent    uint64 // 已成功发送的日志条数
+	Dropped uint64 // 因队列已满或重试耗尽而丢弃的日志条数
+	Retries uint64 // 重试请求次数
+	Batches uint64 // 已成功发送的批次数
+}
+
+// httpRecord 待发送的一条日志
+type httpRecord struct {
+	ts     time.Time // 时间戳, Loki 使用
+	stream string    // Loki 流标识 (规范化的标签集合), OTLP 格式下为日志记录器名称
+	line   []byte    // 格式化后的日志, 不含末尾换行
+}
+
+// httpBatch 一个待发送批次
+type httpBatch struct {
+	records []httpRecord        // 日志记录
+	bytes   int                 // 记录的总字节数
+	streams map[string][]string // Loki 流标识 → 标签键值对 (键、值交替)
+	done    chan struct{}       // 非 nil 时, 发送协程处理完该批次后关闭, 用于 Flush
+	stop    bool                // 为 true 时, 发送协程处理完该批次后退出
+}
+
+// HTTPSink HTTP 批量发送器
+//
+// 日志先追加到当前批次, 达到条数或字节上限、或经过 FlushInterval 后交给后台协程发送,
+// 记录日志的调用方不会等待网络请求。发送失败时对网络错误、429 和 5xx 响应按指数退避重试,
+// 重试耗尽后丢弃该批次并输出错误到 stderr。
+//
+// 既可作为 io.WriteCloser 单独使用 (每次 Write 为一条已格式化的日志, 仅携带固定标签),
+// 也可通过 Config.HTTP 接入日志记录器 (按 Formatter 重新格式化, 并从条目中提取 Loki 标签)。
+type HTTPSink struct {
+	url          string             // 目标地址
+	format       HTTPSinkFormat     // 请求体格式
+	formatter    Formatter          // 单条日志格式化器
+	level        Level              // 最低级别
+	batchSize    int                // 每批最大条数
+	batchBytes   int                // 每批最大字节数
+	interval     time.Duration      // 发送间隔
+	gzip         bool               // 是否压缩
+	headers      map[string]string  // 自定义请求头
+	username     string             // Basic 认证用户名
+	password     string             // Basic 认证密码
+	bearer       string             // Bearer 令牌
+	maxRetries   int                // 最大重试次数
+	retryBackoff time.Duration      // 首次重试间隔
+	drainTimeout time.Duration      // Flush 和 Close 等待批次发送的最长时间
+	client       *http.Client       // HTTP 客户端
+	labelKeys    []string           // Loki 动态标签的来源键
+	labelNames   []string           // Loki 动态标签名, 与 labelKeys 一一对应
+	staticLabels []string           // Loki 固定标签键值对, 按名称排序
+	esAction     []byte             // Elasticsearch 操作行 (含换行)
+	otlp         *otlpEncoder       // OTLP 编码器, 仅 OTLP 格式使用
+	contentType  string             // 请求体类型
+	batches      chan *httpBatch    // 待发送批次队列
+	mu           sync.Mutex         // 保护 cur 和 closed
+	cur          *httpBatch         // 当前批次
+	closed       bool               // 是否已关闭
+	done         chan struct{}      // 关闭信号, 停止定时发送
+	stopped      chan struct{}      // 发送协程已退出
+	abort        context.Context    // Close 超时后取消, 放弃剩余批次和进行中的请求
+	cancel       context.CancelFunc // 取消 abort
+	wg           sync.WaitGroup     // 等待定时发送协程退出
+	sent         atomic.Uint64      // 已发送条数
+	dropped      atomic.Uint64      // 丢弃条数
+	retries      atomic.Uint64      // 重试次数
+	sentBatches  atomic.Uint64      // 已发送批次数
+}
+
+// NewHTTPSink 创建 HTTP 批量发送器并启动后台发送协程
+//
+// 参数:
+//   - cfg: HTTP 批量发送配置
+//
+// 返回:
+//   - *HTTPSink: HTTP 批量发送器
+//   - error: 配置非法时返回
+func NewHTTPSink(cfg *HTTPSinkConfig) (*HTTPSink, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	s := &HTTPSink{
+		url:          cfg.URL,
+		format:       cfg.Format,
+		formatter:    cfg.Formatter,
+		level:        cfg.Level,
+		batchSize:    cfg.BatchSize,
+		batchBytes:   cfg.BatchBytes,
+		interval:     cfg.FlushInterval,
+		gzip:         cfg.Gzip,
+		headers:      cfg.Headers,
+		username:     cfg.Username,
+		password:     cfg.Password,
+		bearer:       cfg.BearerToken,
+		maxRetries:   cfg.MaxRetries,
+		retryBackoff: cfg.RetryBackoff,
+		drainTimeout: cfg.DrainTimeout,
+		client:       cfg.Client,
+		done:         make(chan struct{}),
+		stopped:      make(chan struct{}),
+	}
+
+	// 应用默认值
+	if s.formatter == nil {
+		s.formatter = JSON{}
+	}
+	if s.batchSize == 0 {
+		s.batchSize = DefaultHTTPBatchSize
+	}
+	if s.batchBytes == 0 {
+		s.batchBytes = DefaultHTTPBatchBytes
+	}
+	if s.interval == 0 {
+		s.interval = DefaultHTTPFlushInterval
+	}
+	if s.maxRetries == 0 {
+		s.maxRetries = DefaultHTTPMaxRetries
+	} else if s.maxRetries < 0 {
+		s.maxRetries = 0
+	}
+	if s.retryBackoff == 0 {
+		s.retryBackoff = DefaultHTTPRetryBackoff
+	}
+	if s.drainTimeout == 0 {
+		s.drainTimeout = DefaultHTTPDrainTimeout
+	}
+	s.abort, s.cancel = context.WithCancel(context.Background())
+	if s.client == nil {
+		timeout := cfg.Timeout
+		if timeout == 0 {
+			timeout = DefaultHTTPTimeout
+		}
+		s.client = &http.Client{Timeout: timeout}
+	}
+	queueSize := cfg.QueueSize
+	if queueSize == 0 {
+		queueSize = DefaultHTTPQueueSize
+	}
+	s.batches = make(chan *httpBatch, queueSize)
+
+	// 格式专属配置
+	switch s.format {
+	case HTTPLoki:
+		s.contentType = "application/json"
+		for _, key := range cfg.LokiLabels {
+			s.labelKeys = append(s.labelKeys, key)
+			s.labelNames = append(s.labelNames, lokiLabelName(key))
+		}
+		names := make([]string, 0, len(cfg.StaticLabels))
+		for name := range cfg.StaticLabels {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			s.staticLabels = append(s.staticLabels, lokiLabelName(name), cfg.StaticLabels[name])
+		}
+	case HTTPElasticsearch:
+		s.contentType = "application/x-ndjson"
+		if cfg.ESIndex == "" {
+			s.esAction = []byte("{\"index\":{}}\n")
+		} else {
+			s.esAction = append([]byte(`{"index":{"_index":`), appendJSONString(nil, cfg.ESIndex)...)
+			s.esAction = append(s.esAction, "}}\n"...)
+		}
+	case HTTPOTLPJSON:
+		s.contentType = "application/json"
+		s.otlp = newOTLPEncoder(cfg)
+	case HTTPOTLPProtobuf:
+		s.contentType = "application/x-protobuf"
+		s.otlp = newOTLPEncoder(cfg)
+	default:
+		s.contentType = "application/json"
+	}
+
+	s.cur = s.newBatch()
+	s.wg.Add(1)
+	go s.tick()
+	go s.run()
+	return s, nil
+}
+
+// newBatch 创建空批次
+func (s *HTTPSink) newBatch() *httpBatch {
+	b := &httpBatch{}
+	if s.format == HTTPLoki {
+		b.streams = make(map[string][]string)
+	}
+	return b
+}
+
+// Write 追加一条已格式化的日志, 末尾的换行符会被去除
+//
+// Loki 格式下仅携带固定标签, 时间戳为写入时间。
+// OTLP 格式下整行作为 LogRecord 的 body, 不设置严重性。
+//
+// 参数:
+//   - p: 格式化后的日志
+//
+// 返回:
+//   - int: 成功时返回 len(p)
+//   - error: 发送器已关闭时返回错误
+func (s *HTTPSink) Write(p []byte) (int, error) {
+	line := bytes.TrimSuffix(p, []byte{'\n'})
+	rec := httpRecord{ts: time.Now(), line: append([]byte(nil), line...)}
+	if s.otlp != nil {
+		rec.line = s.otlp.appendRecord(nil, &Entry{Time: rec.ts, Message: string(line)}, rec.ts)
+	}
+	if err := s.add(rec, s.staticLabels); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// fire 按发送器的格式化器 (OTLP 格式为 LogRecord 编码) 格式化日志条目并追加到当前批次
+//
+// 参数:
+//   - entry: 日志条目
+//
+// 返回:
+//   - error: 格式化失败或发送器已关闭时返回
+func (s *HTTPSink) fire(entry *Entry) error {
+	if !s.level.Enabled(entry.Level) {
+		return nil
+	}
+	if s.otlp != nil {
+		rec := httpRecord{ts: entry.Time, stream: entry.Logger, line: s.otlp.appendRecord(nil, entry, time.Now())}
+		return s.add(rec, nil)
+	}
+	var line []byte
+	var err error
+	if af, ok := s.formatter.(AppendFormatter); ok {
+		line, err = af.AppendFormat(nil, entry)
+	} else {
+		line, err = s.formatter.Format(entry)
+	}
+	if err != nil {
+		return err
+	}
+	rec := httpRecord{ts: entry.Time, line: bytes.TrimSuffix(line, []byte{'\n'})}
+	return s.add(rec, s.entryLabels(entry))
+}
+
+// entryLabels 提取日志条目的 Loki 标签键值对 (固定标签 + 动态标签)
+func (s *HTTPSink) entryLabels(entry *Entry) []string {
+	if s.format != HTTPLoki || len(s.labelKeys) == 0 {
+		return s.staticLabels
+	}
+	labels := append([]string(nil), s.staticLabels...)
+	for i, key := range s.labelKeys {
+		var value string
+		switch key {
+		case "level":
+			value = entry.Level.String()
+		case "logger":
+			value = entry.Logger
+		default:
+			if f, ok := lookupField(entry.Fields, key); ok {
+				value = f.valueWithTimeFormat(entry.TimeFormat)
+			}
+		}
+		if value != "" {
+			labels = append(labels, s.labelNames[i], value)
+		}
+	}
+	return labels
+}
+
+// add 追加一条记录, 当前批次已满时交给发送协程
+//
+// 参数:
+//   - rec: 日志记录
+//   - labels: Loki 标签键值对, 其他格式忽略
+//
+// 返回:
+//   - error: 发送器已关闭时返回
+func (s *HTTPSink) add(rec httpRecord, labels []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return errHTTPSinkClosed
+	}
+	if s.format == HTTPLoki {
+		rec.stream = lokiStreamKey(labels)
+		if _, ok := s.cur.streams[rec.stream]; !ok {
+			s.cur.streams[rec.stream] = sortLabelPairs(labels)
+		}
+	}
+	s.cur.records = append(s.cur.records, rec)
+	s.cur.bytes += len(rec.line)
+	if len(s.cur.records) >= s.batchSize || s.cur.bytes >= s.batchBytes {
+		s.enqueueLocked()
+	}
+	return nil
+}
+
+// enqueueLocked 将当前批次交给发送协程, 队列已满时丢弃 (调用方需持有锁)
+func (s *HTTPSink) enqueueLocked() {
+	if len(s.cur.records) == 0 {
+		return
+	}
+	select {
+	case s.batches <- s.cur:
+	default:
+		s.dropped.Add(uint64(len(s.cur.records)))
+	}
+	s.cur = s.newBatch()
+}
+
+// tick 定时发送未满的批次
+func (s *HTTPSink) tick() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			s.mu.Lock()
+			if !s.closed {
+				s.enqueueLocked()
+			}
+			s.mu.Unlock()
+		}
+	}
+}
+
+// run 后台发送协程, 按顺序发送批次, Close 超时后剩余批次直接丢弃
+func (s *HTTPSink) run() {
+	defer close(s.stopped)
+	for b := range s.batches {
+		if len(b.records) > 0 {
+			if s.abort.Err() == nil {
+				s.send(b)
+			} else {
+				s.dropped.Add(uint64(len(b.records)))
+			}
+		}
+		if b.done != nil {
+			close(b.done)
+		}
+		if b.stop {
+			return
+		}
+	}
+}
+
+// send 编码并发送一个批次, 按需重试
+func (s *HTTPSink) send(b *httpBatch) {
+	body := s.encode(b)
+	if s.gzip {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		_, _ = zw.Write(body)
+		_ = zw.Close()
+		body = buf.Bytes()
+	}
+
+	backoff := s.retryBackoff
+	for attempt := 0; ; attempt++ {
+		err := s.post(body)
+		if err == nil {
+			s.sent.Add(uint64(len(b.records)))
+			s.sentBatches.Add(1)
+			return
+		}
+		var se *httpStatusError
+		retryable := (!errors.As(err, &se) || se.retryable()) && s.abort.Err() == nil
+		if !retryable || attempt >= s.maxRetries {
+			s.dropped.Add(uint64(len(b.records)))
+			_, _ = fmt.Fprintf(os.Stderr, "http sink error: dropped %d entries: %v\n", len(b.records), err)
+			return
+		}
+		s.retries.Add(1)
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-s.abort.Done():
+			timer.Stop()
+		}
+		backoff *= 2
+	}
+}
+
+// httpStatusError 非 2xx 响应
+type httpStatusError struct {
+	code int    // 状态码
+	body string // 响应体开头, 便于排查
+}
+
+// Error 实现 error 接口
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("unexpected status %d: %s", e.code, e.body)
+}
+
+// retryable 429 和 5xx 响应可重试
+func (e *httpStatusError) retryable() bool {
+	return e.code == http.StatusTooManyRequests || e.code >= 500
+}
+
+// post 发送一次请求
+func (s *HTTPSink) post(body []byte) error {
+	req, err := http.NewRequestWithContext(s.abort, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", s.contentType)
+	if s.gzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	for k, v := range s.headers {
+		req.Header.Set(k, v)
+	}
+	switch {
+	case s.bearer != "":
+		req.Header.Set("Authorization", "Bearer "+s.bearer)
+	case s.username != "" || s.password != "":
+		req.SetBasicAuth(s.username, s.password)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+	return &httpStatusError{code: resp.StatusCode, body: string(bytes.TrimSpace(msg))}
+}
+
+// encode 按格式编码批次请求体
+func (s *HTTPSink) encode(b *httpBatch) []byte {
+	body := make([]byte, 0, b.bytes+len(b.records)*(len(s.esAction)+2)+64)
+	switch s.format {
+	case HTTPElasticsearch:
+		for _, rec := range b.records {
+			body = append(body, s.esAction...)
+			body = append(body, rec.line...)
+			body = append(body, '\n')
+		}
+	case HTTPLoki:
+		body = s.encodeLoki(body, b)
+	case HTTPOTLPJSON, HTTPOTLPProtobuf:
+		body = s.otlp.appendBatch(body, b.records)
+	default:
+		body = append(body, '[')
+		for i, rec := range b.records {
+			if i > 0 {
+				body = append(body, ',')
+			}
+			body = append(body, rec.line...)
+		}
+		body = append(body, ']')
+	}
+	return body
+}
+
+// encodeLoki 编码 Loki push 请求体, 流按首次出现的顺序排列, 流内日志保持写入顺序
+//
+//	{"streams":[{"stream":{"level":"INFO"},"values":[["1736937045000000000","line"]]}]}
+func (s *HTTPSink) encodeLoki(body []byte, b *httpBatch) []byte {
+	order := make([]string, 0, len(b.streams))
+	grouped := make(map[string][]httpRecord, len(b.streams))
+	for _, rec := range b.records {
+		if _, ok := grouped[rec.stream]; !ok {
+			order = append(order, rec.stream)
+		}
+		grouped[rec.stream] = append(grouped[rec.stream], rec)
+	}
+
+	body = append(body, `{"streams":[`...)
+	for i, key := range order {
+		if i > 0 {
+			body = append(body, ',')
+		}
+		body = append(body, `{"stream":{`...)
+		labels := b.streams[key]
+		for j := 0; j+1 < len(labels); j += 2 {
+			if j > 0 {
+				body = append(body, ',')
+			}
+			body = appendJSONString(body, labels[j])
+			body = append(body, ':')
+			body = appendJSONString(body, labels[j+1])
+		}
+		body = append(body, `},"values":[`...)
+		for j, rec := range grouped[key] {
+			if j > 0 {
+				body = append(body, ',')
+			}
+			body = append(body, `["`...)
+			body = strconv.AppendInt(body, rec.ts.UnixNano(), 10)
+			body = append(body, `",`...)
+			body = appendJSONString(body, string(rec.line))
+			body = append(body, ']')
+		}
+		body = append(body, "]}"...)
+	}
+	return append(body, "]}"...)
+}
+
+// sortLabelPairs 复制并按标签名排序标签键值对, 同名标签保留最后一个
+func sortLabelPairs(labels []string) []string {
+	m := make(map[string]string, len(labels)/2)
+	for i := 0; i+1 < len(labels); i += 2 {
+		m[labels[i]] = labels[i+1]
+	}
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	pairs := make([]string, 0, len(names)*2)
+	for _, name := range names {
+		pairs = append(pairs, name, m[name])
+	}
+	return pairs
+}
+
+// lokiStreamKey 返回标签集合的规范化标识, 与标签顺序无关
+func lokiStreamKey(labels []string) string {
+	pairs := sortLabelPairs(labels)
+	var buf []byte
+	for i := 0; i+1 < len(pairs); i += 2 {
+		buf = append(buf, pairs[i]...)
+		buf = append(buf, '=')
+		buf = strconv.AppendQuote(buf, pairs[i+1])
+		buf = append(buf, ',')
+	}
+	return string(buf)
+}
+
+// lokiLabelName 将键名转换为合法的 Loki 标签名: [a-zA-Z_][a-zA-Z0-9_]*
+func lokiLabelName(key string) string {
+	if key == "" {
+		return "_"
+	}
+	b := []byte(key)
+	for i, c := range b {
+		valid := c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (i > 0 && c >= '0' && c <= '9')
+		if !valid {
+			b[i] = '_'
+		}
+	}
+	return string(b)
+}
+
+// Flush 发送当前批次并等待之前的所有批次处理完成 (成功或重试耗尽)
+//
+// 入队和等待合计最多持续 DrainTimeout: 队列在期限内仍无空间时当前批次计入丢弃,
+// 等待超时时已入队的批次仍由发送协程继续发送。
+//
+// 返回:
+//   - error: 发送器已关闭或等待超时时返回
+func (s *HTTPSink) Flush() error {
+	timer := time.NewTimer(s.drainTimeout)
+	defer timer.Stop()
+
+	// 持锁入队, 与 Close 互斥, 停止批次之后不会再有批次入队
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return errHTTPSinkClosed
+	}
+	b := s.cur
+	b.done = make(chan struct{})
+	select {
+	case s.batches <- b:
+	case <-timer.C:
+		s.dropped.Add(uint64(len(b.records)))
+		s.cur = s.newBatch()
+		s.mu.Unlock()
+		return errHTTPDrainTimeout
+	}
+	s.cur = s.newBatch()
+	s.mu.Unlock()
+
+	select {
+	case <-b.done:
+		return nil
+	case <-s.stopped:
+		return errHTTPSinkClosed
+	case <-timer.C:
+		return errHTTPDrainTimeout
+	}
+}
+
+// Stats 返回统计信息快照
+//
+// 返回:
+//   - HTTPSinkStats: 统计信息
+func (s *HTTPSink) Stats() HTTPSinkStats {
+	return HTTPSinkStats{
+		Sent:    s.sent.Load(),
+		Dropped: s.dropped.Load(),
+		Retries: s.retries.Load(),
+		Batches: s.sentBatches.Load(),
+	}
+}
+
+// Close 发送剩余日志并关闭发送器
+//
+// 等待队列中的批次全部处理完成 (成功或重试耗尽) 后返回, 之后的写入返回错误。
+// 超过 DrainTimeout 时取消进行中的请求, 尚未发送的批次计入丢弃。
+//
+// 返回:
+//   - error: 超时返回错误, 重复关闭时返回 nil
+func (s *HTTPSink) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	b := s.cur
+	s.cur = nil
+	s.mu.Unlock()
+
+	// 先停止定时发送, 再送出最后一个批次并等待发送协程退出
+	close(s.done)
+	s.wg.Wait()
+	timer := time.NewTimer(s.drainTimeout)
+	defer timer.Stop()
+
+	// 超时后取消请求, 发送协程随即丢弃剩余批次并腾出队列
+	var err error
+	b.stop = true
+	select {
+	case s.batches <- b:
+	case <-timer.C:
+		s.cancel()
+		err = errHTTPDrainTimeout
+		s.batches <- b
+	}
+	if err == nil {
+		select {
+		case <-s.stopped:
+		case <-timer.C:
+			s.cancel()
+			err = errHTTPDrainTimeout
+		}
+	}
+	<-s.stopped
+	s.cancel()
+	return err
+}
+
+// httpSinkHook 将日志批量发送到 HTTP 地址的钩子（内部使用）
+type httpSinkHook struct {
+	sink *HTTPSink // HTTP 批量发送器
+}
+
+// Fire 按发送器的格式化器重新格式化日志条目并追加到当前批次
+//
+// 参数:
+//   - entry: 日志条目
+//   - data: 主输出格式化后的日志数据 (未使用)
+//
+// 返回:
+//   - error: 格式化失败或发送器已关闭时返回
+func (h *httpSinkHook) Fire(entry *Entry, data []byte) error {
+	return h.sink.fire(entry)
+}
+
+// Levels 返回关心的级别
+//
+// 返回:
+//   - []Level: 不低于最低级别的所有级别
+func (h *httpSinkHook) Levels() []Level {
+	var levels []Level
+	for _, lvl := range AllLevels() {
+		if h.sink.level.Enabled(lvl) {
+			levels = append(levels, lvl)
+		}
+	}
+	return levels
+}
+
+// Sync 发送当前批次并等待发送完成
+//
+// 返回:
+//   - error: 发送器已关闭时返回
+func (h *httpSinkHook) Sync() error {
+	return h.sink.Flush()
+}
+
+// Close 发送剩余日志并关闭发送器
+//
+// 返回:
+//   - error: 关闭过程中的错误
+func (h *httpSinkHook) Close() error {
+	return h.sink.Close()
+}
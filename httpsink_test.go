@@ -0,0 +1,313 @@
+package fastlog
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// sinkRequest 记录的一次请求
+type sinkRequest struct {
+	header http.Header
+	body   []byte
+}
+
+// sinkServer 记录请求的测试服务端, status 依次作为响应状态码, 用完后返回 200
+type sinkServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []sinkRequest
+	status   []int
+}
+
+// newSinkServer 创建测试服务端
+func newSinkServer(t *testing.T, status ...int) *sinkServer {
+	s := &sinkServer{status: status}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			zr, err := gzip.NewReader(bytes.NewReader(body))
+			if err != nil {
+				t.Errorf("invalid gzip body: %v", err)
+				return
+			}
+			body, _ = io.ReadAll(zr)
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.requests = append(s.requests, sinkRequest{header: r.Header.Clone(), body: body})
+		if len(s.status) > 0 {
+			code := s.status[0]
+			s.status = s.status[1:]
+			w.WriteHeader(code)
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// received 返回已记录的请求
+func (s *sinkServer) received() []sinkRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]sinkRequest(nil), s.requests...)
+}
+
+func TestHTTPSinkJSONArray(t *testing.T) {
+	srv := newSinkServer(t)
+	l := New(&Config{HTTP: &HTTPSinkConfig{URL: srv.URL, BatchSize: 2, Headers: map[string]string{"X-Tenant": "t1"}}})
+
+	l.Info("one")
+	l.Info("two")
+	l.Warnw("three", Int("n", 3))
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	reqs := srv.received()
+	if len(reqs) != 2 {
+		t.Fatalf("requests = %d, want 2 (full batch + flush on close)", len(reqs))
+	}
+	var msgs []string
+	for _, r := range reqs {
+		if r.header.Get("Content-Type") != "application/json" || r.header.Get("X-Tenant") != "t1" {
+			t.Errorf("headers = %v", r.header)
+		}
+		var docs []map[string]interface{}
+		if err := json.Unmarshal(r.body, &docs); err != nil {
+			t.Fatalf("body is not a JSON array: %v\n%s", err, r.body)
+		}
+		for _, d := range docs {
+			msgs = append(msgs, d["message"].(string))
+		}
+	}
+	if strings.Join(msgs, ",") != "one,two,three" {
+		t.Errorf("messages = %v", msgs)
+	}
+}
+
+func TestHTTPSinkLoki(t *testing.T) {
+	srv := newSinkServer(t)
+	l := New(&Config{HTTP: &HTTPSinkConfig{
+		URL:          srv.URL,
+		Format:       HTTPLoki,
+		Formatter:    Logfmt{},
+		LokiLabels:   []string{"level", "logger", "req.tenant"},
+		StaticLabels: map[string]string{"app": "shop"},
+		Gzip:         true,
+		BearerToken:  "secret",
+	}})
+
+	l.Named("api").Info("a")
+	l.Named("api").Info("b")
+	l.Named("db").Errorw("c", Namespace("req"), String("tenant", "acme"))
+	_ = l.Close()
+
+	reqs := srv.received()
+	if len(reqs) != 1 {
+		t.Fatalf("requests = %d, want 1", len(reqs))
+	}
+	if reqs[0].header.Get("Authorization") != "Bearer secret" || reqs[0].header.Get("Content-Encoding") != "gzip" {
+		t.Errorf("headers = %v", reqs[0].header)
+	}
+
+	var push struct {
+		Streams []struct {
+			Stream map[string]string `json:"stream"`
+			Values [][2]string       `json:"values"`
+		} `json:"streams"`
+	}
+	if err := json.Unmarshal(reqs[0].body, &push); err != nil {
+		t.Fatalf("invalid Loki body: %v\n%s", err, reqs[0].body)
+	}
+	if len(push.Streams) != 2 {
+		t.Fatalf("streams = %+v, want 2", push.Streams)
+	}
+	api, db := push.Streams[0], push.Streams[1]
+	if api.Stream["app"] != "shop" || api.Stream["level"] != "INFO" || api.Stream["logger"] != "api" || len(api.Values) != 2 {
+		t.Errorf("api stream = %+v", api)
+	}
+	if db.Stream["level"] != "ERROR" || db.Stream["req_tenant"] != "acme" || len(db.Values) != 1 {
+		t.Errorf("db stream = %+v", db)
+	}
+	if !strings.Contains(api.Values[1][1], "message=b") || len(api.Values[0][0]) < 19 {
+		t.Errorf("api values = %v", api.Values)
+	}
+}
+
+func TestHTTPSinkElasticsearch(t *testing.T) {
+	srv := newSinkServer(t)
+	sink, err := NewHTTPSink(&HTTPSinkConfig{URL: srv.URL, Format: HTTPElasticsearch, ESIndex: "logs-app", Username: "u", Password: "p"})
+	if err != nil {
+		t.Fatalf("NewHTTPSink() error = %v", err)
+	}
+	_, _ = sink.Write([]byte(`{"message":"x"}` + "\n"))
+	_, _ = sink.Write([]byte(`{"message":"y"}` + "\n"))
+	if err := sink.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	reqs := srv.received()
+	if len(reqs) != 1 {
+		t.Fatalf("requests = %d, want 1", len(reqs))
+	}
+	want := `{"index":{"_index":"logs-app"}}` + "\n" + `{"message":"x"}` + "\n" + `{"index":{"_index":"logs-app"}}` + "\n" + `{"message":"y"}` + "\n"
+	if string(reqs[0].body) != want {
+		t.Errorf("bulk body =\n%s\nwant\n%s", reqs[0].body, want)
+	}
+	if user, pass, ok := (&http.Request{Header: reqs[0].header}).BasicAuth(); !ok || user != "u" || pass != "p" {
+		t.Errorf("basic auth = %q %q %v", user, pass, ok)
+	}
+	if reqs[0].header.Get("Content-Type") != "application/x-ndjson" {
+		t.Errorf("content type = %q", reqs[0].header.Get("Content-Type"))
+	}
+
+	_ = sink.Close()
+	if _, err := sink.Write([]byte("late")); err == nil {
+		t.Error("Write() after Close should fail")
+	}
+}
+
+func TestHTTPSinkRetry(t *testing.T) {
+	t.Run("retry then succeed", func(t *testing.T) {
+		srv := newSinkServer(t, http.StatusServiceUnavailable, http.StatusTooManyRequests)
+		sink, _ := NewHTTPSink(&HTTPSinkConfig{URL: srv.URL, RetryBackoff: time.Millisecond})
+		_, _ = sink.Write([]byte(`{}`))
+		_ = sink.Close()
+		if st := sink.Stats(); st.Sent != 1 || st.Retries != 2 || st.Dropped != 0 || len(srv.received()) != 3 {
+			t.Errorf("stats = %+v, requests = %d", st, len(srv.received()))
+		}
+	})
+
+	t.Run("client error not retried", func(t *testing.T) {
+		srv := newSinkServer(t, http.StatusBadRequest)
+		sink, _ := NewHTTPSink(&HTTPSinkConfig{URL: srv.URL, RetryBackoff: time.Millisecond})
+		_, _ = sink.Write([]byte(`{}`))
+		_ = sink.Close()
+		if st := sink.Stats(); st.Sent != 0 || st.Retries != 0 || st.Dropped != 1 {
+			t.Errorf("stats = %+v", st)
+		}
+	})
+
+	t.Run("retries exhausted", func(t *testing.T) {
+		srv := newSinkServer(t, 500, 500, 500)
+		sink, _ := NewHTTPSink(&HTTPSinkConfig{URL: srv.URL, RetryBackoff: time.Millisecond, MaxRetries: 2})
+		_, _ = sink.Write([]byte(`{}`))
+		_ = sink.Close()
+		if st := sink.Stats(); st.Dropped != 1 || st.Retries != 2 || len(srv.received()) != 3 {
+			t.Errorf("stats = %+v, requests = %d", st, len(srv.received()))
+		}
+	})
+}
+
+func TestHTTPSinkFlushInterval(t *testing.T) {
+	srv := newSinkServer(t)
+	sink, _ := NewHTTPSink(&HTTPSinkConfig{URL: srv.URL, FlushInterval: 10 * time.Millisecond})
+	defer func() { _ = sink.Close() }()
+
+	_, _ = sink.Write([]byte(`{"n":1}`))
+	waitFor(t, "timed flush", func() bool { return len(srv.received()) == 1 })
+	if got := string(srv.received()[0].body); got != `[{"n":1}]` {
+		t.Errorf("body = %s", got)
+	}
+}
+
+func TestHTTPSinkCloseDrainTimeout(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{}, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	sink, _ := NewHTTPSink(&HTTPSinkConfig{URL: srv.URL, BatchSize: 1, QueueSize: 1, FlushInterval: time.Hour, DrainTimeout: 50 * time.Millisecond})
+	_, _ = sink.Write([]byte(`{"n":1}`))
+	<-started // 第一批卡在请求中
+	_, _ = sink.Write([]byte(`{"n":2}`))
+
+	// 队列已满, Flush 阻塞在入队上, 超过期限后丢弃当前批次, Close 随后开始
+	flushed := make(chan error, 1)
+	go func() { flushed <- sink.Flush() }()
+
+	start := time.Now()
+	if err := sink.Close(); !errors.Is(err, errHTTPDrainTimeout) {
+		t.Errorf("Close() error = %v, want drain timeout", err)
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("Close() took %v", d)
+	}
+	select {
+	case <-flushed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Flush() still blocked after Close")
+	}
+	if st := sink.Stats(); st.Sent != 0 || st.Dropped != 2 {
+		t.Errorf("stats = %+v", st)
+	}
+}
+
+func TestHTTPSinkFlushDeadline(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	sink, _ := NewHTTPSink(&HTTPSinkConfig{URL: srv.URL, BatchSize: 1, QueueSize: 1, FlushInterval: time.Hour, DrainTimeout: 50 * time.Millisecond})
+	defer func() { _ = sink.Close() }()
+
+	// 端点不响应时 Flush 在期限内返回, 不等待整个请求和重试过程
+	_, _ = sink.Write([]byte(`{"n":1}`))
+	for _, name := range []string{"wait", "enqueue"} {
+		start := time.Now()
+		if err := sink.Flush(); !errors.Is(err, errHTTPDrainTimeout) {
+			t.Errorf("%s: Flush() error = %v, want drain timeout", name, err)
+		}
+		if d := time.Since(start); d > 5*time.Second {
+			t.Errorf("%s: Flush() took %v", name, d)
+		}
+	}
+}
+
+func TestConfigHTTPValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		http    *HTTPSinkConfig
+		wantErr bool
+	}{
+		{"ok", &HTTPSinkConfig{URL: "http://localhost"}, false},
+		{"missing url", &HTTPSinkConfig{}, true},
+		{"bad format", &HTTPSinkConfig{URL: "http://x", Format: 9}, true},
+		{"negative batch", &HTTPSinkConfig{URL: "http://x", BatchSize: -1}, true},
+		{"negative interval", &HTTPSinkConfig{URL: "http://x", FlushInterval: -time.Second}, true},
+		{"negative drain timeout", &HTTPSinkConfig{URL: "http://x", DrainTimeout: -time.Second}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{HTTP: tt.http}
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
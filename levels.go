@@ -0,0 +1,247 @@
+package fastlog
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// maxLevels 可注册的级别总数上限 (含 6 个内置级别)
+const maxLevels = 32
+
+// levelRankStep 默认位次与级别数值的倍数, 内置级别的位次为 DEBUG=100 到 PANIC=600,
+// 留出的间隔供自定义级别通过 LevelSpec.Rank 插入内置级别之间
+const levelRankStep = 100
+
+// LevelSpec 级别定义, 用于注册自定义级别
+//
+// 级别的严重程度由位次 (Rank) 决定, 日志级别、输出级别和路由范围都按位次比较。
+// 内置级别为 DEBUG=1 到 PANIC=6, 位次为数值 × 100; 零值保留表示未设置。
+// 自定义级别使用其余数值, 默认位次同样为数值 × 100, 如 TRACE=-1 低于 DEBUG、AUDIT=10 高于 PANIC;
+// 需要插在内置级别之间时显式指定位次, 如 NOTICE=7 取位次 250, 介于 INFO 和 WARN 之间。
+type LevelSpec struct {
+	// Level 级别数值, 不能为 0 且未被占用
+	Level Level
+
+	// Rank 排序位次, 越大越严重, 不能与已注册级别重复, 零值为 Level × 100
+	Rank int
+
+	// Name 级别名称, 如 "TRACE", 注册时转为大写, 用于格式化输出和 ParseLevel
+	Name string
+
+	// Style 彩色输出样式, 用于 DefaultTheme 和 CapitalColorLevelEncoder, 零值不着色
+	Style Style
+
+	// FileName 级别路由的文件名, 零值为 Name + ".log"
+	FileName string
+
+	// Always 为 true 时该级别不受日志级别、名称级别、输出级别和采样过滤, 始终输出, 如审计日志
+	Always bool
+}
+
+// levelRegistry 已注册的级别（内部使用）, 注册时整体替换, 读取无锁
+type levelRegistry struct {
+	specs  []LevelSpec      // 按注册顺序排列, 下标 + 1 为级别的槽位编号
+	slots  map[Level]int    // 级别 → 槽位编号 (从 1 开始)
+	names  map[string]Level // 大写名称 → 级别
+	ranks  map[Level]int    // 级别 → 位次
+	sorted []Level          // 按位次升序排列的级别
+	always bool             // 是否存在 Always 级别
+}
+
+var (
+	levelsMu sync.Mutex    // 串行化注册
+	levels   = newLevels() // 当前级别注册表
+)
+
+// newLevels 创建只包含内置级别的注册表
+//
+// 以变量初始化而非 init 函数的方式创建, 使其他包级变量在初始化时也能使用级别名称。
+func newLevels() *atomic.Pointer[levelRegistry] {
+	reg := &levelRegistry{}
+	for _, spec := range []LevelSpec{
+		{Level: DEBUG, Name: LevelNameDebug, Style: Style{Fg: ColorCyan, Bold: true}},
+		{Level: INFO, Name: LevelNameInfo, Style: Style{Fg: ColorBlue, Bold: true}},
+		{Level: WARN, Name: LevelNameWarn, Style: Style{Fg: ColorYellow, Bold: true}},
+		{Level: ERROR, Name: LevelNameError, Style: Style{Fg: ColorRed, Bold: true}},
+		{Level: FATAL, Name: LevelNameFatal, Style: Style{Fg: ColorRed, Bold: true}},
+		{Level: PANIC, Name: LevelNamePanic, Style: Style{Fg: ColorMagenta, Bold: true}},
+	} {
+		reg = reg.with(spec)
+	}
+	p := &atomic.Pointer[levelRegistry]{}
+	p.Store(reg)
+	return p
+}
+
+// with 返回追加了一个级别的新注册表
+func (r *levelRegistry) with(spec LevelSpec) *levelRegistry {
+	if spec.FileName == "" {
+		spec.FileName = spec.Name + ".log"
+	}
+	if spec.Rank == 0 {
+		spec.Rank = int(spec.Level) * levelRankStep
+	}
+	n := &levelRegistry{
+		specs:  append(append([]LevelSpec(nil), r.specs...), spec),
+		slots:  make(map[Level]int, len(r.specs)+1),
+		names:  make(map[string]Level, len(r.specs)+1),
+		ranks:  make(map[Level]int, len(r.specs)+1),
+		always: r.always || spec.Always,
+	}
+	for i, s := range n.specs {
+		n.slots[s.Level] = i + 1
+		n.names[s.Name] = s.Level
+		n.ranks[s.Level] = s.Rank
+		n.sorted = append(n.sorted, s.Level)
+	}
+	// 插入排序, 级别数量很少
+	for i := 1; i < len(n.sorted); i++ {
+		for j := i; j > 0 && n.ranks[n.sorted[j]] < n.ranks[n.sorted[j-1]]; j-- {
+			n.sorted[j], n.sorted[j-1] = n.sorted[j-1], n.sorted[j]
+		}
+	}
+	return n
+}
+
+// RegisterLevel 注册自定义级别
+//
+// 注册后级别可用于 Logger.Log、ParseLevel、所有格式化器、ColorWriter、采样器、
+// 异步日志统计和级别路由。须在创建使用该级别的日志记录器之前注册,
+// 级别路由和 DefaultTheme 在创建时读取已注册的级别。
+//
+// 参数:
+//   - spec: 级别定义
+//
+// 返回:
+//   - error: 数值、名称或位次已被占用、名称非法或超过数量上限时返回
+//
+// 示例:
+//
+//	var TRACE = fastlog.MustRegisterLevel(fastlog.LevelSpec{Level: -1, Name: "TRACE", Style: fastlog.Style{Fg: fastlog.ColorBrightBlack}})
+//	var NOTICE = fastlog.MustRegisterLevel(fastlog.LevelSpec{Level: 7, Rank: 250, Name: "NOTICE"}) // 介于 INFO 和 WARN 之间
+//	var AUDIT = fastlog.MustRegisterLevel(fastlog.LevelSpec{Level: 10, Name: "AUDIT", Always: true})
+//
+//	logger.Log(TRACE, "进入函数", fastlog.String("fn", "handle"))
+func RegisterLevel(spec LevelSpec) error {
+	spec.Name = strings.ToUpper(spec.Name)
+	if spec.Level == 0 {
+		return fmt.Errorf("level %s: value 0 is reserved", spec.Name)
+	}
+	if spec.Name == "" {
+		return errors.New("level name must be set")
+	}
+	for _, c := range spec.Name {
+		if (c < 'A' || c > 'Z') && (c < '0' || c > '9') && c != '_' {
+			return fmt.Errorf("level name %q: only letters, digits and underscores are allowed", spec.Name)
+		}
+	}
+
+	levelsMu.Lock()
+	defer levelsMu.Unlock()
+	reg := levels.Load()
+	if s, ok := reg.spec(spec.Level); ok {
+		return fmt.Errorf("level %d is already registered as %s", spec.Level, s.Name)
+	}
+	if _, ok := reg.names[spec.Name]; ok {
+		return fmt.Errorf("level name %s is already registered", spec.Name)
+	}
+	rank := spec.Rank
+	if rank == 0 {
+		rank = int(spec.Level) * levelRankStep
+	}
+	for _, s := range reg.specs {
+		if s.Rank == rank {
+			return fmt.Errorf("level %s: rank %d is already used by %s", spec.Name, rank, s.Name)
+		}
+	}
+	if len(reg.specs) >= maxLevels {
+		return fmt.Errorf("too many levels: at most %d", maxLevels)
+	}
+	levels.Store(reg.with(spec))
+	return nil
+}
+
+// MustRegisterLevel 注册自定义级别, 失败时 panic, 适合在包级变量中使用
+//
+// 参数:
+//   - spec: 级别定义
+//
+// 返回:
+//   - Level: 注册的级别
+func MustRegisterLevel(spec LevelSpec) Level {
+	if err := RegisterLevel(spec); err != nil {
+		panic(err)
+	}
+	return spec.Level
+}
+
+// LookupLevel 返回已注册级别的定义
+//
+// 参数:
+//   - l: 日志级别
+//
+// 返回:
+//   - LevelSpec: 级别定义
+//   - bool: 级别已注册时返回 true
+func LookupLevel(l Level) (LevelSpec, bool) {
+	return levels.Load().spec(l)
+}
+
+// spec 返回级别的定义
+func (r *levelRegistry) spec(l Level) (LevelSpec, bool) {
+	i, ok := r.slots[l]
+	if !ok {
+		return LevelSpec{}, false
+	}
+	return r.specs[i-1], true
+}
+
+// levelSlot 返回级别的槽位编号 (1 ~ maxLevels), 未注册的级别返回 0
+//
+// 槽位按注册顺序分配且不会变化, 供采样器和异步日志统计按级别建立固定大小的数组。
+func levelSlot(l Level) int {
+	return levels.Load().slots[l]
+}
+
+// slotLevel 返回槽位编号对应的级别, 0 或未使用的槽位返回 0
+func slotLevel(slot int) Level {
+	reg := levels.Load()
+	if slot <= 0 || slot > len(reg.specs) {
+		return 0
+	}
+	return reg.specs[slot-1].Level
+}
+
+// levelRank 返回级别的位次, 未注册的级别为数值 × 100
+//
+// 内置级别的位次固定, 不读取注册表, 使只涉及内置级别的比较保持无锁且无需查表。
+func levelRank(l Level) int {
+	if l >= DEBUG && l <= PANIC {
+		return int(l) * levelRankStep
+	}
+	if r, ok := levels.Load().ranks[l]; ok {
+		return r
+	}
+	return int(l) * levelRankStep
+}
+
+// alwaysEnabled 返回级别是否为不可过滤的 Always 级别
+func alwaysEnabled(l Level) bool {
+	reg := levels.Load()
+	if !reg.always {
+		return false
+	}
+	s, ok := reg.spec(l)
+	return ok && s.Always
+}
+
+// levelFileName 返回级别路由使用的文件名
+func levelFileName(l Level) string {
+	if s, ok := levels.Load().spec(l); ok {
+		return s.FileName
+	}
+	return l.String() + ".log"
+}
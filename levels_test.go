@@ -0,0 +1,248 @@
+package fastlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// 测试用自定义级别: TRACE 低于 DEBUG, NOTICE 通过位次排在 INFO 和 WARN 之间, ALERT 和 AUDIT 高于 PANIC
+const (
+	testTrace  Level = -1
+	testNotice Level = 7
+	testAlert  Level = 8
+	testAudit  Level = 10
+)
+
+// withTestLevels 注册测试用自定义级别, 测试结束后恢复注册表, 避免影响其他测试
+func withTestLevels(t *testing.T) {
+	t.Helper()
+	levelsMu.Lock()
+	saved := levels.Load()
+	levelsMu.Unlock()
+	t.Cleanup(func() {
+		levelsMu.Lock()
+		defer levelsMu.Unlock()
+		levels.Store(saved)
+	})
+
+	for _, spec := range []LevelSpec{
+		{Level: testTrace, Name: "trace", Style: Style{Fg: ColorBrightBlack}},
+		{Level: testNotice, Rank: 250, Name: "NOTICE", Style: Style{Fg: ColorGreen}},
+		{Level: testAlert, Name: "ALERT"},
+		{Level: testAudit, Name: "AUDIT", FileName: "audit.log", Always: true},
+	} {
+		if err := RegisterLevel(spec); err != nil {
+			t.Fatalf("RegisterLevel(%s) error = %v", spec.Name, err)
+		}
+	}
+}
+
+func TestRegisterLevel(t *testing.T) {
+	withTestLevels(t)
+	if testTrace.String() != "TRACE" || testNotice.String() != "NOTICE" || testAlert.String() != "ALERT" {
+		t.Errorf("String() = %q, %q, %q", testTrace, testNotice, testAlert)
+	}
+	if lvl, err := ParseLevel("notice"); err != nil || lvl != testNotice {
+		t.Errorf("ParseLevel(notice) = %v, %v", lvl, err)
+	}
+	if spec, ok := LookupLevel(testTrace); !ok || spec.FileName != "TRACE.log" {
+		t.Errorf("LookupLevel(TRACE) = %+v, %v", spec, ok)
+	}
+	if _, ok := LookupLevel(Level(99)); ok {
+		t.Error("LookupLevel(99) should report an unregistered level")
+	}
+
+	// 按位次排列, NOTICE 虽然数值为 7 但位于 INFO 和 WARN 之间
+	want := []Level{testTrace, DEBUG, INFO, testNotice, WARN, ERROR, FATAL, PANIC, testAlert, testAudit}
+	if all := AllLevels(); !slices.Equal(all, want) {
+		t.Errorf("AllLevels() = %v, want %v", all, want)
+	}
+
+	for _, spec := range []LevelSpec{
+		{Level: 0, Name: "ZERO"},
+		{Level: 9, Name: ""},
+		{Level: 9, Name: "BAD NAME"},
+		{Level: INFO, Name: "INFO2"},
+		{Level: 9, Name: "notice"},
+		{Level: 9, Rank: 250, Name: "NOTICE2"},
+		{Level: 3, Name: "WARN2"},
+		{Level: 9, Rank: 300, Name: "WARNING"},
+	} {
+		if err := RegisterLevel(spec); err == nil {
+			t.Errorf("RegisterLevel(%+v) should fail", spec)
+		}
+	}
+}
+
+func TestCustomLevelLogging(t *testing.T) {
+	withTestLevels(t)
+	buf := &bytes.Buffer{}
+	l := New(&Config{Level: INFO, Outputs: []Output{{Writer: buf, Formatter: MustPattern("%level %msg")}}})
+
+	l.Log(testTrace, "hidden")
+	l.Log(testNotice, "shown", String("k", "v"))
+	l.Log(testAlert, "shown")
+	l.SetLevel(WARN)
+	l.Log(testNotice, "hidden") // 数值大于 WARN, 但位次低于 WARN
+	l.SetLevel(testNotice)
+	l.Info("hidden")
+	l.Log(testNotice, "shown")
+	l.Warn("shown")
+	l.SetLevel(testAudit + 1)
+	l.Log(testAudit, "login")
+	l.Log(testAlert, "hidden")
+	l.SetLevel(testTrace)
+	l.Log(testTrace, "traced")
+	_ = l.Close()
+
+	want := "NOTICE shown\nALERT shown\nNOTICE shown\nWARN shown\nAUDIT login\nTRACE traced\n"
+	if got := buf.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+
+	data, err := JSON{}.Format(&Entry{Level: testNotice, Message: "m"})
+	var rec map[string]any
+	if err != nil || json.Unmarshal(data, &rec) != nil || rec["level"] != "NOTICE" {
+		t.Errorf("JSON level = %v, %v", rec["level"], err)
+	}
+
+	// syslog 和 OTLP 按位次所在区间映射
+	for _, tt := range []struct {
+		level          Level
+		syslog, otlpSv int
+	}{
+		{testTrace, 7, 1},
+		{testNotice, 5, 9},
+		{testAlert, 1, 22},
+	} {
+		if got := SyslogSeverity(tt.level); got != tt.syslog {
+			t.Errorf("SyslogSeverity(%s) = %d, want %d", tt.level, got, tt.syslog)
+		}
+		if got := OTLPSeverity(tt.level); got != tt.otlpSv {
+			t.Errorf("OTLPSeverity(%s) = %d, want %d", tt.level, got, tt.otlpSv)
+		}
+	}
+}
+
+func TestCustomLevelSampling(t *testing.T) {
+	withTestLevels(t)
+	s := NewSampler(time.Minute, 1, 0)
+	for i, want := range []bool{true, false, false} {
+		if got := s.Allow(testNotice, "msg"); got != want {
+			t.Errorf("Allow(NOTICE) #%d = %v, want %v", i, got, want)
+		}
+	}
+	for i := 0; i < 3; i++ {
+		if !s.Allow(testAudit, "msg") {
+			t.Errorf("Allow(AUDIT) #%d should never sample out an always level", i)
+		}
+	}
+	if !s.Allow(Level(99), "msg") {
+		t.Error("Allow() should pass unregistered levels")
+	}
+
+	a := &asyncCore{policy: BackpressureDropNewest}
+	if a.policyFor(testAudit) != BackpressureBlock || a.policyFor(testNotice) != BackpressureDropNewest {
+		t.Error("policyFor() should block for always levels only")
+	}
+}
+
+func TestCustomLevelRouter(t *testing.T) {
+	withTestLevels(t)
+	dir := t.TempDir()
+	cfg := NewConfig(filepath.Join(dir, "app.log"))
+	cfg.OutputConsole = false
+	cfg.BufferEnabled = false
+	cfg.Formatter = MustPattern("%level %msg")
+	cfg.Level = WARN
+	cfg.LevelRouter = true
+
+	l := New(cfg)
+	l.Log(testTrace, "filtered")
+	l.Log(testNotice, "filtered")
+	l.Log(testAudit, "login")
+	_ = l.Close()
+
+	if got := readLogFile(t, filepath.Join(dir, "audit.log")); got != "AUDIT login\n" {
+		t.Errorf("audit.log = %q", got)
+	}
+	for _, name := range []string{"TRACE.log", "NOTICE.log"} {
+		if got := readLogFile(t, filepath.Join(dir, name)); got != "" {
+			t.Errorf("%s = %q, want no file below the level", name, got)
+		}
+	}
+}
+
+func TestCustomLevelRank(t *testing.T) {
+	withTestLevels(t)
+	dir := t.TempDir()
+	buf := &bytes.Buffer{}
+	l := New(&Config{
+		Level:   DEBUG,
+		Outputs: []Output{{Writer: buf, Formatter: MustPattern("%level %msg"), Level: testNotice}},
+		Routes:  []Route{{MinLevel: INFO, MaxLevel: testNotice, Path: filepath.Join(dir, "notice.log"), Formatter: MustPattern("%level %msg")}},
+	})
+	l.Info("info")
+	l.Log(testNotice, "notice")
+	l.Warn("warn")
+	l.Log(testAlert, "alert")
+	_ = l.Close()
+
+	// 输出级别和路由范围都按位次比较
+	if got := buf.String(); got != "NOTICE notice\nWARN warn\nALERT alert\n" {
+		t.Errorf("output = %q", got)
+	}
+	if got := readLogFile(t, filepath.Join(dir, "notice.log")); got != "INFO info\nNOTICE notice\n" {
+		t.Errorf("notice.log = %q", got)
+	}
+
+	cfg := &Config{Routes: []Route{{MinLevel: WARN, MaxLevel: testNotice, Path: "a.log"}}}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() should reject a min level ranked above the max level")
+	}
+}
+
+func TestCustomLevelUnfilteredOutputs(t *testing.T) {
+	withTestLevels(t)
+	dir := t.TempDir()
+	buf := &bytes.Buffer{}
+	l := New(&Config{
+		Level:   testTrace,
+		Outputs: []Output{{Writer: buf, Formatter: MustPattern("%level %msg")}},
+		Routes:  []Route{{MaxLevel: DEBUG, Path: filepath.Join(dir, "debug.log")}},
+	})
+	l.Log(testTrace, "trace")
+	l.Info("info")
+	_ = l.Close()
+
+	// 零值级别不过滤, 负数的自定义级别同样输出
+	if got := buf.String(); got != "TRACE trace\nINFO info\n" {
+		t.Errorf("output = %q", got)
+	}
+	if got := readLogFile(t, filepath.Join(dir, "debug.log")); !strings.Contains(got, "trace") || strings.Contains(got, "info") {
+		t.Errorf("debug.log = %q", got)
+	}
+}
+
+func TestCustomLevelColor(t *testing.T) {
+	withTestLevels(t)
+	buf := &bytes.Buffer{}
+	w := &ColorWriter{w: buf, Depth: ColorDepth16}
+	_, _ = w.Write([]byte("NOTICE ready\n"))
+	if got := buf.String(); got != "\x1b[32mNOTICE\x1b[0m ready\n" {
+		t.Errorf("ColorWriter output = %q", got)
+	}
+
+	kv := KV{EncoderConfig: &EncoderConfig{EncodeLevel: CapitalColorLevelEncoder}}
+	for lvl, want := range map[Level]string{testTrace: "\x1b[90mTRACE\x1b[0m", testAudit: "AUDIT"} {
+		b, _ := kv.Format(&Entry{Level: lvl, Message: "m"})
+		if !strings.Contains(string(b), "level="+want+" ") {
+			t.Errorf("KV output = %q, want level=%s", b, want)
+		}
+	}
+}
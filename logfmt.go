@@ -0,0 +1,415 @@
+package fastlog
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/goccy/go-json"
+)
+
+// Logfmt 标准 logfmt 格式
+// 格式: time="2025-01-15 10:30:45" level=INFO message="用户 登录" user=alice
+//
+// 与 KV 格式的区别:
+//   - 值包含空格、'='、'"'、控制字符或非法 UTF-8 时加引号并转义, 其余原样输出
+//   - 键中的空白、'='、'"' 和控制字符替换为 '_', 空键输出为 "_"
+//   - 空值输出为 key=
+//   - Any 类型的复合值 (map、切片、结构体) 以 JSON 输出, map 键有序, 结果确定
+//
+// 用户输入无法通过消息或字段值伪造额外的键或日志行, 输出可由 ParseLogfmt 还原。
+type Logfmt struct {
+	// EncoderConfig 编码器配置, 为 nil 时使用默认键名和编码方式
+	EncoderConfig *EncoderConfig
+}
+
+// Format 实现 logfmt 格式
+//
+// 参数:
+//   - entry: 日志条目
+//
+// 返回:
+//   - []byte: 格式化后的字节数组
+//   - error: 如果格式化失败
+func (f Logfmt) Format(entry *Entry) ([]byte, error) {
+	return f.AppendFormat(make([]byte, 0, 256), entry)
+}
+
+// AppendFormat 实现 AppendFormatter 接口, 将 logfmt 行追加到 dst
+//
+// 参数:
+//   - dst: 目标缓冲区
+//   - entry: 日志条目
+//
+// 返回:
+//   - []byte: 追加后的字节数组
+//   - error: 如果格式化失败
+func (f Logfmt) AppendFormat(dst []byte, entry *Entry) ([]byte, error) {
+	ec := f.EncoderConfig
+	scratch := &textEncoder{tf: entry.TimeFormat} // 承接编码器输出, 再统一转义
+
+	// 时间
+	dst = appendLogfmtKey(dst, ec.timeKey())
+	ec.encodeTime(entry.Time, scratch)
+	dst = appendLogfmtValue(dst, string(scratch.buf))
+
+	// 级别
+	scratch.buf = scratch.buf[:0]
+	dst = append(dst, ' ')
+	dst = appendLogfmtKey(dst, ec.levelKey())
+	ec.encodeLevel(entry.Level, scratch)
+	dst = appendLogfmtValue(dst, string(scratch.buf))
+
+	// 消息
+	dst = append(dst, ' ')
+	dst = appendLogfmtKey(dst, ec.messageKey())
+	dst = appendLogfmtValue(dst, entry.Message)
+
+	// 调用者信息
+	if entry.Caller != "" {
+		scratch.buf = scratch.buf[:0]
+		dst = append(dst, ' ')
+		dst = appendLogfmtKey(dst, ec.callerKey())
+		ec.encodeCaller(entry, scratch)
+		dst = appendLogfmtValue(dst, string(scratch.buf))
+	}
+
+	// 日志记录器名称
+	if entry.Logger != "" {
+		dst = append(dst, ' ')
+		dst = appendLogfmtKey(dst, ec.nameKey())
+		dst = appendLogfmtValue(dst, entry.Logger)
+	}
+
+	// 字段: 累积字段使用子日志记录器预先编码的结果
+	var ns string
+	p, fields := cachedFields(entry, f)
+	if p != nil {
+		dst = append(dst, p.data...)
+		ns = p.ns
+	}
+	dst, _ = appendLogfmtFields(dst, ec, fields, ns, entry.TimeFormat)
+
+	return append(dst, '\n'), nil
+}
+
+// encodePrefix 实现 prefixEncoder, 每个字段前都带有空格
+func (f Logfmt) encodePrefix(fields []Field, tf string) fieldPrefix {
+	data, ns := appendLogfmtFields(nil, f.EncoderConfig, fields, "", tf)
+	return fieldPrefix{data: data, ns: ns}
+}
+
+// appendLogfmtFields 追加 logfmt 字段, 命名空间以点号拼接为键前缀
+//
+// 参数:
+//   - dst: 目标缓冲区
+//   - ec: 编码器配置
+//   - fields: 字段
+//   - ns: 当前命名空间前缀
+//   - tf: 时间格式
+//
+// 返回:
+//   - []byte: 追加后的缓冲区
+//   - string: 追加后的命名空间前缀
+func appendLogfmtFields(dst []byte, ec *EncoderConfig, fields []Field, ns, tf string) ([]byte, string) {
+	for _, field := range fields {
+		if field.typ == NamespaceType {
+			ns = joinNamespace(ns, field.key)
+			continue
+		}
+		key := ec.fieldKey(field.key)
+		if ns != "" {
+			key = ns + "." + key
+		}
+		dst = append(dst, ' ')
+		dst = appendLogfmtKey(dst, key)
+		dst = appendLogfmtValue(dst, logfmtFieldValue(field, tf))
+	}
+	return dst, ns
+}
+
+// logfmtFieldValue 返回字段在 logfmt 中的值 (未转义)
+//
+// 参数:
+//   - f: 字段
+//   - tf: 时间格式
+//
+// 返回:
+//   - string: 字段值
+func logfmtFieldValue(f Field, tf string) string {
+	if f.typ == LogValuerType {
+		f = f.resolve()
+	}
+	if f.typ != AnyType {
+		return f.valueWithTimeFormat(tf)
+	}
+
+	switch val := f.iface.(type) {
+	case nil:
+		return "null"
+	case string, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64,
+		float32, float64, bool, time.Duration, error:
+		return f.anyString()
+	case time.Time:
+		return val.Format(tf)
+	case fmt.Stringer:
+		return val.String()
+	}
+
+	// 复合类型统一以 JSON 输出, 序列化失败时回退为 %v
+	b, err := json.Marshal(f.iface)
+	if err != nil {
+		return f.anyString()
+	}
+	return string(b)
+}
+
+// appendLogfmtKey 追加 key=, 键中的非法字符替换为 '_'
+//
+// 参数:
+//   - dst: 目标缓冲区
+//   - key: 原始键名
+//
+// 返回:
+//   - []byte: 追加后的缓冲区
+func appendLogfmtKey(dst []byte, key string) []byte {
+	if key == "" {
+		return append(dst, '_', '=')
+	}
+	for i := 0; i < len(key); {
+		c := key[i]
+		if c < utf8.RuneSelf {
+			if c <= ' ' || c == '=' || c == '"' || c == 0x7f {
+				dst = append(dst, '_')
+			} else {
+				dst = append(dst, c)
+			}
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(key[i:])
+		if (r == utf8.RuneError && size == 1) || unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			dst = append(dst, '_')
+		} else {
+			dst = append(dst, key[i:i+size]...)
+		}
+		i += size
+	}
+	return append(dst, '=')
+}
+
+// appendLogfmtValue 追加 logfmt 值, 仅在需要时加引号并转义
+//
+// 参数:
+//   - dst: 目标缓冲区
+//   - s: 原始值
+//
+// 返回:
+//   - []byte: 追加后的缓冲区
+func appendLogfmtValue(dst []byte, s string) []byte {
+	if !logfmtNeedsQuote(s) {
+		return append(dst, s...)
+	}
+	return appendJSONString(dst, s)
+}
+
+// logfmtNeedsQuote 判断值是否需要加引号
+//
+// 参数:
+//   - s: 原始值
+//
+// 返回:
+//   - bool: 包含空白、'='、'"'、控制字符、不可打印字符或非法 UTF-8 时返回 true
+func logfmtNeedsQuote(s string) bool {
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			if c <= ' ' || c == '=' || c == '"' || c == 0x7f {
+				return true
+			}
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if (r == utf8.RuneError && size == 1) || unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return true
+		}
+		i += size
+	}
+	return false
+}
+
+// LogfmtPair logfmt 键值对
+type LogfmtPair struct {
+	Key   string // 键
+	Value string // 值, 已去除引号并还原转义; 只有键没有 '=' 时为空字符串
+}
+
+// ParseLogfmt 解析一行 logfmt 文本
+//
+// 按出现顺序返回全部键值对, 重复的键原样保留。行尾的换行符会被忽略。
+// 未加引号的值不能包含 '=' 和 '"', 引号内支持 \" \\ \/ \b \f \n \r \t 和 \uXXXX 转义。
+//
+// 参数:
+//   - line: logfmt 文本
+//
+// 返回:
+//   - []LogfmtPair: 键值对列表
+//   - error: 语法错误时返回, 包含出错位置
+//
+// 示例:
+//
+//	pairs, err := fastlog.ParseLogfmt(`level=INFO message="a b" user=alice`)
+//	// pairs: [{level INFO} {message a b} {user alice}]
+func ParseLogfmt(line string) ([]LogfmtPair, error) {
+	var pairs []LogfmtPair
+	i, n := 0, len(line)
+	for {
+		// 跳过空白
+		for i < n && line[i] <= ' ' {
+			i++
+		}
+		if i >= n {
+			return pairs, nil
+		}
+
+		// 键
+		start := i
+		for i < n && line[i] > ' ' && line[i] != '=' && line[i] != '"' {
+			i++
+		}
+		if i == start {
+			return pairs, fmt.Errorf("logfmt: unexpected %q at offset %d", line[i], i)
+		}
+		pair := LogfmtPair{Key: line[start:i]}
+
+		// 只有键没有值
+		if i >= n || line[i] != '=' {
+			if i < n && line[i] == '"' {
+				return pairs, fmt.Errorf("logfmt: unexpected '\"' in key at offset %d", i)
+			}
+			pairs = append(pairs, pair)
+			continue
+		}
+		i++ // 跳过 '='
+
+		// 值
+		switch {
+		case i < n && line[i] == '"':
+			val, next, err := unquoteLogfmt(line, i)
+			if err != nil {
+				return pairs, err
+			}
+			pair.Value, i = val, next
+			if i < n && line[i] > ' ' {
+				return pairs, fmt.Errorf("logfmt: missing space after quoted value at offset %d", i)
+			}
+		default:
+			start = i
+			for i < n && line[i] > ' ' {
+				if line[i] == '=' || line[i] == '"' {
+					return pairs, fmt.Errorf("logfmt: unexpected %q in value at offset %d", line[i], i)
+				}
+				i++
+			}
+			pair.Value = line[start:i]
+		}
+		pairs = append(pairs, pair)
+	}
+}
+
+// errLogfmtUnterminated 引号未闭合
+var errLogfmtUnterminated = errors.New("logfmt: unterminated quoted value")
+
+// unquoteLogfmt 解析从 start 处开始的带引号值
+//
+// 参数:
+//   - s: 原始文本
+//   - start: 起始引号的位置
+//
+// 返回:
+//   - string: 还原转义后的值
+//   - int: 结束引号之后的位置
+//   - error: 引号未闭合或转义非法时返回
+func unquoteLogfmt(s string, start int) (string, int, error) {
+	i := start + 1
+
+	// 快速路径: 无转义时直接切片
+	for j := i; j < len(s); j++ {
+		if s[j] == '"' {
+			return s[i:j], j + 1, nil
+		}
+		if s[j] == '\\' {
+			break
+		}
+	}
+
+	buf := make([]byte, 0, len(s)-i)
+	for i < len(s) {
+		c := s[i]
+		switch {
+		case c == '"':
+			return string(buf), i + 1, nil
+		case c != '\\':
+			buf = append(buf, c)
+			i++
+			continue
+		}
+
+		// 转义序列
+		if i+1 >= len(s) {
+			return "", 0, errLogfmtUnterminated
+		}
+		esc := s[i+1]
+		i += 2
+		switch esc {
+		case '"', '\\', '/':
+			buf = append(buf, esc)
+		case 'b':
+			buf = append(buf, '\b')
+		case 'f':
+			buf = append(buf, '\f')
+		case 'n':
+			buf = append(buf, '\n')
+		case 'r':
+			buf = append(buf, '\r')
+		case 't':
+			buf = append(buf, '\t')
+		case 'u':
+			r, ok := parseHex4(s, i)
+			if !ok {
+				return "", 0, fmt.Errorf("logfmt: invalid \\u escape at offset %d", i-2)
+			}
+			i += 4
+			// UTF-16 代理对
+			if utf16.IsSurrogate(r) && i+6 <= len(s) && s[i] == '\\' && s[i+1] == 'u' {
+				if lo, ok := parseHex4(s, i+2); ok {
+					if dec := utf16.DecodeRune(r, lo); dec != utf8.RuneError {
+						r = dec
+						i += 6
+					}
+				}
+			}
+			buf = utf8.AppendRune(buf, r)
+		default:
+			return "", 0, fmt.Errorf("logfmt: invalid escape %q at offset %d", esc, i-2)
+		}
+	}
+	return "", 0, errLogfmtUnterminated
+}
+
+// parseHex4 解析 s[i:i+4] 处的 4 位十六进制数
+func parseHex4(s string, i int) (rune, bool) {
+	if i+4 > len(s) {
+		return 0, false
+	}
+	v, err := strconv.ParseUint(s[i:i+4], 16, 32)
+	if err != nil {
+		return 0, false
+	}
+	return rune(v), true
+}
@@ -0,0 +1,173 @@
+package fastlog
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// parseLogfmtMap 解析 logfmt 行并转为 map, 同时检查不存在重复的键
+func parseLogfmtMap(t *testing.T, line string) map[string]string {
+	t.Helper()
+	pairs, err := ParseLogfmt(line)
+	if err != nil {
+		t.Fatalf("ParseLogfmt(%q) error = %v", line, err)
+	}
+	m := make(map[string]string, len(pairs))
+	for _, p := range pairs {
+		if _, dup := m[p.Key]; dup {
+			t.Fatalf("ParseLogfmt(%q) duplicate key %q", line, p.Key)
+		}
+		m[p.Key] = p.Value
+	}
+	return m
+}
+
+func TestLogfmtFormat(t *testing.T) {
+	t.Run("basic", func(t *testing.T) {
+		entry := makeEntry("hello", "main.go:main:10", String("user", "alice"), Int("n", 3))
+		entry.Logger = "app.db"
+		b, err := Logfmt{}.Format(entry)
+		if err != nil {
+			t.Fatalf("Format() error = %v", err)
+		}
+		want := `time="2026-01-15 10:30:45" level=INFO message=hello caller=main.go:main:10 logger=app.db user=alice n=3` + "\n"
+		if string(b) != want {
+			t.Errorf("Logfmt.Format() =\n%q\nwant\n%q", b, want)
+		}
+	})
+
+	t.Run("quoting", func(t *testing.T) {
+		tests := []struct {
+			in   string
+			want string
+		}{
+			{"plain", "v=plain"},
+			{"", "v="},
+			{"a b", `v="a b"`},
+			{"a=b", `v="a=b"`},
+			{`say "hi"`, `v="say \"hi\""`},
+			{"l1\nl2", `v="l1\nl2"`},
+			{"\x1b[31mred", `v="\u001b[31mred"`},
+			{`back\slash`, `v=back\slash`},
+			{"中文", "v=中文"},
+			{"全角\u3000空格", "v=\"全角\u3000空格\""},
+		}
+		for _, tt := range tests {
+			b, _ := Logfmt{}.Format(makeEntry("m", "", String("v", tt.in)))
+			if got := strings.TrimSuffix(string(b), "\n"); !strings.HasSuffix(got, " "+tt.want) {
+				t.Errorf("value %q: got %q, want suffix %q", tt.in, got, tt.want)
+			}
+		}
+	})
+
+	t.Run("key sanitize", func(t *testing.T) {
+		b, _ := Logfmt{}.Format(makeEntry("m", "", String("a b=c\"d\n", "v"), String("", "empty")))
+		if !strings.Contains(string(b), " a_b_c_d_=v _=empty") {
+			t.Errorf("sanitized keys = %q", b)
+		}
+	})
+
+	t.Run("namespace and nested", func(t *testing.T) {
+		entry := makeEntry("m", "",
+			Namespace("req"),
+			Any("meta", map[string]interface{}{"b": 2, "a": "x y"}),
+			Object("user", ObjectMarshalerFunc(func(enc ObjectEncoder) error {
+				enc.AddString("name", "alice")
+				enc.AddInt("age", 30)
+				return nil
+			})),
+		)
+		b, _ := Logfmt{}.Format(entry)
+		m := parseLogfmtMap(t, string(b))
+		if m["req.meta"] != `{"a":"x y","b":2}` {
+			t.Errorf("req.meta = %q, want sorted JSON", m["req.meta"])
+		}
+		if m["req.user"] != "{name=alice age=30}" {
+			t.Errorf("req.user = %q", m["req.user"])
+		}
+	})
+}
+
+func TestLogfmtInjection(t *testing.T) {
+	msg := "login ok\n2026-01-15 10:30:46 level=ERROR message=forged admin=true"
+	entry := makeEntry(msg, "", String("user", `bob" admin="true`))
+	b, _ := Logfmt{}.Format(entry)
+
+	if strings.Count(string(b), "\n") != 1 {
+		t.Fatalf("output should be a single line, got %q", b)
+	}
+	m := parseLogfmtMap(t, string(b))
+	if _, ok := m["admin"]; ok {
+		t.Errorf("user input forged an extra key: %v", m)
+	}
+	if m["message"] != msg || m["user"] != `bob" admin="true` || m["level"] != "INFO" {
+		t.Errorf("round trip mismatch: %v", m)
+	}
+}
+
+func TestLogfmtRoundTrip(t *testing.T) {
+	values := []string{
+		"", " ", "=", `"`, `\`, `\"`, "a\tb", "\r\n", "\x00\x7f", "emoji 😀", "\u2028",
+		`{"json":"value"}`, "key=value other=thing", "trailing ", `C:\path\to`,
+	}
+	for _, v := range values {
+		b, err := Logfmt{}.Format(makeEntry(v, "", String("field", v)))
+		if err != nil {
+			t.Fatalf("Format() error = %v", err)
+		}
+		m := parseLogfmtMap(t, string(b))
+		if m["message"] != v || m["field"] != v {
+			t.Errorf("round trip %q: message=%q field=%q", v, m["message"], m["field"])
+		}
+	}
+}
+
+func TestParseLogfmt(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		pairs, err := ParseLogfmt(`a=1 b="x \"y\" \u00e9\ud83d\ude00" flag c= a=2` + "\n")
+		if err != nil {
+			t.Fatalf("ParseLogfmt() error = %v", err)
+		}
+		want := []LogfmtPair{{"a", "1"}, {"b", `x "y" é😀`}, {"flag", ""}, {"c", ""}, {"a", "2"}}
+		if len(pairs) != len(want) {
+			t.Fatalf("pairs = %v, want %v", pairs, want)
+		}
+		for i := range want {
+			if pairs[i] != want[i] {
+				t.Errorf("pairs[%d] = %v, want %v", i, pairs[i], want[i])
+			}
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, line := range []string{
+			`a="unterminated`,
+			`a="bad \q escape"`,
+			`a=b=c`,
+			`a=x"y`,
+			`=v`,
+			`a="x"b`,
+			`a="\u12"`,
+		} {
+			if _, err := ParseLogfmt(line); err == nil {
+				t.Errorf("ParseLogfmt(%q) should fail", line)
+			}
+		}
+		if _, err := ParseLogfmt(`a="x`); !errors.Is(err, errLogfmtUnterminated) {
+			t.Errorf("unterminated error = %v", err)
+		}
+	})
+}
+
+func TestLogfmtEncoderConfig(t *testing.T) {
+	b, _ := Logfmt{EncoderConfig: &EncoderConfig{
+		MessageKey:  "msg",
+		EncodeLevel: LowercaseLevelEncoder,
+		EncodeTime:  EpochTimeEncoder,
+	}}.Format(makeEntry("hi there", ""))
+	m := parseLogfmtMap(t, string(b))
+	if m["msg"] != "hi there" || m["level"] != "info" || m["time"] != "1768473045" {
+		t.Errorf("EncoderConfig not applied: %v", m)
+	}
+}
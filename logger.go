@@ -17,6 +17,8 @@ import (
 type Level int32
 
 // 日志级别常量
+//
+// 零值保留表示未设置; 通过 RegisterLevel 注册的自定义级别使用其余数值, 并通过 LevelSpec.Rank 决定排序位置。
 const (
 	DEBUG Level = iota + 1 // 调试级别 (1)
 	INFO                   // 信息级别 (2)
@@ -36,7 +38,7 @@ const (
 	LevelNamePanic = "PANIC"
 )
 
-// String 返回级别的字符串表示
+// String 返回级别的字符串表示, 包括通过 RegisterLevel 注册的自定义级别
 func (l Level) String() string {
 	switch l {
 	case DEBUG:
@@ -51,12 +53,17 @@ func (l Level) String() string {
 		return LevelNameFatal
 	case PANIC:
 		return LevelNamePanic
-	default:
-		return fmt.Sprintf("Level(%d)", l)
 	}
+	if s, ok := levels.Load().spec(l); ok {
+		return s.Name
+	}
+	return fmt.Sprintf("Level(%d)", l)
 }
 
-// Enabled 检查是否启用该级别 (lvl >= l 时启用)
+// Enabled 检查是否启用该级别 (lvl 的位次不低于 l 或 lvl 为 Always 级别时启用)
+//
+// 级别按 LevelSpec.Rank 比较而非数值, 如位次介于 INFO 和 WARN 之间的 NOTICE 在 WARN 级别下不输出。
+// 零值表示不过滤, 启用包括负数自定义级别在内的所有级别。
 //
 // 参数:
 //   - lvl: 要检查的级别
@@ -64,10 +71,10 @@ func (l Level) String() string {
 // 返回:
 //   - bool: 是否启用该级别
 func (l Level) Enabled(lvl Level) bool {
-	return lvl >= l
+	return l == 0 || levelRank(lvl) >= levelRank(l) || alwaysEnabled(lvl)
 }
 
-// ParseLevel 从字符串解析日志级别
+// ParseLevel 从字符串解析日志级别, 包括通过 RegisterLevel 注册的自定义级别
 //
 // 参数:
 //   - s: 要解析的字符串
@@ -76,30 +83,18 @@ func (l Level) Enabled(lvl Level) bool {
 //   - Level: 解析后的日志级别
 //   - error: 如果解析失败
 func ParseLevel(s string) (Level, error) {
-	switch strings.ToUpper(s) {
-	case LevelNameDebug:
-		return DEBUG, nil
-	case LevelNameInfo:
-		return INFO, nil
-	case LevelNameWarn:
-		return WARN, nil
-	case LevelNameError:
-		return ERROR, nil
-	case LevelNameFatal:
-		return FATAL, nil
-	case LevelNamePanic:
-		return PANIC, nil
-	default:
-		return INFO, fmt.Errorf("unknown level: %s", s)
-	}
-}
-
-// AllLevels 返回所有日志级别
+	if lvl, ok := levels.Load().names[strings.ToUpper(s)]; ok {
+		return lvl, nil
+	}
+	return INFO, fmt.Errorf("unknown level: %s", s)
+}
+
+// AllLevels 返回所有日志级别, 包括自定义级别, 按位次 (LevelSpec.Rank) 升序排列
 //
 // 返回:
 //   - []Level: 包含所有日志级别的切片
 func AllLevels() []Level {
-	return []Level{DEBUG, INFO, WARN, ERROR, FATAL, PANIC}
+	return append([]Level(nil), levels.Load().sorted...)
 }
 
 // Entry 表示一条日志记录
@@ -110,6 +105,10 @@ type Entry struct {
 	Caller     string    // 调用者信息: file.go:func:line
 	Fields     []Field   // 键值对字段
 	TimeFormat string    // 时间格式, 从 Config.TimeFormat 传递
+	Logger     string    // 日志记录器名称, 由 Named 设置, 为空表示根日志记录器
+	CallerPC   uintptr   // 调用者程序计数器, 未记录调用者时为 0, 供 CallerEncoder 解析完整路径
+
+	prefix *fieldCache // 开头累积字段的编码缓存, 由日志记录器设置, nil 表示逐条编码
 }
 
 // callerSkip 是 getCaller 的跳过层数常量
@@ -119,7 +118,7 @@ const callerSkip = 3
 // Logger 日志记录器
 //
 // Logger 是 FastLog 的核心日志记录器, 提供日志记录、级别控制、采样等功能。
-// 支持 6 种日志级别: DEBUG, INFO, WARN, ERROR, FATAL, PANIC。
+// 支持 6 种内置日志级别: DEBUG, INFO, WARN, ERROR, FATAL, PANIC, 以及通过 RegisterLevel 注册的自定义级别。
 // 支持三种调用方式: 标准日志 (Info)、格式化日志 (Infof)、结构化日志 (Infow)。
 //
 // 必须通过 fastlog.New(cfg) 构造函数创建, 切勿直接声明空结构体使用。
@@ -132,12 +131,19 @@ const callerSkip = 3
 //	defer func() { _ = logger.Close() }()
 //	logger.Info("服务启动成功")
 type Logger struct {
-	config  *Config        // 日志配置
-	writer  io.WriteCloser // 日志写入器
-	sampler *Sampler       // 日志采样器, nil 表示不启用采样
-	mu      sync.Mutex     // 日志记录器的互斥锁
-	level   atomic.Int32   // 运行时日志级别, 支持动态调整, 初始化时从 config.Level 设置
-	hooks   []hook         // 内部 hooks, 用于级别路由等扩展功能
+	config    *Config        // 日志配置
+	writer    io.WriteCloser // 日志写入器
+	sampler   *Sampler       // 日志采样器, nil 表示不启用采样
+	mu        *sync.Mutex    // 日志记录器的互斥锁, 与子日志记录器共享
+	level     *atomic.Int32  // 运行时日志级别, 支持动态调整, 与子日志记录器共享
+	hooks     []hook         // 内部 hooks, 用于级别路由、syslog、HTTP 批量输出等扩展功能
+	userHooks *hookSet       // 通过 AddHook 注册的 hooks, 与子日志记录器共享
+	fields    []Field        // 预合并字段: config.Fields + With 累积字段, 只读, 容量等于长度
+	prefix    *fieldCache    // fields 按各格式化器预先编码的结果, nil 表示不缓存
+	name      string         // 日志记录器名称, 由 Named 设置
+	names     *nameLevels    // 按名称前缀覆盖的级别, 与子日志记录器共享
+	async     *asyncCore     // 异步日志核心, nil 表示同步写入, 与子日志记录器共享
+	render    *renderPlan    // 多格式化器输出计划, nil 表示只使用 Config.Formatter, 与子日志记录器共享
 }
 
 // New 创建一个新的日志记录器
@@ -164,7 +170,7 @@ func New(cfg *Config) *Logger {
 
 	// 应用默认值
 	if config.Level == 0 {
-		config.Level = INFO
+		config.Level = defaultOutputsLevel(config.Outputs)
 	}
 	if config.Formatter == nil {
 		config.Formatter = Def{}
@@ -185,51 +191,143 @@ func New(cfg *Config) *Logger {
 
 	// 创建日志记录器实例
 	l := &Logger{
-		config:  config,         // 日志配置
-		writer:  writer,         // 日志写入器
-		sampler: sampler,        // 日志采样器
-		level:   atomic.Int32{}, // 运行时日志级别, 初始化时从 config.Level 设置
+		config:    config,                          // 日志配置
+		writer:    writer,                          // 日志写入器
+		sampler:   sampler,                         // 日志采样器
+		mu:        &sync.Mutex{},                   // 互斥锁
+		level:     &atomic.Int32{},                 // 运行时日志级别, 初始化时从 config.Level 设置
+		fields:    mergeFields(config.Fields, nil), // 预合并配置中的字段
+		names:     &nameLevels{},                   // 按名称前缀覆盖的级别
+		userHooks: &hookSet{},                      // 通过 AddHook 注册的 hooks
+		render:    newRenderPlan(config),           // 多格式化器输出计划
 	}
 
 	// 以 Config.Level 作为运行时级别的初始值
 	l.level.Store(int32(config.Level))
 
-	// 如果启用级别路由，自动初始化 hooks
-	if config.LevelRouter {
-		l.initLevelHooks(config)
+	// 如果启用级别路由或配置了路由规则，为每条规则创建专属文件 hook
+	if routes := config.routes(); len(routes) > 0 {
+		l.initRoutes(routes)
+	}
+
+	// 如果配置了按键分发，添加按键分发钩子
+	if config.FanOut != nil {
+		hook := newFanOutHook(config)
+		if l.render != nil {
+			hook.slot = l.render.slotOf(config.FanOut.Formatter) // 按键分发文件使用的格式化器
+		}
+		l.hooks = append(l.hooks, hook)
+	}
+
+	// 如果配置了 syslog，添加 syslog 钩子
+	if config.Syslog != nil {
+		l.hooks = append(l.hooks, newSyslogHook(config.Syslog))
+	}
+
+	// 如果配置了 HTTP 批量输出，添加 HTTP 钩子（配置已验证，创建不会失败）
+	if config.HTTP != nil {
+		if sink, err := NewHTTPSink(config.HTTP); err == nil {
+			l.hooks = append(l.hooks, &httpSinkHook{sink: sink})
+		}
+	}
+
+	// 预先编码配置中的字段（须在路由和按键分发的格式化器登记之后）
+	l.prefix = l.newFieldCache(l.fields)
+
+	// 如果启用异步日志，启动消费协程（须在 hooks 初始化之后）
+	if config.AsyncLog != nil {
+		l.async = newAsyncCore(l, config.AsyncLog)
 	}
 
 	return l
 }
 
-// initLevelHooks 初始化级别路由 hooks（内部方法）
-// 为 >= cfg.Level 的每个级别创建专属文件 hook
-func (l *Logger) initLevelHooks(cfg *Config) {
-	dir := filepath.Dir(cfg.LogPath)
+// With 创建一个携带额外字段的子日志记录器
+//
+// 子日志记录器与父日志记录器共享写入器、hooks、采样器和运行时级别,
+// 仅额外累积字段。累积字段在创建时预合并一次, 每条日志输出时位于调用字段之前。
+// 创建子日志记录器不会影响父日志记录器, 关闭任意一个都会关闭共享的写入器。
+//
+// 累积字段同时在创建时按 JSON、KV、Logfmt 格式化器各预先编码一次, 格式化时直接追加,
+// 只需编码调用字段; hooks、路由规则和 %field 仍读取条目中的完整字段。
+// 注册了同步 hook 时 (可能修改字段) 不使用预先编码的结果。
+//
+// 参数:
+//   - fields: 要附加的字段
+//
+// 返回:
+//   - *Logger: 子日志记录器, 未传入字段时返回自身
+//
+// 示例:
+//
+//	reqLog := logger.With(fastlog.String("request_id", id))
+//	reqLog.Infow("开始处理", fastlog.String("path", path))
+//	// 输出: ... 开始处理 request_id=xxx, path=/api
+func (l *Logger) With(fields ...Field) *Logger {
+	if len(fields) == 0 {
+		return l
+	}
+	child := l.clone()
+	child.fields = mergeFields(l.fields, fields)
+	child.prefix = child.newFieldCache(child.fields)
+	return child
+}
 
-	// 为 >= cfg.Level 的每个级别创建专属文件
-	for _, lvl := range AllLevels() {
-		if lvl < cfg.Level {
-			continue // 低于设置级别的级别不创建专属文件
-		}
+// newFieldCache 按日志记录器使用的各个格式化器预先编码累积字段（内部方法）
+//
+// 参数:
+//   - fields: 累积字段
+//
+// 返回:
+//   - *fieldCache: 编码缓存, 无法缓存时返回 nil
+func (l *Logger) newFieldCache(fields []Field) *fieldCache {
+	formatters := []Formatter{l.config.Formatter}
+	if l.render != nil {
+		formatters = l.render.formatters
+	}
+	return newFieldCache(fields, l.config.TimeFormat, formatters)
+}
 
-		// 创建级别专属配置
-		lvlCfg := cfg.Clone()
-		lvlCfg.LogPath = filepath.Join(dir, lvl.String()+".log")
-		lvlCfg.OutputConsole = false // 级别路由不输出到控制台，防止影响主日志记录
-		lvlCfg.LevelRouter = false   // 防止递归
-
-		// 创建写入器
-		writer := lvlCfg.NewWriter()
-		if writer != nil {
-			l.hooks = append(l.hooks, &levelHook{
-				level:  lvl, // 级别
-				writer: writer,
-			})
-		} else {
-			_, _ = fmt.Fprintf(os.Stderr, "failed to create level file: %s\n", lvlCfg.LogPath)
-		}
+// clone 浅拷贝日志记录器, 共享写入器、锁和运行时级别（内部方法）
+//
+// 返回:
+//   - *Logger: 新的日志记录器实例
+func (l *Logger) clone() *Logger {
+	return &Logger{
+		config:    l.config,
+		writer:    l.writer,
+		sampler:   l.sampler,
+		mu:        l.mu,
+		level:     l.level,
+		hooks:     l.hooks,
+		userHooks: l.userHooks,
+		fields:    l.fields,
+		prefix:    l.prefix,
+		name:      l.name,
+		names:     l.names,
+		async:     l.async,
+		render:    l.render,
+	}
+}
+
+// mergeFields 合并两组字段, 返回容量等于长度的新切片
+//
+// 容量等于长度保证任何 append 都会重新分配, 不会篡改共享的预合并字段。
+//
+// 参数:
+//   - base: 前置字段
+//   - extra: 追加字段
+//
+// 返回:
+//   - []Field: 合并后的字段, 两者都为空时返回 nil
+func mergeFields(base, extra []Field) []Field {
+	n := len(base) + len(extra)
+	if n == 0 {
+		return nil
 	}
+	merged := make([]Field, 0, n)
+	merged = append(merged, base...)
+	return append(merged, extra...)
 }
 
 // SetLevel 运行时动态修改日志级别, 立即生效
@@ -248,6 +346,23 @@ func (l *Logger) Level() Level {
 	return Level(l.level.Load())
 }
 
+// Enabled 检查该日志记录器是否启用指定级别
+//
+// 名称命中 SetNameLevel 设置的前缀时使用覆盖级别, 否则使用全局运行时级别。
+//
+// 参数:
+//   - level: 要检查的级别
+//
+// 返回:
+//   - bool: 是否启用该级别
+func (l *Logger) Enabled(level Level) bool {
+	threshold, ok := l.names.lookup(l.name)
+	if !ok {
+		threshold = Level(l.level.Load())
+	}
+	return threshold.Enabled(level)
+}
+
 // log 记录日志的核心方法
 //
 // 参数:
@@ -256,7 +371,7 @@ func (l *Logger) Level() Level {
 //   - fields: 日志字段
 func (l *Logger) log(level Level, msg string, fields []Field) {
 	// 检查日志级别是否启用, 如果未启用则直接返回
-	if !Level(l.level.Load()).Enabled(level) {
+	if !l.Enabled(level) {
 		return
 	}
 
@@ -265,43 +380,150 @@ func (l *Logger) log(level Level, msg string, fields []Field) {
 		return
 	}
 
+	// 记录调用者信息
+	var pc uintptr
+	if l.config.Caller {
+		pc = callerPC(callerSkip)
+	}
+
+	l.output(time.Now(), level, msg, fields, pc)
+}
+
+// output 组装日志条目并格式化、写入（内部方法）
+//
+// 调用方负责级别和采样检查, 以及按需获取调用者信息。
+//
+// 参数:
+//   - t: 时间戳
+//   - level: 日志级别
+//   - msg: 日志消息
+//   - fields: 调用字段, 位于预合并字段之后
+//   - pc: 调用者程序计数器, 为 0 表示不记录
+func (l *Logger) output(t time.Time, level Level, msg string, fields []Field, pc uintptr) {
+	l.emit(t, level, msg, fields, pc, nil)
+}
+
+// emit 与 output 相同, 可覆盖异步模式下该条日志的背压策略（内部方法）
+//
+// 参数:
+//   - t: 时间戳
+//   - level: 日志级别
+//   - msg: 日志消息
+//   - fields: 调用字段, 位于预合并字段之后
+//   - pc: 调用者程序计数器, 为 0 表示不记录
+//   - policy: 背压策略, 为 nil 时使用级别对应的策略
+//
+// 返回:
+//   - bool: 日志已写入、已放入异步队列或被 hook 丢弃时返回 true
+func (l *Logger) emit(t time.Time, level Level, msg string, fields []Field, pc uintptr, policy *BackpressurePolicy) bool {
 	// 从对象池获取日志条目
 	entry := GetEntry()
-	defer PutEntry(entry)
+	pooled := entry.Fields // 池中条目自带的字段缓冲区
+	defer func() {
+		entry.Fields = pooled[:0] // 归还自带缓冲区, 避免预合并字段被池复用
+		PutEntry(entry)
+	}()
 
 	// 填充日志条目
-	entry.Time = time.Now()                                     // 时间戳
-	entry.Level = level                                         // 日志级别
-	entry.Message = msg                                         // 日志消息
-	entry.TimeFormat = l.config.TimeFormat                      // 时间格式
-	entry.Fields = append(entry.Fields[:0], l.config.Fields...) // 添加配置中的字段
-	entry.Fields = append(entry.Fields, fields...)              // 添加用户提供的字段
+	entry.Time = t                         // 时间戳
+	entry.Level = level                    // 日志级别
+	entry.Message = msg                    // 日志消息
+	entry.Caller = callerFromPC(pc)        // 调用者信息
+	entry.CallerPC = pc                    // 调用者程序计数器
+	entry.TimeFormat = l.config.TimeFormat // 时间格式
+	entry.Logger = l.name                  // 日志记录器名称
+
+	// 填充字段: 无调用字段且没有可修改条目的同步 hook 时直接引用预合并字段, 无需逐条复制
+	hasSync := l.userHooks.hasSync.Load()
+	if len(fields) == 0 && !hasSync {
+		entry.Fields = l.fields
+	} else {
+		pooled = append(append(pooled[:0], l.fields...), fields...)
+		entry.Fields = pooled
+	}
 
-	// 记录调用者信息
-	if l.config.Caller {
-		entry.Caller = getCaller(callerSkip)
+	// 没有同步 hook 时开头的累积字段不会被修改, 格式化器可直接使用预先编码的结果
+	if !hasSync {
+		entry.prefix = l.prefix
+	}
+
+	// 执行 AddHook 注册的 hooks: 同步 hook 可修改条目或丢弃日志
+	if !l.userHooks.run(entry) {
+		return true
 	}
 
-	// 填充字段
-	data, err := l.config.Formatter.Format(entry)
+	// 配置了多个格式化器: 按输出级别格式化, 每个格式化器只执行一次
+	if l.render != nil {
+		r := l.render.renderOutputs(entry, l.hooks)
+		if l.async != nil {
+			return l.async.enqueue(entry, asyncRecord{rendered: r}, policy)
+		}
+		defer r.release()
+		l.writeRendered(entry, r)
+		return true
+	}
+
+	// 格式化日志条目: 支持追加式格式化时使用池化缓冲区
+	data, bp, err := formatEntry(l.config.Formatter, entry)
 	if err != nil {
 		_, _ = fmt.Fprintf(os.Stderr, "format error: %v\n", err)
-		return
+		return false
+	}
+
+	// 异步模式: 交给消费协程写入, 缓冲区随记录转交
+	if l.async != nil {
+		return l.async.enqueue(entry, asyncRecord{data: data, buf: bp}, policy)
+	}
+	if bp != nil {
+		defer func() { putBuffer(bp, data) }()
 	}
+	l.write(entry.Level, entry, data)
+	return true
+}
 
+// write 写入已格式化的日志并执行 hooks（内部方法）
+//
+// 同步模式下由记录日志的协程调用, 异步模式下由消费协程调用。
+// 写入器实现 LevelWriter 时通过 WriteLevel 传入日志级别。
+//
+// 参数:
+//   - level: 日志级别
+//   - entry: 日志条目, 没有 hooks 时可为 nil
+//   - data: 格式化后的日志
+func (l *Logger) write(level Level, entry *Entry, data []byte) {
 	// 写入日志（主文件 + hooks）
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
-	_, err = l.writer.Write(data)
-	if err != nil {
+	if _, err := writeLevel(l.writer, level, data); err != nil {
 		_, _ = fmt.Fprintf(os.Stderr, "write error: %v\n", err)
 	}
 
-	// 执行内部 hooks（级别路由）
-	// Fire 方法内部会检查级别是否匹配
+	// 执行内部 hooks（级别路由、syslog、HTTP 批量输出）
+	// Fire 方法内部会检查级别是否匹配, 错误输出到标准错误, 不影响主流程
 	for _, h := range l.hooks {
-		_ = h.Fire(entry, data) // 忽略 hook 错误，避免影响主流程
+		if err := h.Fire(entry, data); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "hook error: %v\n", err)
+		}
+	}
+}
+
+// writeRendered 将多格式化器的结果写入主写入器、各输出并执行 hooks（内部方法）
+//
+// 参数:
+//   - entry: 日志条目, 没有 hooks 时可为 nil
+//   - r: 格式化结果
+func (l *Logger) writeRendered(entry *Entry, r *rendered) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.render.write(l.writer, r)
+
+	// 每个 hook 收到其格式化器对应的数据
+	for _, h := range l.hooks {
+		if err := h.Fire(entry, r.hookData(h, entry)); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "hook error: %v\n", err)
+		}
 	}
 }
 
@@ -473,13 +695,34 @@ func (l *Logger) Panicw(msg string, fields ...Field) {
 	panic(msg)
 }
 
+// Log 记录指定级别的日志, 用于通过 RegisterLevel 注册的自定义级别
+//
+// 与 Fatal、Panic 不同, 以 FATAL 或 PANIC 级别调用 Log 只记录日志, 不会退出程序或触发 panic。
+//
+// 参数:
+//   - level: 日志级别
+//   - msg: 日志消息
+//   - fields: 日志字段
+func (l *Logger) Log(level Level, msg string, fields ...Field) {
+	l.log(level, msg, fields)
+}
+
 // Sync 同步日志到存储
 //
+// 异步模式下先等待队列中已记录的日志写入 (最长 AsyncConfig.DrainTimeout)。
+//
 // 返回:
 //   - error: 同步过程中的错误, 如果写入器不支持同步则返回 nil
 func (l *Logger) Sync() error {
 	var errs []error
 
+	// 等待异步队列清空
+	if l.async != nil {
+		if err := l.async.sync(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
 	// 同步主写入器
 	if syncer, ok := l.writer.(interface{ Sync() error }); ok {
 		if err := syncer.Sync(); err != nil {
@@ -487,6 +730,13 @@ func (l *Logger) Sync() error {
 		}
 	}
 
+	// 同步独立输出
+	if l.render != nil {
+		if err := l.render.sync(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
 	// 同步所有 hooks
 	for _, h := range l.hooks {
 		if err := h.Sync(); err != nil {
@@ -494,21 +744,45 @@ func (l *Logger) Sync() error {
 		}
 	}
 
+	// 等待异步 hooks 执行完已入队的日志
+	for _, h := range l.userHooks.load() {
+		if err := h.flush(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
 	return errors.Join(errs...)
 }
 
 // Close 关闭日志记录器
 //
+// 异步模式下先停止接收新日志, 等待队列清空 (最长 AsyncConfig.DrainTimeout) 并停止消费协程。
+// 写入器和 hooks 与 With、Named 创建的日志记录器共享, 关闭任意一个都会关闭它们, 应只关闭根日志记录器。
+//
 // 返回:
 //   - error: 关闭过程中的错误
 func (l *Logger) Close() error {
 	var errs []error
 
+	// 停止异步消费协程
+	if l.async != nil {
+		if err := l.async.close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
 	// 关闭主写入器
 	if err := l.writer.Close(); err != nil {
 		errs = append(errs, err)
 	}
 
+	// 关闭独立输出
+	if l.render != nil {
+		if err := l.render.close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
 	// 关闭所有 hooks
 	for _, h := range l.hooks {
 		if err := h.Close(); err != nil {
@@ -516,9 +790,27 @@ func (l *Logger) Close() error {
 		}
 	}
 
+	// 停止异步 hooks
+	for _, h := range l.userHooks.load() {
+		if err := h.stop(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
 	return errors.Join(errs...)
 }
 
+// AsyncStats 返回异步日志统计信息
+//
+// 返回:
+//   - AsyncStats: 统计信息快照, 未启用异步日志时返回零值
+func (l *Logger) AsyncStats() AsyncStats {
+	if l.async == nil {
+		return AsyncStats{}
+	}
+	return l.async.stats()
+}
+
 // getCaller 获取调用者信息
 //
 // 参数:
@@ -528,23 +820,70 @@ func (l *Logger) Close() error {
 //   - string: 调用者信息, 格式为 "文件名:函数名:行号"
 //   - error: 如果获取调用者信息失败
 func getCaller(skip int) string {
-	// 获取调用栈信息: pc=程序计数器, file=文件名, line=行号
-	pc, file, line, ok := runtime.Caller(skip)
-	if !ok {
+	pc := callerPC(skip + 1)
+	if pc == 0 {
+		return "?:?:0"
+	}
+	return callerFromPC(pc)
+}
+
+// callerPC 获取调用者的程序计数器
+//
+// 跳过层数与 runtime.Caller 一致: 0 表示 callerPC 自身。
+//
+// 参数:
+//   - skip: 跳过调用栈的层数
+//
+// 返回:
+//   - uintptr: 程序计数器, 获取失败时返回 0
+func callerPC(skip int) uintptr {
+	var pcs [1]uintptr
+	if runtime.Callers(skip+1, pcs[:]) == 0 {
+		return 0
+	}
+	return pcs[0]
+}
+
+// callerFromPC 根据程序计数器获取调用者信息
+//
+// 用于已持有 PC 的场景 (如 slog.Record.PC), 无需再次遍历调用栈。
+//
+// 参数:
+//   - pc: 程序计数器
+//
+// 返回:
+//   - string: 调用者信息, 格式为 "文件名:函数名:行号", pc 为 0 时返回空字符串
+func callerFromPC(pc uintptr) string {
+	if pc == 0 {
+		return ""
+	}
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	if frame.File == "" {
 		return "?:?:0"
 	}
+	fnName := frame.Function
+	if fnName == "" {
+		fnName = "?"
+	}
+	return formatCaller(frame.File, fnName, frame.Line)
+}
 
+// formatCaller 格式化调用者信息
+//
+// 参数:
+//   - file: 文件完整路径
+//   - fnName: 完整函数名
+//   - line: 行号
+//
+// 返回:
+//   - string: 调用者信息, 格式为 "文件名:函数名:行号"
+func formatCaller(file, fnName string, line int) string {
 	// 取文件名最后一段, 如 "path/to/main.go" → "main.go"
 	file = filepath.Base(file)
 
-	// 通过 PC 获取函数名, 获取失败时用 "?" 保底
-	fnName := "?"
-	if fn := runtime.FuncForPC(pc); fn != nil {
-		fnName = fn.Name()
-		// 取完整函数名最后一个点之后的部分, 如 "main.main" → "main"
-		if i := strings.LastIndexByte(fnName, '.'); i >= 0 {
-			fnName = fnName[i+1:]
-		}
+	// 取完整函数名最后一个点之后的部分, 如 "main.main" → "main"
+	if i := strings.LastIndexByte(fnName, '.'); i >= 0 {
+		fnName = fnName[i+1:]
 	}
 
 	// 格式化调用者信息
@@ -560,6 +899,38 @@ var EntryPool = sync.Pool{
 	},
 }
 
+// maxPooledBufferSize 可归还缓冲池的最大容量, 超过后丢弃, 避免个别超长日志长期占用内存
+const maxPooledBufferSize = 64 * 1024
+
+// bufferPool 格式化缓冲区池, 供 AppendFormatter 复用
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		b := make([]byte, 0, 1024)
+		return &b
+	},
+}
+
+// getBuffer 从池中获取格式化缓冲区
+func getBuffer() *[]byte {
+	return bufferPool.Get().(*[]byte)
+}
+
+// putBuffer 将格式化缓冲区放回池中
+//
+// 参数:
+//   - bp: 从 getBuffer 获取的缓冲区
+//   - used: 实际使用的切片, 扩容后的底层数组随之归还
+func putBuffer(bp *[]byte, used []byte) {
+	if cap(used) > cap(*bp) {
+		*bp = used
+	}
+	if cap(*bp) > maxPooledBufferSize {
+		return
+	}
+	*bp = (*bp)[:0]
+	bufferPool.Put(bp)
+}
+
 // GetEntry 从池中获取日志条目
 //
 // 返回:
@@ -578,5 +949,8 @@ func PutEntry(e *Entry) {
 	e.Caller = ""           // 清空调用者信息
 	e.Message = ""          // 清空日志消息
 	e.Time = time.Time{}    // 清空时间戳
+	e.Logger = ""           // 清空日志记录器名称
+	e.CallerPC = 0          // 清空调用者程序计数器
+	e.prefix = nil          // 清空字段编码缓存
 	EntryPool.Put(e)        // 放回池
 }
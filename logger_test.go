@@ -3,6 +3,7 @@ package fastlog
 import (
 	"bytes"
 	"fmt"
+	"runtime"
 	"strings"
 	"testing"
 	"time"
@@ -435,6 +436,16 @@ func TestGetCaller(t *testing.T) {
 	}
 }
 
+func TestCallerFromPC(t *testing.T) {
+	if got := callerFromPC(0); got != "" {
+		t.Errorf("callerFromPC(0) = %q, want empty", got)
+	}
+	pc, _, _, _ := runtime.Caller(0)
+	if got := callerFromPC(pc); !strings.Contains(got, "logger_test.go:TestCallerFromPC") {
+		t.Errorf("callerFromPC() = %q, want test file and function", got)
+	}
+}
+
 func TestGetCallerSkipTooDeep(t *testing.T) {
 	caller := getCaller(100)
 	if caller != "?:?:0" {
@@ -528,3 +539,95 @@ func TestLoggerPresetAndLocalFieldsMerge(t *testing.T) {
 		t.Errorf("Should contain local field 'method=GET'")
 	}
 }
+
+// ======== 子日志记录器测试 ========
+
+func TestLoggerWith(t *testing.T) {
+	t.Run("fields ahead of call fields", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		l := New(&Config{
+			Level:         INFO,
+			OutputConsole: true,
+			Formatter:     &testFormatter{buf: buf},
+			Fields:        []Field{String("app", "fastlog")},
+		})
+		child := l.With(String("request_id", "r-1"))
+
+		child.Infow("handled", String("path", "/api"))
+		want := "INFO handled app=fastlog request_id=r-1 path=/api\n"
+		if got := buf.String(); got != want {
+			t.Errorf("child output = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("parent not affected", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		l := New(&Config{Level: INFO, OutputConsole: true, Formatter: &testFormatter{buf: buf}})
+		_ = l.With(String("request_id", "r-1"))
+
+		l.Info("parent")
+		if strings.Contains(buf.String(), "request_id") {
+			t.Errorf("parent should not carry child fields, got: %q", buf.String())
+		}
+	})
+
+	t.Run("siblings do not share fields", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		l := New(&Config{Level: INFO, OutputConsole: true, Formatter: &testFormatter{buf: buf}})
+		base := l.With(String("a", "1"))
+		c1 := base.With(String("b", "2"))
+		c2 := base.With(String("c", "3"))
+
+		c1.Infow("one", String("x", "y"))
+		c2.Info("two")
+		base.Info("base")
+		want := "INFO one a=1 b=2 x=y\nINFO two a=1 c=3\nINFO base a=1\n"
+		if got := buf.String(); got != want {
+			t.Errorf("output = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("no fields returns self", func(t *testing.T) {
+		l := New(&Config{Level: INFO, OutputConsole: true})
+		if l.With() != l {
+			t.Errorf("With() without fields should return the same logger")
+		}
+	})
+
+	t.Run("shares level and writer", func(t *testing.T) {
+		m := newMock()
+		l := New(&Config{Level: INFO, OutputConsole: true, Formatter: Simple{}})
+		l.writer = m
+		child := l.With(String("k", "v"))
+
+		l.SetLevel(ERROR)
+		if child.Level() != ERROR {
+			t.Errorf("child level = %v, want ERROR", child.Level())
+		}
+		child.Error("to shared writer")
+		if !strings.Contains(m.String(), "to shared writer k=v") {
+			t.Errorf("child should write to parent writer, got: %q", m.String())
+		}
+		if err := child.Close(); err != nil || !m.closed {
+			t.Errorf("child Close() should close shared writer, err = %v", err)
+		}
+	})
+}
+
+// discardWriteCloser 丢弃全部写入, 用于基准测试
+type discardWriteCloser struct{}
+
+func (discardWriteCloser) Write(p []byte) (int, error) { return len(p), nil }
+func (discardWriteCloser) Close() error                { return nil }
+
+func BenchmarkLoggerJSON(b *testing.B) {
+	l := New(&Config{Level: INFO, OutputConsole: true, Formatter: JSON{}})
+	l.writer = discardWriteCloser{}
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			l.Infow("用户登录成功", String("user", "alice"), Int("age", 30))
+		}
+	})
+}
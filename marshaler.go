@@ -0,0 +1,135 @@
+package fastlog
+
+import "time"
+
+// ObjectMarshaler 自定义对象序列化接口
+//
+// 实现该接口的类型通过 ObjectEncoder 逐个描述自身字段, 无需反射。
+// 同一实现在 JSON 格式中输出为嵌套对象, 在文本格式中输出为 {k=v k2=v2}。
+//
+// 示例:
+//
+//	type User struct {
+//	    Name string
+//	    Age  int
+//	}
+//
+//	func (u User) MarshalLogObject(enc fastlog.ObjectEncoder) error {
+//	    enc.AddString("name", u.Name)
+//	    enc.AddInt("age", u.Age)
+//	    return nil
+//	}
+//
+//	logger.Infow("登录", fastlog.Object("user", user))
+//	// JSON: "user":{"name":"alice","age":30}
+//	// Def:  user={name=alice age=30}
+type ObjectMarshaler interface {
+	MarshalLogObject(enc ObjectEncoder) error
+}
+
+// ArrayMarshaler 自定义数组序列化接口
+//
+// 实现该接口的类型通过 ArrayEncoder 逐个追加元素, 无需反射。
+// JSON 格式输出为数组, 文本格式输出为 [a b c]。
+type ArrayMarshaler interface {
+	MarshalLogArray(enc ArrayEncoder) error
+}
+
+// ObjectMarshalerFunc 函数适配器, 将普通函数转换为 ObjectMarshaler
+type ObjectMarshalerFunc func(enc ObjectEncoder) error
+
+// MarshalLogObject 实现 ObjectMarshaler 接口
+//
+// 参数:
+//   - enc: 对象编码器
+//
+// 返回:
+//   - error: 序列化过程中的错误
+func (f ObjectMarshalerFunc) MarshalLogObject(enc ObjectEncoder) error {
+	return f(enc)
+}
+
+// ArrayMarshalerFunc 函数适配器, 将普通函数转换为 ArrayMarshaler
+type ArrayMarshalerFunc func(enc ArrayEncoder) error
+
+// MarshalLogArray 实现 ArrayMarshaler 接口
+//
+// 参数:
+//   - enc: 数组编码器
+//
+// 返回:
+//   - error: 序列化过程中的错误
+func (f ArrayMarshalerFunc) MarshalLogArray(enc ArrayEncoder) error {
+	return f(enc)
+}
+
+// ObjectEncoder 对象编码器, 由格式化器实现, 供 ObjectMarshaler 描述字段
+type ObjectEncoder interface {
+	AddString(key, val string)                       // 添加字符串
+	AddInt(key string, val int)                      // 添加 int
+	AddInt64(key string, val int64)                  // 添加 int64
+	AddUint64(key string, val uint64)                // 添加 uint64
+	AddFloat64(key string, val float64)              // 添加 float64
+	AddBool(key string, val bool)                    // 添加 bool
+	AddTime(key string, val time.Time)               // 添加时间, 按 Config.TimeFormat 格式化
+	AddDuration(key string, val time.Duration)       // 添加时间持续值
+	AddObject(key string, obj ObjectMarshaler) error // 添加嵌套对象
+	AddArray(key string, arr ArrayMarshaler) error   // 添加嵌套数组
+	AddAny(key string, val interface{})              // 添加任意值, 回退到反射, 应尽量避免
+}
+
+// PrimitiveArrayEncoder 基础类型数组编码器
+type PrimitiveArrayEncoder interface {
+	AppendString(val string)          // 追加字符串
+	AppendInt(val int)                // 追加 int
+	AppendInt64(val int64)            // 追加 int64
+	AppendUint64(val uint64)          // 追加 uint64
+	AppendFloat64(val float64)        // 追加 float64
+	AppendBool(val bool)              // 追加 bool
+	AppendTime(val time.Time)         // 追加时间, 按 Config.TimeFormat 格式化
+	AppendDuration(val time.Duration) // 追加时间持续值
+}
+
+// ArrayEncoder 数组编码器, 由格式化器实现, 供 ArrayMarshaler 追加元素
+type ArrayEncoder interface {
+	PrimitiveArrayEncoder
+	AppendObject(obj ObjectMarshaler) error // 追加嵌套对象
+	AppendArray(arr ArrayMarshaler) error   // 追加嵌套数组
+}
+
+// Object 创建一个对象字段
+//
+// 参数:
+//   - key: 字段键
+//   - val: 实现 ObjectMarshaler 的值
+//
+// 返回:
+//   - Field: 字段实例
+func Object(key string, val ObjectMarshaler) Field {
+	return Field{key: key, typ: ObjectType, iface: val}
+}
+
+// Array 创建一个数组字段
+//
+// 参数:
+//   - key: 字段键
+//   - val: 实现 ArrayMarshaler 的值
+//
+// 返回:
+//   - Field: 字段实例
+//
+// 示例:
+//
+//	ids := []int64{1, 2, 3}
+//	logger.Infow("批量删除", fastlog.Array("ids", fastlog.ArrayMarshalerFunc(
+//	    func(enc fastlog.ArrayEncoder) error {
+//	        for _, id := range ids {
+//	            enc.AppendInt64(id)
+//	        }
+//	        return nil
+//	    })))
+//	// JSON: "ids":[1,2,3]
+//	// Def:  ids=[1 2 3]
+func Array(key string, val ArrayMarshaler) Field {
+	return Field{key: key, typ: ArrayType, iface: val}
+}
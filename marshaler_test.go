@@ -0,0 +1,191 @@
+package fastlog
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+// testUser 用于测试的 ObjectMarshaler 实现
+type testUser struct {
+	name  string
+	age   int
+	roles []string
+}
+
+func (u testUser) MarshalLogObject(enc ObjectEncoder) error {
+	enc.AddString("name", u.name)
+	enc.AddInt("age", u.age)
+	return enc.AddArray("roles", ArrayMarshalerFunc(func(arr ArrayEncoder) error {
+		for _, r := range u.roles {
+			arr.AppendString(r)
+		}
+		return nil
+	}))
+}
+
+// testInts 用于测试的 ArrayMarshaler 实现
+type testInts []int64
+
+func (s testInts) MarshalLogArray(enc ArrayEncoder) error {
+	for _, v := range s {
+		enc.AppendInt64(v)
+	}
+	return nil
+}
+
+func TestObjectField(t *testing.T) {
+	u := testUser{name: "alice", age: 30, roles: []string{"admin", "dev"}}
+
+	f := Object("user", u)
+	if f.Type() != ObjectType {
+		t.Fatalf("Object().Type() = %v, want ObjectType", f.Type())
+	}
+	if got, want := f.Value(), "{name=alice age=30 roles=[admin dev]}"; got != want {
+		t.Errorf("Value() = %q, want %q", got, want)
+	}
+	raw, ok := f.toInterface().(json.RawMessage)
+	if !ok {
+		t.Fatalf("toInterface() = %T, want json.RawMessage", f.toInterface())
+	}
+	if got, want := string(raw), `{"name":"alice","age":30,"roles":["admin","dev"]}`; got != want {
+		t.Errorf("toInterface() = %s, want %s", got, want)
+	}
+
+	if Any("user", u).Type() != ObjectType {
+		t.Error("Any(ObjectMarshaler) should be ObjectType")
+	}
+}
+
+func TestArrayField(t *testing.T) {
+	f := Array("ids", testInts{1, 2, 3})
+	if f.Type() != ArrayType {
+		t.Fatalf("Array().Type() = %v, want ArrayType", f.Type())
+	}
+	if got := f.Value(); got != "[1 2 3]" {
+		t.Errorf("Value() = %q, want [1 2 3]", got)
+	}
+	if got := string(f.toInterface().(json.RawMessage)); got != "[1,2,3]" {
+		t.Errorf("toInterface() = %s, want [1,2,3]", got)
+	}
+	if Any("ids", testInts{}).Type() != ArrayType {
+		t.Error("Any(ArrayMarshaler) should be ArrayType")
+	}
+	if got := Array("empty", testInts{}).Value(); got != "[]" {
+		t.Errorf("empty array Value() = %q, want []", got)
+	}
+}
+
+func TestMarshalerTimeFormat(t *testing.T) {
+	ts := time.Date(2026, 1, 15, 10, 30, 45, 0, time.UTC)
+	f := Object("ev", ObjectMarshalerFunc(func(enc ObjectEncoder) error {
+		enc.AddTime("at", ts)
+		enc.AddDuration("took", 1500*time.Millisecond)
+		return nil
+	}))
+
+	if got, want := f.valueWithTimeFormat(time.TimeOnly), "{at=10:30:45 took=1.5s}"; got != want {
+		t.Errorf("valueWithTimeFormat() = %q, want %q", got, want)
+	}
+	raw := f.toInterfaceWithTimeFormat(time.RFC3339).(json.RawMessage)
+	if got, want := string(raw), `{"at":"2026-01-15T10:30:45Z","took":"1.5s"}`; got != want {
+		t.Errorf("toInterfaceWithTimeFormat() = %s, want %s", got, want)
+	}
+}
+
+func TestMarshalerError(t *testing.T) {
+	f := Object("obj", ObjectMarshalerFunc(func(enc ObjectEncoder) error {
+		enc.AddString("partial", "x")
+		return errors.New("boom")
+	}))
+	if got := f.Value(); got != "boom" {
+		t.Errorf("Value() = %q, want error message", got)
+	}
+	if got := string(f.toInterface().(json.RawMessage)); got != `"boom"` {
+		t.Errorf("toInterface() = %s, want \"boom\"", got)
+	}
+
+	// 嵌套对象失败不影响外层已写入的字段
+	outer := Object("outer", ObjectMarshalerFunc(func(enc ObjectEncoder) error {
+		enc.AddInt("n", 1)
+		_ = enc.AddObject("inner", f.iface.(ObjectMarshaler))
+		enc.AddBool("ok", true)
+		return nil
+	}))
+	if got, want := string(outer.toInterface().(json.RawMessage)), `{"n":1,"inner":"boom","ok":true}`; got != want {
+		t.Errorf("nested error = %s, want %s", got, want)
+	}
+}
+
+func TestJSONEncoderEscape(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"plain", `"plain"`},
+		{`a"b\c`, `"a\"b\\c"`},
+		{"line1\nline2\ttab\r", `"line1\nline2\ttab\r"`},
+		{"\x00\x1b[31m", `"\u0000\u001b[31m"`},
+		{"中文", `"中文"`},
+		{"bad\xffutf8", `"bad�utf8"`},
+	}
+	for _, tt := range tests {
+		got := string(appendJSONString(nil, tt.in))
+		if got != tt.want {
+			t.Errorf("appendJSONString(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+		if !json.Valid([]byte(got)) {
+			t.Errorf("appendJSONString(%q) produced invalid JSON: %s", tt.in, got)
+		}
+	}
+}
+
+func TestJSONEncoderFloat(t *testing.T) {
+	enc := jsonEncoder{buf: []byte{'{'}}
+	enc.AddFloat64("a", 1.5)
+	enc.AddFloat64("nan", math.NaN())
+	enc.AddFloat64("inf", math.Inf(1))
+	enc.AddFloat64("ninf", math.Inf(-1))
+	enc.buf = append(enc.buf, '}')
+
+	want := `{"a":1.5,"nan":"NaN","inf":"+Inf","ninf":"-Inf"}`
+	if string(enc.buf) != want {
+		t.Errorf("got %s, want %s", enc.buf, want)
+	}
+	if !json.Valid(enc.buf) {
+		t.Errorf("invalid JSON: %s", enc.buf)
+	}
+}
+
+func TestMarshalerFormatters(t *testing.T) {
+	u := testUser{name: "bob", age: 25, roles: []string{"ops"}}
+
+	entry := makeEntry("login", "", Object("user", u), Array("ids", testInts{7, 8}))
+	b, err := JSON{}.Format(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		t.Fatalf("invalid JSON %s: %v", b, err)
+	}
+	user, ok := m["user"].(map[string]interface{})
+	if !ok || user["name"] != "bob" || user["age"] != float64(25) {
+		t.Errorf("user = %v, want nested object", m["user"])
+	}
+	if ids, ok := m["ids"].([]interface{}); !ok || len(ids) != 2 {
+		t.Errorf("ids = %v, want array of 2", m["ids"])
+	}
+
+	b, err = Def{}.Format(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := string(b); !strings.Contains(s, "user={name=bob age=25 roles=[ops]}") || !strings.Contains(s, "ids=[7 8]") {
+		t.Errorf("Def output = %q", s)
+	}
+}
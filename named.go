@@ -0,0 +1,133 @@
+package fastlog
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// nameLevels 按名称前缀覆盖日志级别（内部使用）
+//
+// 前缀按点号分段匹配, 最长前缀优先:
+// 设置 "app.db" 后, "app.db" 与 "app.db.pool" 均命中, "app.dbx" 不命中。
+type nameLevels struct {
+	mu     sync.RWMutex     // 保护 levels
+	levels map[string]Level // 名称前缀 → 级别
+	count  atomic.Int32     // 覆盖规则数量, 为 0 时跳过查找
+}
+
+// set 设置名称前缀的级别
+//
+// 参数:
+//   - prefix: 名称前缀
+//   - level: 日志级别
+func (n *nameLevels) set(prefix string, level Level) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.levels == nil {
+		n.levels = make(map[string]Level)
+	}
+	n.levels[prefix] = level
+	n.count.Store(int32(len(n.levels)))
+}
+
+// remove 移除名称前缀的级别覆盖
+//
+// 参数:
+//   - prefix: 名称前缀
+func (n *nameLevels) remove(prefix string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	delete(n.levels, prefix)
+	n.count.Store(int32(len(n.levels)))
+}
+
+// lookup 查找名称对应的覆盖级别, 无内存分配
+//
+// 参数:
+//   - name: 日志记录器名称
+//
+// 返回:
+//   - Level: 覆盖级别
+//   - bool: 是否存在匹配的前缀
+func (n *nameLevels) lookup(name string) (Level, bool) {
+	if name == "" || n.count.Load() == 0 {
+		return 0, false
+	}
+
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	for {
+		if lvl, ok := n.levels[name]; ok {
+			return lvl, true
+		}
+		// 去掉最后一段继续匹配, 如 "app.db.pool" → "app.db"
+		i := strings.LastIndexByte(name, '.')
+		if i < 0 {
+			return 0, false
+		}
+		name = name[:i]
+	}
+}
+
+// Named 创建一个带名称的子日志记录器
+//
+// 名称按层级以点号拼接, 如 logger.Named("app").Named("db") 的名称为 "app.db"。
+// 所有内置格式化器都会以 logger 字段输出名称。
+// 子日志记录器与父日志记录器共享写入器、hooks、采样器和运行时级别。
+//
+// 参数:
+//   - name: 名称片段, 为空时返回自身
+//
+// 返回:
+//   - *Logger: 子日志记录器
+//
+// 示例:
+//
+//	db := logger.Named("app").Named("db")
+//	db.Info("连接成功") // 输出: ... 连接成功 logger=app.db
+func (l *Logger) Named(name string) *Logger {
+	if name == "" {
+		return l
+	}
+	child := l.clone()
+	if l.name == "" {
+		child.name = name
+	} else {
+		child.name = l.name + "." + name
+	}
+	return child
+}
+
+// Name 返回日志记录器名称
+//
+// 返回:
+//   - string: 点号分隔的层级名称, 根日志记录器为空
+func (l *Logger) Name() string {
+	return l.name
+}
+
+// SetNameLevel 运行时为名称前缀设置日志级别, 立即生效
+//
+// 覆盖级别优先于 SetLevel 设置的全局级别, 可用于单独调高或调低某个子系统的日志量。
+// 同一 New 创建的日志记录器及其全部子日志记录器共享这些设置。
+//
+// 参数:
+//   - prefix: 名称前缀, 按点号分段匹配, 最长前缀优先
+//   - level: 日志级别
+//
+// 示例:
+//
+//	logger.SetNameLevel("app.db", fastlog.ERROR)      // 静默 app.db 及其子系统
+//	logger.SetNameLevel("app.db.pool", fastlog.DEBUG) // 单独放开连接池调试日志
+func (l *Logger) SetNameLevel(prefix string, level Level) {
+	l.names.set(prefix, level)
+}
+
+// RemoveNameLevel 移除名称前缀的级别覆盖, 恢复使用全局级别
+//
+// 参数:
+//   - prefix: 名称前缀
+func (l *Logger) RemoveNameLevel(prefix string) {
+	l.names.remove(prefix)
+}
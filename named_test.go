@@ -0,0 +1,117 @@
+package fastlog
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoggerNamed(t *testing.T) {
+	t.Run("hierarchical name", func(t *testing.T) {
+		l := New(&Config{Level: INFO, OutputConsole: true})
+		db := l.Named("app").Named("db").Named("pool")
+		if got := db.Name(); got != "app.db.pool" {
+			t.Errorf("Name() = %q, want %q", got, "app.db.pool")
+		}
+		if l.Name() != "" {
+			t.Errorf("root Name() = %q, want empty", l.Name())
+		}
+	})
+
+	t.Run("empty name returns self", func(t *testing.T) {
+		l := New(&Config{Level: INFO, OutputConsole: true})
+		if l.Named("") != l {
+			t.Errorf("Named(\"\") should return the same logger")
+		}
+	})
+
+	t.Run("name emitted as logger field", func(t *testing.T) {
+		m := newMock()
+		l := New(&Config{Level: INFO, OutputConsole: true, Formatter: Simple{}})
+		l.writer = m
+
+		l.Named("app").Named("db").Infow("connected", String("host", "db1"))
+		if !strings.Contains(m.String(), "connected logger=app.db, host=db1") {
+			t.Errorf("output should carry logger field, got: %q", m.String())
+		}
+	})
+
+	t.Run("name combined with With", func(t *testing.T) {
+		m := newMock()
+		l := New(&Config{Level: INFO, OutputConsole: true, Formatter: KV{}})
+		l.writer = m
+
+		l.Named("cache").With(String("shard", "a")).Info("hit")
+		if !strings.Contains(m.String(), "logger=cache shard=a") {
+			t.Errorf("output should carry logger and With fields, got: %q", m.String())
+		}
+	})
+}
+
+func TestLoggerNameLevel(t *testing.T) {
+	m := newMock()
+	l := New(&Config{Level: INFO, OutputConsole: true, Formatter: Simple{}})
+	l.writer = m
+
+	app := l.Named("app")
+	db := app.Named("db")
+	pool := db.Named("pool")
+	dbx := app.Named("dbx")
+
+	// 静默 app.db, 单独放开 app.db.pool 的调试日志
+	l.SetNameLevel("app.db", ERROR)
+	db.SetNameLevel("app.db.pool", DEBUG)
+
+	db.Warn("db warn")
+	pool.Debug("pool debug")
+	dbx.Info("dbx info")
+	app.Info("app info")
+
+	out := m.String()
+	if strings.Contains(out, "db warn") {
+		t.Errorf("app.db WARN should be suppressed by override, got: %q", out)
+	}
+	if !strings.Contains(out, "pool debug") {
+		t.Errorf("app.db.pool DEBUG should pass by longest prefix, got: %q", out)
+	}
+	if !strings.Contains(out, "dbx info") {
+		t.Errorf("app.dbx should not match app.db prefix, got: %q", out)
+	}
+	if !strings.Contains(out, "app info") {
+		t.Errorf("app should use global level, got: %q", out)
+	}
+
+	if db.Enabled(WARN) {
+		t.Errorf("db.Enabled(WARN) = true, want false")
+	}
+
+	// 移除覆盖后恢复全局级别
+	l.RemoveNameLevel("app.db")
+	if !db.Enabled(WARN) {
+		t.Errorf("db.Enabled(WARN) after RemoveNameLevel = false, want true")
+	}
+}
+
+func TestNameLevelsLookup(t *testing.T) {
+	var n nameLevels
+	if _, ok := n.lookup("app"); ok {
+		t.Errorf("lookup on empty nameLevels should miss")
+	}
+
+	n.set("app", WARN)
+	tests := []struct {
+		name string
+		want Level
+		ok   bool
+	}{
+		{"app", WARN, true},
+		{"app.http.server", WARN, true},
+		{"application", 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := n.lookup(tt.name)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("lookup(%q) = (%v, %v), want (%v, %v)", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,590 @@
+package fastlog
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// OverflowPolicy 断线期间缓冲区已满时的处理策略
+type OverflowPolicy uint8
+
+const (
+	// OverflowDropNewest 丢弃新写入的日志, 保留已缓冲的日志 (默认)
+	OverflowDropNewest OverflowPolicy = iota
+
+	// OverflowDropOldest 丢弃最早缓冲的日志, 为新日志腾出空间
+	OverflowDropOldest
+
+	// OverflowBlock 阻塞写入直到重连成功或缓冲区有空间, 会阻塞日志调用方
+	OverflowBlock
+)
+
+// NetState 网络写入器的连接状态
+type NetState int32
+
+const (
+	NetDisconnected NetState = iota // 未连接, 日志写入缓冲区, 后台按退避策略重连
+	NetConnected                    // 已连接, 日志直接发送
+	NetClosed                       // 已关闭
+)
+
+// String 返回连接状态名称
+//
+// 返回:
+//   - string: 状态名称
+func (s NetState) String() string {
+	switch s {
+	case NetDisconnected:
+		return "disconnected"
+	case NetConnected:
+		return "connected"
+	case NetClosed:
+		return "closed"
+	default:
+		return fmt.Sprintf("NetState(%d)", int32(s))
+	}
+}
+
+// 网络写入器默认值
+const (
+	DefaultNetDialTimeout  = 5 * time.Second        // 默认连接超时
+	DefaultNetWriteTimeout = 5 * time.Second        // 默认写入超时
+	DefaultNetMinBackoff   = 100 * time.Millisecond // 默认初始重连间隔
+	DefaultNetMaxBackoff   = 30 * time.Second       // 默认最大重连间隔
+	DefaultNetBufferSize   = 1 << 20                // 默认断线缓冲区大小 (1MB)
+)
+
+// errNetWriterClosed 写入已关闭的网络写入器
+var errNetWriterClosed = errors.New("net writer closed")
+
+// NetWriterConfig 网络写入器配置
+//
+// 示例:
+//
+//	cfg := fastlog.NewConfig("logs/app.log")
+//	cfg.Net = &fastlog.NetWriterConfig{
+//	    Network:  "tcp",
+//	    Addr:     "logs.example.com:5170",
+//	    Overflow: fastlog.OverflowDropOldest,
+//	}
+type NetWriterConfig struct {
+	// Network 网络类型: "tcp"、"udp"、"unix"、"unixgram" 及 "tcp4" 等变体
+	Network string
+
+	// Addr 地址, 如 "localhost:5170" 或 "/var/run/app.sock"
+	Addr string
+
+	// DialTimeout 连接超时时间, 零值默认 5 秒
+	DialTimeout time.Duration
+
+	// WriteTimeout 单次写入超时时间, 零值默认 5 秒
+	WriteTimeout time.Duration
+
+	// MinBackoff 首次重连前的等待时间, 之后每次失败翻倍, 零值默认 100 毫秒
+	MinBackoff time.Duration
+
+	// MaxBackoff 重连等待时间上限, 零值默认 30 秒
+	MaxBackoff time.Duration
+
+	// BufferSize 断线期间缓冲的最大字节数, 零值默认 1MB
+	BufferSize int
+
+	// Overflow 缓冲区已满时的处理策略, 零值默认 OverflowDropNewest
+	Overflow OverflowPolicy
+}
+
+// validate 验证网络写入器配置
+//
+// 返回:
+//   - error: 验证通过时返回 nil, 否则返回错误信息
+func (c *NetWriterConfig) validate() error {
+	switch c.Network {
+	case "tcp", "tcp4", "tcp6", "udp", "udp4", "udp6", "unix", "unixgram":
+	default:
+		return fmt.Errorf("unsupported net writer network %q", c.Network)
+	}
+	if c.Addr == "" {
+		return errors.New("net writer addr must be set")
+	}
+	if c.DialTimeout < 0 || c.WriteTimeout < 0 || c.MinBackoff < 0 || c.MaxBackoff < 0 {
+		return errors.New("net writer timeouts must be >= 0")
+	}
+	if c.BufferSize < 0 {
+		return errors.New("net writer buffer size must be >= 0")
+	}
+	if c.Overflow > OverflowBlock {
+		return fmt.Errorf("unsupported overflow policy %d", c.Overflow)
+	}
+	return nil
+}
+
+// NetWriterStats 网络写入器统计信息
+type NetWriterStats struct {
+	State         NetState // 当前连接状态
+	Written       uint64   // 已发送的日志条数
+	Dropped       uint64   // 因缓冲区溢出、只发送了一部分或关闭时未发送而丢弃的日志条数
+	Reconnects    uint64   // 断线后重连成功的次数
+	Buffered      int      // 当前缓冲的日志条数
+	BufferedBytes int      // 当前缓冲的字节数
+}
+
+// NetWriter 网络写入器, 将日志发送到 TCP、UDP 或 unix 套接字
+//
+// 已连接时每次 Write 直接发送; 连接失败或被对端关闭后转为断线状态, 日志写入有界缓冲区,
+// 后台协程按指数退避重连, 重连成功后按原顺序补发缓冲的日志。
+// 断线期间 Write 不返回错误, 丢弃的日志通过 Stats 统计。写入器是并发安全的。
+//
+// 每条日志至多发送一次: 发送失败时若已写出部分字节, 该条日志计入丢弃而不是重发,
+// 避免接收端收到残片之后再收到重复的完整日志。
+//
+// 流式连接按原样发送字节, 由格式化器末尾的换行符分隔; 数据报连接每条日志一个数据报。
+type NetWriter struct {
+	network      string         // 网络类型
+	addr         string         // 地址
+	dialTimeout  time.Duration  // 连接超时
+	writeTimeout time.Duration  // 写入超时
+	minBackoff   time.Duration  // 初始重连间隔
+	maxBackoff   time.Duration  // 最大重连间隔
+	bufferSize   int            // 断线缓冲区上限 (字节)
+	overflow     OverflowPolicy // 溢出策略
+
+	dialer func() (net.Conn, bool, error)          // 自定义连接方式, 返回连接及是否为流式连接, nil 时按 network 连接
+	framer func(dst, p []byte, stream bool) []byte // 发送前的分帧方式, 将 p 分帧后追加到 dst, nil 时原样发送
+
+	mu         sync.Mutex   // 保护以下字段
+	cond       *sync.Cond   // OverflowBlock 策略下等待缓冲区空间或重连
+	conn       net.Conn     // 当前连接, nil 表示未连接
+	stream     bool         // 当前连接是否为流式连接
+	dead       *atomic.Bool // 流式连接是否已被对端关闭, 由 watchConn 设置
+	frame      []byte       // 分帧的复用缓冲区
+	state      NetState     // 连接状态
+	queue      [][]byte     // 断线缓冲区, 按写入顺序
+	queueBytes int          // 缓冲区字节数, 包含正在补发的日志
+	replay     net.Conn     // 正在补发缓冲日志的连接, 关闭时用于中断补发
+	inflight   int          // 正在补发的日志条数
+	written    uint64       // 已发送条数
+	dropped    uint64       // 丢弃条数
+	reconnects uint64       // 重连成功次数
+	connected  bool         // 是否曾经连接成功, 用于区分首次连接和重连
+
+	kick chan struct{} // 通知后台协程开始重连
+	done chan struct{} // 关闭信号
+}
+
+// NewNetWriter 创建网络写入器
+//
+// 创建时不要求目标可达, 后台协程立即开始连接, 连接成功前的日志写入缓冲区。
+//
+// 参数:
+//   - cfg: 网络写入器配置
+//
+// 返回:
+//   - *NetWriter: 网络写入器
+//   - error: 配置非法时返回
+func NewNetWriter(cfg NetWriterConfig) (*NetWriter, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	w := newNetWriter(cfg)
+	w.start()
+	return w, nil
+}
+
+// newNetWriter 按配置创建尚未启动后台协程的网络写入器, 应用默认值
+func newNetWriter(cfg NetWriterConfig) *NetWriter {
+	w := &NetWriter{
+		network:      cfg.Network,
+		addr:         cfg.Addr,
+		dialTimeout:  cfg.DialTimeout,
+		writeTimeout: cfg.WriteTimeout,
+		minBackoff:   cfg.MinBackoff,
+		maxBackoff:   cfg.MaxBackoff,
+		bufferSize:   cfg.BufferSize,
+		overflow:     cfg.Overflow,
+		kick:         make(chan struct{}, 1),
+		done:         make(chan struct{}),
+	}
+
+	// 应用默认值
+	if w.dialTimeout == 0 {
+		w.dialTimeout = DefaultNetDialTimeout
+	}
+	if w.writeTimeout == 0 {
+		w.writeTimeout = DefaultNetWriteTimeout
+	}
+	if w.minBackoff == 0 {
+		w.minBackoff = DefaultNetMinBackoff
+	}
+	if w.maxBackoff == 0 {
+		w.maxBackoff = DefaultNetMaxBackoff
+	}
+	if w.maxBackoff < w.minBackoff {
+		w.maxBackoff = w.minBackoff
+	}
+	if w.bufferSize == 0 {
+		w.bufferSize = DefaultNetBufferSize
+	}
+	w.cond = sync.NewCond(&w.mu)
+	return w
+}
+
+// start 启动后台协程并立即开始连接
+func (w *NetWriter) start() {
+	w.kick <- struct{}{}
+	go w.run()
+}
+
+// Write 发送一条日志, 断线时写入缓冲区
+//
+// 参数:
+//   - p: 要发送的字节数据
+//
+// 返回:
+//   - int: 已接收的字节数, 缓冲或丢弃时同样返回 len(p)
+//   - error: 仅在写入器已关闭时返回错误
+func (w *NetWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for {
+		if w.state == NetClosed {
+			return 0, errNetWriterClosed
+		}
+		if w.conn != nil && w.dead != nil && w.dead.Load() {
+			w.disconnect()
+		}
+
+		// 已连接: 直接发送, 失败则转为断线并缓冲
+		if w.conn != nil {
+			n, err := w.send(p)
+			if err == nil {
+				w.written++
+				return len(p), nil
+			}
+			w.disconnect()
+			if n > 0 {
+				// 已发送部分数据, 重发整条会让接收端收到残片和重复的日志, 只能丢弃
+				w.dropped++
+				return len(p), nil
+			}
+		}
+
+		// 单条日志超过缓冲区上限, 无论何种策略都无法缓冲
+		if len(p) > w.bufferSize {
+			w.dropped++
+			return len(p), nil
+		}
+		if w.queueBytes+len(p) <= w.bufferSize {
+			break
+		}
+
+		// 缓冲区已满
+		switch w.overflow {
+		case OverflowDropOldest:
+			for len(w.queue) > 0 && w.queueBytes+len(p) > w.bufferSize {
+				w.popFront()
+				w.dropped++
+			}
+			if w.queueBytes+len(p) > w.bufferSize {
+				// 剩余空间被正在补发的日志占用, 无法再腾出
+				w.dropped++
+				return len(p), nil
+			}
+		case OverflowBlock:
+			w.cond.Wait()
+			continue // 重新检查状态: 可能已重连或已关闭
+		default:
+			w.dropped++
+			return len(p), nil
+		}
+		break
+	}
+
+	msg := make([]byte, len(p))
+	copy(msg, p)
+	w.queue = append(w.queue, msg)
+	w.queueBytes += len(msg)
+	return len(p), nil
+}
+
+// send 发送一条日志 (调用方需持有锁)
+//
+// 返回:
+//   - int: 实际写入连接的字节数, 失败时可能只写入了一部分
+//   - error: 发送失败时返回
+func (w *NetWriter) send(p []byte) (int, error) {
+	return w.writeFrame(w.conn, w.stream, &w.frame, p)
+}
+
+// writeFrame 分帧后将一条日志写入指定连接
+//
+// 参数:
+//   - conn: 目标连接
+//   - stream: 是否为流式连接
+//   - buf: 分帧的复用缓冲区
+//   - p: 要发送的日志
+//
+// 返回:
+//   - int: 实际写入连接的字节数, 失败时可能只写入了一部分
+//   - error: 发送失败时返回
+func (w *NetWriter) writeFrame(conn net.Conn, stream bool, buf *[]byte, p []byte) (int, error) {
+	if w.framer != nil {
+		*buf = w.framer((*buf)[:0], p, stream)
+		p = *buf
+	}
+	if w.writeTimeout > 0 {
+		_ = conn.SetWriteDeadline(time.Now().Add(w.writeTimeout))
+	}
+	return conn.Write(p)
+}
+
+// popFront 移除最早缓冲的日志 (调用方需持有锁)
+func (w *NetWriter) popFront() {
+	w.queueBytes -= len(w.queue[0])
+	w.queue[0] = nil
+	w.queue = w.queue[1:]
+}
+
+// disconnect 关闭当前连接并通知后台协程重连 (调用方需持有锁)
+func (w *NetWriter) disconnect() {
+	if w.conn != nil {
+		_ = w.conn.Close()
+		w.conn = nil
+	}
+	w.state = NetDisconnected
+	select {
+	case w.kick <- struct{}{}:
+	default:
+	}
+}
+
+// run 后台重连协程, 收到通知后按指数退避重连直到成功或关闭
+func (w *NetWriter) run() {
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-w.kick:
+		}
+
+		backoff := w.minBackoff
+		for !w.attach(w.dial()) {
+			timer := time.NewTimer(jitter(backoff))
+			select {
+			case <-w.done:
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+			backoff = min(backoff*2, w.maxBackoff)
+		}
+	}
+}
+
+// dial 建立连接
+//
+// 返回:
+//   - net.Conn: 新建立的连接
+//   - bool: 是否为流式连接
+//   - error: 连接失败时返回
+func (w *NetWriter) dial() (net.Conn, bool, error) {
+	if w.dialer != nil {
+		return w.dialer()
+	}
+	conn, err := net.DialTimeout(w.network, w.addr, w.dialTimeout)
+	if err != nil {
+		return nil, false, err
+	}
+	switch w.network {
+	case "tcp", "tcp4", "tcp6", "unix":
+		return conn, true, nil
+	default:
+		return conn, false, nil
+	}
+}
+
+// attach 补发缓冲的日志并启用连接
+//
+// 参数:
+//   - conn: 新建立的连接
+//   - stream: 是否为流式连接
+//   - err: 连接错误, 非 nil 表示连接失败
+//
+// 返回:
+//   - bool: 连接可用、已连接或写入器已关闭时返回 true, 需要继续重连时返回 false
+func (w *NetWriter) attach(conn net.Conn, stream bool, err error) bool {
+	if err != nil {
+		return false
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.state != NetDisconnected {
+		_ = conn.Close()
+		return true
+	}
+
+	// 按原顺序补发: 在锁内取走缓冲区, 在锁外发送, 期间 w.conn 仍为 nil,
+	// 新写入继续排在缓冲区中, 本批发送完后再补发, 直到缓冲区为空才启用连接
+	w.replay = conn
+	for len(w.queue) > 0 {
+		batch := w.queue
+		w.queue, w.inflight = nil, len(batch)
+		w.mu.Unlock()
+		sent, lost, err := w.replayBatch(conn, stream, batch)
+		w.mu.Lock()
+		w.inflight = 0
+
+		if w.state == NetClosed {
+			// Close 已清空缓冲区字节数, 只需将未发出的日志计入丢弃
+			w.written += uint64(sent)
+			w.dropped += uint64(len(batch) - sent)
+			w.replay = nil
+			_ = conn.Close()
+			return true
+		}
+
+		// 未发出的日志保留在缓冲区头部等待下次重连, 只发出一部分的日志丢弃
+		done := sent + lost
+		for _, msg := range batch[:done] {
+			w.queueBytes -= len(msg)
+		}
+		w.written += uint64(sent)
+		w.dropped += uint64(lost)
+		if done < len(batch) {
+			w.queue = append(batch[done:], w.queue...)
+		}
+		w.cond.Broadcast()
+
+		if err != nil {
+			w.replay = nil
+			_ = conn.Close()
+			return false
+		}
+	}
+	w.queue = nil
+	w.replay = nil
+	w.conn, w.stream = conn, stream
+
+	if stream {
+		w.dead = &atomic.Bool{}
+		go watchConn(conn, w.dead)
+	} else {
+		w.dead = nil
+	}
+	if w.connected {
+		w.reconnects++
+	}
+	w.connected = true
+	w.state = NetConnected
+	w.cond.Broadcast()
+	return true
+}
+
+// replayBatch 按顺序补发一批缓冲的日志 (调用方不持有锁)
+//
+// 参数:
+//   - conn: 补发使用的连接
+//   - stream: 是否为流式连接
+//   - batch: 要补发的日志
+//
+// 返回:
+//   - int: 成功发送的条数
+//   - int: 只发出一部分而丢弃的条数 (0 或 1)
+//   - error: 发送失败时返回, 此时 batch 中其余日志未发送
+func (w *NetWriter) replayBatch(conn net.Conn, stream bool, batch [][]byte) (int, int, error) {
+	var frame []byte
+	for i, msg := range batch {
+		if n, err := w.writeFrame(conn, stream, &frame, msg); err != nil {
+			if n > 0 {
+				return i, 1, err
+			}
+			return i, 0, err
+		}
+	}
+	return len(batch), 0, nil
+}
+
+// watchConn 监测流式连接是否已被对端关闭
+//
+// 日志接收端通常不会向客户端发送数据, 读取返回 (通常为 EOF 或连接被关闭) 即表示连接已不可用。
+// 未监测时, 写入已被对端关闭的 TCP 连接可能先成功一次, 导致该条日志丢失。
+func watchConn(conn net.Conn, dead *atomic.Bool) {
+	var buf [1]byte
+	_, _ = conn.Read(buf[:])
+	dead.Store(true)
+}
+
+// jitter 在退避时间上增加 ±20% 的随机抖动, 避免大量客户端同时重连
+func jitter(d time.Duration) time.Duration {
+	delta := int64(d) / 5
+	if delta <= 0 {
+		return d
+	}
+	return d + time.Duration(rand.Int64N(2*delta+1)-delta)
+}
+
+// State 返回当前连接状态
+//
+// 返回:
+//   - NetState: 连接状态
+func (w *NetWriter) State() NetState {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.state
+}
+
+// Stats 返回统计信息快照
+//
+// 返回:
+//   - NetWriterStats: 统计信息
+func (w *NetWriter) Stats() NetWriterStats {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return NetWriterStats{
+		State:         w.state,
+		Written:       w.written,
+		Dropped:       w.dropped,
+		Reconnects:    w.reconnects,
+		Buffered:      len(w.queue) + w.inflight,
+		BufferedBytes: w.queueBytes,
+	}
+}
+
+// Close 关闭网络写入器
+//
+// 停止后台重连, 关闭连接。尚未发送的缓冲日志计入丢弃数, 阻塞中的写入返回错误。
+//
+// 返回:
+//   - error: 关闭连接时的错误, 重复关闭返回 nil
+func (w *NetWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.state == NetClosed {
+		return nil
+	}
+	w.state = NetClosed
+	close(w.done)
+	w.cond.Broadcast()
+
+	w.dropped += uint64(len(w.queue))
+	w.queue, w.queueBytes = nil, 0
+	if w.replay != nil {
+		// 中断正在进行的补发, 补发协程负责统计未发出的日志
+		_ = w.replay.Close()
+	}
+
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
@@ -0,0 +1,373 @@
+package fastlog
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// waitFor 轮询等待条件成立, 超时则失败
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timeout waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// readLines 从连接读取 n 行
+func readLines(t *testing.T, conn net.Conn, n int) []string {
+	t.Helper()
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	r := bufio.NewReader(conn)
+	lines := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read line %d: %v", i, err)
+		}
+		lines = append(lines, strings.TrimSuffix(line, "\n"))
+	}
+	return lines
+}
+
+// unreachableSocket 返回一个不存在的 unix 套接字路径, 连接始终失败
+func unreachableSocket(t *testing.T) string {
+	dir, err := os.MkdirTemp("", "fastlog-net")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	return filepath.Join(dir, "missing.sock")
+}
+
+func TestNetWriterBufferAndReconnect(t *testing.T) {
+	// 先占用端口再释放, 让写入器在目标不可用时启动
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("tcp not available: %v", err)
+	}
+	addr := ln.Addr().String()
+	_ = ln.Close()
+
+	w, err := NewNetWriter(NetWriterConfig{Network: "tcp", Addr: addr, MinBackoff: 10 * time.Millisecond, MaxBackoff: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewNetWriter() error = %v", err)
+	}
+	defer func() { _ = w.Close() }()
+
+	for _, msg := range []string{"one\n", "two\n", "three\n"} {
+		if _, err := w.Write([]byte(msg)); err != nil {
+			t.Fatalf("Write() while disconnected error = %v", err)
+		}
+	}
+	if st := w.Stats(); st.State != NetDisconnected || st.Buffered != 3 || st.BufferedBytes != 14 {
+		t.Fatalf("stats while disconnected = %+v", st)
+	}
+
+	// 目标恢复后补发缓冲的日志
+	ln, err = net.Listen("tcp", addr)
+	if err != nil {
+		t.Skipf("cannot rebind %s: %v", addr, err)
+	}
+	defer func() { _ = ln.Close() }()
+	conn, err := ln.Accept()
+	if err != nil {
+		t.Fatalf("Accept() error = %v", err)
+	}
+	if got := strings.Join(readLines(t, conn, 3), ","); got != "one,two,three" {
+		t.Errorf("flushed lines = %q", got)
+	}
+	waitFor(t, "connected", func() bool { return w.State() == NetConnected })
+
+	// 对端断开后自动重连, 断开期间的日志不丢失
+	_ = conn.Close()
+	waitFor(t, "peer close detected", func() bool {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		return w.dead != nil && w.dead.Load()
+	})
+	_, _ = w.Write([]byte("four\n"))
+	conn, err = ln.Accept()
+	if err != nil {
+		t.Fatalf("Accept() error = %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+	if got := readLines(t, conn, 1)[0]; got != "four" {
+		t.Errorf("line after reconnect = %q", got)
+	}
+	waitFor(t, "reconnected", func() bool { return w.State() == NetConnected })
+
+	st := w.Stats()
+	if st.Written != 4 || st.Dropped != 0 || st.Reconnects != 1 || st.Buffered != 0 {
+		t.Errorf("final stats = %+v", st)
+	}
+}
+
+func TestNetWriterOverflow(t *testing.T) {
+	newWriter := func(policy OverflowPolicy) *NetWriter {
+		w, err := NewNetWriter(NetWriterConfig{Network: "unix", Addr: unreachableSocket(t), BufferSize: 10, Overflow: policy, MinBackoff: time.Hour})
+		if err != nil {
+			t.Fatalf("NewNetWriter() error = %v", err)
+		}
+		return w
+	}
+	queued := func(w *NetWriter) string {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		var parts []string
+		for _, m := range w.queue {
+			parts = append(parts, string(m))
+		}
+		return strings.Join(parts, ",")
+	}
+
+	t.Run("drop newest", func(t *testing.T) {
+		w := newWriter(OverflowDropNewest)
+		defer func() { _ = w.Close() }()
+		for _, m := range []string{"aaaa", "bbbb", "cccc", "this is too large"} {
+			_, _ = w.Write([]byte(m))
+		}
+		if got := queued(w); got != "aaaa,bbbb" || w.Stats().Dropped != 2 {
+			t.Errorf("queue = %q, stats = %+v", got, w.Stats())
+		}
+	})
+
+	t.Run("drop oldest", func(t *testing.T) {
+		w := newWriter(OverflowDropOldest)
+		defer func() { _ = w.Close() }()
+		for _, m := range []string{"aaaa", "bbbb", "cccc", "dd"} {
+			_, _ = w.Write([]byte(m))
+		}
+		if got := queued(w); got != "bbbb,cccc,dd" || w.Stats().Dropped != 1 {
+			t.Errorf("queue = %q, stats = %+v", got, w.Stats())
+		}
+	})
+
+	t.Run("block until close", func(t *testing.T) {
+		w := newWriter(OverflowBlock)
+		_, _ = w.Write([]byte("aaaaaaaa"))
+		errc := make(chan error, 1)
+		go func() {
+			_, err := w.Write([]byte("bbbb"))
+			errc <- err
+		}()
+		select {
+		case err := <-errc:
+			t.Fatalf("Write() should block when buffer is full, got %v", err)
+		case <-time.After(50 * time.Millisecond):
+		}
+		_ = w.Close()
+		select {
+		case err := <-errc:
+			if err == nil {
+				t.Error("blocked Write() should fail after Close")
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("Close() did not release blocked Write()")
+		}
+		if st := w.Stats(); st.State != NetClosed || st.Dropped != 1 {
+			t.Errorf("stats after close = %+v", st)
+		}
+	})
+}
+
+// shortConn 只接受 limit 字节的测试连接, 超出部分写入失败
+type shortConn struct {
+	net.Conn
+	limit int
+	got   []byte
+}
+
+func (c *shortConn) Write(p []byte) (int, error) {
+	n := min(len(p), c.limit)
+	c.limit -= n
+	c.got = append(c.got, p[:n]...)
+	if n < len(p) {
+		return n, errors.New("broken pipe")
+	}
+	return n, nil
+}
+
+func (c *shortConn) SetWriteDeadline(time.Time) error { return nil }
+
+func (c *shortConn) Close() error { return nil }
+
+func TestNetWriterPartialWrite(t *testing.T) {
+	// 不启动后台协程, 由测试直接提供连接
+	w := newNetWriter(NetWriterConfig{Network: "tcp", Addr: "127.0.0.1:1"})
+	defer func() { _ = w.Close() }()
+
+	first := &shortConn{limit: 8}
+	w.attach(first, false, nil)
+	_, _ = w.Write([]byte("one\n"))
+	_, _ = w.Write([]byte("partial\n")) // 只写出 4 字节, 不能重新缓冲
+	_, _ = w.Write([]byte("queued\n"))
+	if string(first.got) != "one\npart" {
+		t.Errorf("first conn got %q", first.got)
+	}
+	if st := w.Stats(); st.Written != 1 || st.Dropped != 1 || st.Buffered != 1 {
+		t.Errorf("stats after partial write = %+v", st)
+	}
+
+	// 补发时只写出一部分的日志同样丢弃, 不会在下次重连时重发
+	if w.attach(&shortConn{limit: 3}, false, nil) {
+		t.Fatal("attach() should fail when replay is cut short")
+	}
+	second := &shortConn{limit: 100}
+	if !w.attach(second, false, nil) {
+		t.Fatal("attach() should succeed with an empty buffer")
+	}
+	if st := w.Stats(); len(second.got) != 0 || st.Dropped != 2 || st.Buffered != 0 {
+		t.Errorf("second conn got %q, stats = %+v", second.got, st)
+	}
+}
+
+// gateConn 第一次写入阻塞到 release 关闭的测试连接
+type gateConn struct {
+	net.Conn
+	started chan struct{}
+	release chan struct{}
+	once    sync.Once
+	mu      sync.Mutex
+	got     []byte
+}
+
+func (c *gateConn) Write(p []byte) (int, error) {
+	c.once.Do(func() {
+		close(c.started)
+		<-c.release
+	})
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.got = append(c.got, p...)
+	return len(p), nil
+}
+
+func (c *gateConn) SetWriteDeadline(time.Time) error { return nil }
+
+func (c *gateConn) Close() error { return nil }
+
+func (c *gateConn) String() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return string(c.got)
+}
+
+func TestNetWriterReplayOutsideLock(t *testing.T) {
+	w := newNetWriter(NetWriterConfig{Network: "tcp", Addr: "127.0.0.1:1"})
+	defer func() { _ = w.Close() }()
+
+	_, _ = w.Write([]byte("a\n"))
+	_, _ = w.Write([]byte("b\n"))
+
+	conn := &gateConn{started: make(chan struct{}), release: make(chan struct{})}
+	attached := make(chan bool, 1)
+	go func() { attached <- w.attach(conn, false, nil) }()
+	<-conn.started
+
+	// 补发期间写入不阻塞, 排在补发的日志之后
+	wrote := make(chan struct{})
+	go func() {
+		_, _ = w.Write([]byte("c\n"))
+		close(wrote)
+	}()
+	select {
+	case <-wrote:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Write() blocked while the backlog was being replayed")
+	}
+	if st := w.Stats(); st.State != NetDisconnected || st.Buffered != 3 {
+		t.Errorf("stats during replay = %+v", st)
+	}
+
+	close(conn.release)
+	if !<-attached {
+		t.Fatal("attach() should succeed")
+	}
+	_, _ = w.Write([]byte("d\n"))
+	if got := conn.String(); got != "a\nb\nc\nd\n" {
+		t.Errorf("conn got %q", got)
+	}
+	if st := w.Stats(); st.State != NetConnected || st.Written != 4 || st.Buffered != 0 || st.BufferedBytes != 0 {
+		t.Errorf("stats after replay = %+v", st)
+	}
+}
+
+func TestNetWriterUDP(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("udp not available: %v", err)
+	}
+	defer func() { _ = pc.Close() }()
+
+	w, err := NewNetWriter(NetWriterConfig{Network: "udp", Addr: pc.LocalAddr().String()})
+	if err != nil {
+		t.Fatalf("NewNetWriter() error = %v", err)
+	}
+	defer func() { _ = w.Close() }()
+	waitFor(t, "connected", func() bool { return w.State() == NetConnected })
+
+	_, _ = w.Write([]byte("datagram\n"))
+	buf := make([]byte, 64)
+	_ = pc.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := pc.ReadFrom(buf)
+	if err != nil || string(buf[:n]) != "datagram\n" {
+		t.Errorf("ReadFrom() = %q, %v", buf[:n], err)
+	}
+}
+
+func TestLoggerNetOutput(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("tcp not available: %v", err)
+	}
+	defer func() { _ = ln.Close() }()
+
+	l := New(&Config{Formatter: Logfmt{}, Net: &NetWriterConfig{Network: "tcp", Addr: ln.Addr().String()}})
+	defer func() { _ = l.Close() }()
+	l.Infow("shipped", String("to", "collector"))
+
+	conn, err := ln.Accept()
+	if err != nil {
+		t.Fatalf("Accept() error = %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+	if got := readLines(t, conn, 1)[0]; !strings.HasSuffix(got, "level=INFO message=shipped to=collector") {
+		t.Errorf("received %q", got)
+	}
+}
+
+func TestConfigNetValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		net     *NetWriterConfig
+		wantErr bool
+	}{
+		{"tcp", &NetWriterConfig{Network: "tcp", Addr: "localhost:5170"}, false},
+		{"missing addr", &NetWriterConfig{Network: "udp"}, true},
+		{"bad network", &NetWriterConfig{Network: "http", Addr: "x"}, true},
+		{"negative buffer", &NetWriterConfig{Network: "tcp", Addr: "x:1", BufferSize: -1}, true},
+		{"bad policy", &NetWriterConfig{Network: "tcp", Addr: "x:1", Overflow: 7}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Net: tt.net}
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+	if NetConnected.String() != "connected" || NetState(9).String() != "NetState(9)" {
+		t.Error("NetState.String() mismatch")
+	}
+}
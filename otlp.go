@@ -0,0 +1,441 @@
+package fastlog
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OTLPSeverity 返回日志级别对应的 OpenTelemetry 严重性编号
+//
+// 对应关系: DEBUG → 5, INFO → 9, WARN → 13, ERROR → 17, FATAL → 21, PANIC → 22,
+// 自定义级别按位次 (LevelSpec.Rank) 所在区间映射, 如介于 INFO 和 WARN 之间的 NOTICE → 9,
+// 低于 DEBUG 的 (如 TRACE) → 1, 高于 PANIC 的 → 22。
+//
+// 参数:
+//   - l: 日志级别
+//
+// 返回:
+//   - int: 严重性编号, 范围 1~24, 未知级别返回 0 (UNSPECIFIED)
+func OTLPSeverity(l Level) int {
+	rank := levelRank(l)
+	switch {
+	case l == 0:
+		return 0 // UNSPECIFIED
+	case rank >= levelRank(PANIC):
+		return 22 // FATAL2
+	case rank >= levelRank(FATAL):
+		return 21 // FATAL
+	case rank >= levelRank(ERROR):
+		return 17 // ERROR
+	case rank >= levelRank(WARN):
+		return 13 // WARN
+	case rank >= levelRank(INFO):
+		return 9 // INFO
+	case rank >= levelRank(DEBUG):
+		return 5 // DEBUG
+	default:
+		return 1 // TRACE
+	}
+}
+
+// otlpEncoder 将日志条目编码为 OTLP LogRecord, 并将批次包装为 ExportLogsServiceRequest
+//
+// 单条日志在记录时即编码为 LogRecord (JSON 对象或 protobuf 消息), 批次发送时只需拼接。
+// 日志记录器名称作为 InstrumentationScope 名称, 同一批次内按名称分组。
+type otlpEncoder struct {
+	protobuf bool   // 是否使用 protobuf 编码
+	traceKey string // 链路 ID 字段键名
+	spanKey  string // Span ID 字段键名
+	resource []byte // 预编码的 Resource 消息 (JSON 对象或 protobuf 消息体)
+}
+
+// newOTLPEncoder 根据配置创建 OTLP 编码器
+//
+// 参数:
+//   - cfg: HTTP 批量发送配置
+//
+// 返回:
+//   - *otlpEncoder: OTLP 编码器
+func newOTLPEncoder(cfg *HTTPSinkConfig) *otlpEncoder {
+	e := &otlpEncoder{
+		protobuf: cfg.Format == HTTPOTLPProtobuf,
+		traceKey: orDefault(cfg.TraceIDKey, DefaultTraceIDKey),
+		spanKey:  orDefault(cfg.SpanIDKey, DefaultSpanIDKey),
+	}
+
+	// 资源属性: service.name 在前, 其余按键名排序
+	attrs := []string{"service.name", orDefault(cfg.ServiceName, filepath.Base(os.Args[0]))}
+	keys := make([]string, 0, len(cfg.ResourceAttributes))
+	for k := range cfg.ResourceAttributes {
+		if k != "service.name" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		attrs = append(attrs, k, cfg.ResourceAttributes[k])
+	}
+
+	if e.protobuf {
+		for i := 0; i < len(attrs); i += 2 {
+			kv := appendPBString(nil, 1, attrs[i])
+			kv = appendPBBytes(kv, 2, appendPBString(nil, 1, attrs[i+1]))
+			e.resource = appendPBBytes(e.resource, 1, kv)
+		}
+		return e
+	}
+	e.resource = append(e.resource, `{"attributes":[`...)
+	for i := 0; i < len(attrs); i += 2 {
+		if i > 0 {
+			e.resource = append(e.resource, ',')
+		}
+		e.resource = append(e.resource, `{"key":`...)
+		e.resource = appendJSONString(e.resource, attrs[i])
+		e.resource = append(e.resource, `,"value":{"stringValue":`...)
+		e.resource = appendJSONString(e.resource, attrs[i+1])
+		e.resource = append(e.resource, "}}"...)
+	}
+	e.resource = append(e.resource, "]}"...)
+	return e
+}
+
+// otlpAttr 一个待编码的属性
+type otlpAttr struct {
+	key   string // 属性键, 命名空间以点号拼接
+	field Field  // 属性值
+}
+
+// appendRecord 将日志条目编码为 LogRecord 并追加到 dst
+//
+// 参数:
+//   - dst: 目标缓冲区
+//   - entry: 日志条目
+//   - observed: 观测时间, 即日志被导出器接收的时间
+//
+// 返回:
+//   - []byte: 追加后的缓冲区
+func (e *otlpEncoder) appendRecord(dst []byte, entry *Entry, observed time.Time) []byte {
+	// 拆分链路字段和普通属性
+	var traceID, spanID []byte
+	attrs := make([]otlpAttr, 0, len(entry.Fields)+3)
+	var ns string
+	for _, f := range entry.Fields {
+		if f.typ == NamespaceType {
+			ns = joinNamespace(ns, f.key)
+			continue
+		}
+		if f.typ == LogValuerType {
+			f = f.resolve()
+		}
+		if ns == "" && f.typ == StringType {
+			if f.key == e.traceKey {
+				if id := decodeTraceID(f.stringVal, 16); id != nil {
+					traceID = id
+					continue
+				}
+			} else if f.key == e.spanKey {
+				if id := decodeTraceID(f.stringVal, 8); id != nil {
+					spanID = id
+					continue
+				}
+			}
+		}
+		key := f.key
+		if ns != "" {
+			key = ns + "." + f.key
+		}
+		attrs = append(attrs, otlpAttr{key: key, field: f})
+	}
+
+	// 调用者信息按语义约定拆分为 code.* 属性
+	if file, fn, line, ok := splitCaller(entry.Caller); ok {
+		attrs = append(attrs,
+			otlpAttr{key: "code.filepath", field: String("", file)},
+			otlpAttr{key: "code.function", field: String("", fn)},
+			otlpAttr{key: "code.lineno", field: Int64("", line)},
+		)
+	}
+
+	if e.protobuf {
+		return e.appendRecordPB(dst, entry, observed, attrs, traceID, spanID)
+	}
+	return e.appendRecordJSON(dst, entry, observed, attrs, traceID, spanID)
+}
+
+// appendRecordJSON 以 OTLP/JSON 编码 LogRecord
+func (e *otlpEncoder) appendRecordJSON(dst []byte, entry *Entry, observed time.Time, attrs []otlpAttr, traceID, spanID []byte) []byte {
+	dst = append(dst, `{"timeUnixNano":"`...)
+	dst = strconv.AppendInt(dst, entry.Time.UnixNano(), 10)
+	dst = append(dst, `","observedTimeUnixNano":"`...)
+	dst = strconv.AppendInt(dst, observed.UnixNano(), 10)
+	dst = append(dst, '"')
+	if sev := OTLPSeverity(entry.Level); sev > 0 {
+		dst = append(dst, `,"severityNumber":`...)
+		dst = strconv.AppendInt(dst, int64(sev), 10)
+		dst = append(dst, `,"severityText":`...)
+		dst = appendJSONString(dst, entry.Level.String())
+	}
+	dst = append(dst, `,"body":{"stringValue":`...)
+	dst = appendJSONString(dst, entry.Message)
+	dst = append(dst, '}')
+	if len(attrs) > 0 {
+		dst = append(dst, `,"attributes":[`...)
+		for i, a := range attrs {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = append(dst, `{"key":`...)
+			dst = appendJSONString(dst, a.key)
+			dst = append(dst, `,"value":`...)
+			dst = appendOTLPValueJSON(dst, a.field, entry.TimeFormat)
+			dst = append(dst, '}')
+		}
+		dst = append(dst, ']')
+	}
+	if traceID != nil {
+		dst = append(dst, `,"traceId":"`...)
+		dst = hex.AppendEncode(dst, traceID)
+		dst = append(dst, '"')
+	}
+	if spanID != nil {
+		dst = append(dst, `,"spanId":"`...)
+		dst = hex.AppendEncode(dst, spanID)
+		dst = append(dst, '"')
+	}
+	return append(dst, '}')
+}
+
+// appendOTLPValueJSON 以 OTLP/JSON 编码 AnyValue
+func appendOTLPValueJSON(dst []byte, f Field, tf string) []byte {
+	switch f.typ {
+	case IntType, Int64Type:
+		dst = append(dst, `{"intValue":"`...)
+		dst = strconv.AppendInt(dst, f.intVal, 10)
+		return append(dst, `"}`...)
+	case UintType, Uint64Type:
+		if f.uintVal <= math.MaxInt64 {
+			dst = append(dst, `{"intValue":"`...)
+			dst = strconv.AppendUint(dst, f.uintVal, 10)
+			return append(dst, `"}`...)
+		}
+	case Float64Type:
+		dst = append(dst, `{"doubleValue":`...)
+		switch {
+		case math.IsNaN(f.floatVal):
+			dst = append(dst, `"NaN"`...)
+		case math.IsInf(f.floatVal, 1):
+			dst = append(dst, `"Infinity"`...)
+		case math.IsInf(f.floatVal, -1):
+			dst = append(dst, `"-Infinity"`...)
+		default:
+			dst = strconv.AppendFloat(dst, f.floatVal, 'g', -1, 64)
+		}
+		return append(dst, '}')
+	case BoolType:
+		dst = append(dst, `{"boolValue":`...)
+		dst = strconv.AppendBool(dst, f.boolVal)
+		return append(dst, '}')
+	}
+	dst = append(dst, `{"stringValue":`...)
+	dst = appendJSONString(dst, otlpStringValue(f, tf))
+	return append(dst, '}')
+}
+
+// appendRecordPB 以 protobuf 编码 LogRecord
+func (e *otlpEncoder) appendRecordPB(dst []byte, entry *Entry, observed time.Time, attrs []otlpAttr, traceID, spanID []byte) []byte {
+	dst = appendPBFixed64(dst, 1, uint64(entry.Time.UnixNano()))
+	if sev := OTLPSeverity(entry.Level); sev > 0 {
+		dst = appendPBVarintField(dst, 2, uint64(sev))
+		dst = appendPBString(dst, 3, entry.Level.String())
+	}
+	dst = appendPBBytes(dst, 5, appendPBString(nil, 1, entry.Message))
+	for _, a := range attrs {
+		kv := appendPBString(nil, 1, a.key)
+		kv = appendPBBytes(kv, 2, appendOTLPValuePB(nil, a.field, entry.TimeFormat))
+		dst = appendPBBytes(dst, 6, kv)
+	}
+	if traceID != nil {
+		dst = appendPBBytes(dst, 9, traceID)
+	}
+	if spanID != nil {
+		dst = appendPBBytes(dst, 10, spanID)
+	}
+	return appendPBFixed64(dst, 11, uint64(observed.UnixNano()))
+}
+
+// appendOTLPValuePB 以 protobuf 编码 AnyValue
+func appendOTLPValuePB(dst []byte, f Field, tf string) []byte {
+	switch f.typ {
+	case IntType, Int64Type:
+		return appendPBVarintField(dst, 3, uint64(f.intVal))
+	case UintType, Uint64Type:
+		if f.uintVal <= math.MaxInt64 {
+			return appendPBVarintField(dst, 3, f.uintVal)
+		}
+	case Float64Type:
+		return appendPBFixed64(dst, 4, math.Float64bits(f.floatVal))
+	case BoolType:
+		v := uint64(0)
+		if f.boolVal {
+			v = 1
+		}
+		return appendPBVarintField(dst, 2, v)
+	}
+	return appendPBString(dst, 1, otlpStringValue(f, tf))
+}
+
+// otlpStringValue 返回以字符串属性输出的字段值, 复合值编码为 JSON
+func otlpStringValue(f Field, tf string) string {
+	switch f.typ {
+	case AnyType:
+		if s, ok := f.iface.(string); ok {
+			return s
+		}
+		return string(encodeJSON(f, tf))
+	case ObjectType, ArrayType:
+		return string(encodeJSON(f, tf))
+	}
+	return f.valueWithTimeFormat(tf)
+}
+
+// appendBatch 将批次编码为 ExportLogsServiceRequest
+//
+// 参数:
+//   - dst: 目标缓冲区
+//   - records: 已编码的日志记录, stream 为日志记录器名称
+//
+// 返回:
+//   - []byte: 追加后的缓冲区
+func (e *otlpEncoder) appendBatch(dst []byte, records []httpRecord) []byte {
+	// 按日志记录器名称分组, 保持首次出现的顺序
+	var order []string
+	grouped := make(map[string][]httpRecord)
+	for _, rec := range records {
+		if _, ok := grouped[rec.stream]; !ok {
+			order = append(order, rec.stream)
+		}
+		grouped[rec.stream] = append(grouped[rec.stream], rec)
+	}
+
+	if e.protobuf {
+		// ExportLogsServiceRequest{1: ResourceLogs{1: Resource, 2: ScopeLogs{1: Scope, 2: LogRecord}}}
+		resourceLogs := appendPBBytes(nil, 1, e.resource)
+		for _, name := range order {
+			var scope []byte
+			if name != "" {
+				scope = appendPBString(nil, 1, name)
+			}
+			scopeLogs := appendPBBytes(nil, 1, scope)
+			for _, rec := range grouped[name] {
+				scopeLogs = appendPBBytes(scopeLogs, 2, rec.line)
+			}
+			resourceLogs = appendPBBytes(resourceLogs, 2, scopeLogs)
+		}
+		return appendPBBytes(dst, 1, resourceLogs)
+	}
+
+	dst = append(dst, `{"resourceLogs":[{"resource":`...)
+	dst = append(dst, e.resource...)
+	dst = append(dst, `,"scopeLogs":[`...)
+	for i, name := range order {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+		dst = append(dst, `{"scope":{`...)
+		if name != "" {
+			dst = append(dst, `"name":`...)
+			dst = appendJSONString(dst, name)
+		}
+		dst = append(dst, `},"logRecords":[`...)
+		for j, rec := range grouped[name] {
+			if j > 0 {
+				dst = append(dst, ',')
+			}
+			dst = append(dst, rec.line...)
+		}
+		dst = append(dst, "]}"...)
+	}
+	return append(dst, "]}]}"...)
+}
+
+// decodeTraceID 解码十六进制链路标识, 长度不符或全零时返回 nil
+//
+// 参数:
+//   - s: 十六进制字符串
+//   - n: 期望的字节数, 链路 ID 为 16, Span ID 为 8
+//
+// 返回:
+//   - []byte: 解码后的字节
+func decodeTraceID(s string, n int) []byte {
+	if len(s) != n*2 {
+		return nil
+	}
+	id, err := hex.DecodeString(s)
+	if err != nil {
+		return nil
+	}
+	for _, b := range id {
+		if b != 0 {
+			return id
+		}
+	}
+	return nil
+}
+
+// splitCaller 拆分 "文件名:函数名:行号" 格式的调用者信息
+func splitCaller(caller string) (file, fn string, line int64, ok bool) {
+	i := strings.LastIndexByte(caller, ':')
+	if i <= 0 {
+		return "", "", 0, false
+	}
+	line, err := strconv.ParseInt(caller[i+1:], 10, 64)
+	if err != nil {
+		return "", "", 0, false
+	}
+	rest := caller[:i]
+	j := strings.LastIndexByte(rest, ':')
+	if j < 0 {
+		return rest, "", line, true
+	}
+	return rest[:j], rest[j+1:], line, true
+}
+
+// ======== protobuf 编码辅助函数 ========
+
+// appendPBVarint 追加 varint 编码的整数
+func appendPBVarint(dst []byte, v uint64) []byte {
+	return binary.AppendUvarint(dst, v)
+}
+
+// appendPBVarintField 追加 varint 类型字段
+func appendPBVarintField(dst []byte, num int, v uint64) []byte {
+	dst = appendPBVarint(dst, uint64(num)<<3)
+	return appendPBVarint(dst, v)
+}
+
+// appendPBFixed64 追加 fixed64 类型字段
+func appendPBFixed64(dst []byte, num int, v uint64) []byte {
+	dst = appendPBVarint(dst, uint64(num)<<3|1)
+	return binary.LittleEndian.AppendUint64(dst, v)
+}
+
+// appendPBBytes 追加长度前缀类型字段 (bytes 或嵌套消息)
+func appendPBBytes(dst []byte, num int, b []byte) []byte {
+	dst = appendPBVarint(dst, uint64(num)<<3|2)
+	dst = appendPBVarint(dst, uint64(len(b)))
+	return append(dst, b...)
+}
+
+// appendPBString 追加 string 类型字段
+func appendPBString(dst []byte, num int, s string) []byte {
+	dst = appendPBVarint(dst, uint64(num)<<3|2)
+	dst = appendPBVarint(dst, uint64(len(s)))
+	return append(dst, s...)
+}
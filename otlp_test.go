@@ -0,0 +1,258 @@
+package fastlog
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// otlpRequest OTLP/JSON 请求体中测试关心的部分
+type otlpRequest struct {
+	ResourceLogs []struct {
+		Resource struct {
+			Attributes []otlpKeyValue `json:"attributes"`
+		} `json:"resource"`
+		ScopeLogs []struct {
+			Scope struct {
+				Name string `json:"name"`
+			} `json:"scope"`
+			LogRecords []struct {
+				TimeUnixNano         string         `json:"timeUnixNano"`
+				ObservedTimeUnixNano string         `json:"observedTimeUnixNano"`
+				SeverityNumber       int            `json:"severityNumber"`
+				SeverityText         string         `json:"severityText"`
+				Body                 map[string]any `json:"body"`
+				Attributes           []otlpKeyValue `json:"attributes"`
+				TraceID              string         `json:"traceId"`
+				SpanID               string         `json:"spanId"`
+			} `json:"logRecords"`
+		} `json:"scopeLogs"`
+	} `json:"resourceLogs"`
+}
+
+type otlpKeyValue struct {
+	Key   string         `json:"key"`
+	Value map[string]any `json:"value"`
+}
+
+// attrMap 将属性列表转为 键 → AnyValue 映射
+func attrMap(kvs []otlpKeyValue) map[string]map[string]any {
+	m := make(map[string]map[string]any, len(kvs))
+	for _, kv := range kvs {
+		m[kv.Key] = kv.Value
+	}
+	return m
+}
+
+func TestHTTPSinkOTLPJSON(t *testing.T) {
+	srv := newSinkServer(t)
+	l := New(&Config{Caller: true, HTTP: &HTTPSinkConfig{
+		URL:                srv.URL,
+		Format:             HTTPOTLPJSON,
+		ServiceName:        "order",
+		ResourceAttributes: map[string]string{"deployment.environment": "prod"},
+	}})
+
+	l.Named("api").Infow("created",
+		String("trace_id", "4bf92f3577b34da6a3ce929d0e0e4736"),
+		String("span_id", "00f067aa0ba902b7"),
+		Int("items", 3), Uint64("big", math.MaxUint64), Float64("ratio", 0.5), Bool("paid", true),
+		Namespace("user"), String("id", "u1"),
+	)
+	l.Named("db").Errorw("failed", String("trace_id", "not-hex"), Any("tags", []string{"a", "b"}))
+	_ = l.Close()
+
+	reqs := srv.received()
+	if len(reqs) != 1 {
+		t.Fatalf("requests = %d, want 1", len(reqs))
+	}
+	if ct := reqs[0].header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("content type = %q", ct)
+	}
+	var req otlpRequest
+	if err := json.Unmarshal(reqs[0].body, &req); err != nil {
+		t.Fatalf("invalid OTLP body: %v\n%s", err, reqs[0].body)
+	}
+	if len(req.ResourceLogs) != 1 || len(req.ResourceLogs[0].ScopeLogs) != 2 {
+		t.Fatalf("unexpected structure:\n%s", reqs[0].body)
+	}
+	res := attrMap(req.ResourceLogs[0].Resource.Attributes)
+	if res["service.name"]["stringValue"] != "order" || res["deployment.environment"]["stringValue"] != "prod" {
+		t.Errorf("resource attributes = %v", res)
+	}
+
+	api, db := req.ResourceLogs[0].ScopeLogs[0], req.ResourceLogs[0].ScopeLogs[1]
+	if api.Scope.Name != "api" || db.Scope.Name != "db" {
+		t.Errorf("scopes = %q, %q", api.Scope.Name, db.Scope.Name)
+	}
+	rec := api.LogRecords[0]
+	if rec.SeverityNumber != 9 || rec.SeverityText != "INFO" || rec.Body["stringValue"] != "created" {
+		t.Errorf("record = %+v", rec)
+	}
+	if rec.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || rec.SpanID != "00f067aa0ba902b7" {
+		t.Errorf("trace context = %q / %q", rec.TraceID, rec.SpanID)
+	}
+	if rec.TimeUnixNano == "" || rec.ObservedTimeUnixNano == "" {
+		t.Errorf("timestamps = %q / %q", rec.TimeUnixNano, rec.ObservedTimeUnixNano)
+	}
+	attrs := attrMap(rec.Attributes)
+	if _, ok := attrs["trace_id"]; ok {
+		t.Error("mapped trace_id should not be repeated as an attribute")
+	}
+	if attrs["items"]["intValue"] != "3" || attrs["ratio"]["doubleValue"] != 0.5 || attrs["paid"]["boolValue"] != true {
+		t.Errorf("typed attributes = %v", attrs)
+	}
+	if attrs["big"]["stringValue"] != "18446744073709551615" || attrs["user.id"]["stringValue"] != "u1" {
+		t.Errorf("uint/namespace attributes = %v", attrs)
+	}
+	if attrs["code.filepath"]["stringValue"] != "otlp_test.go" || attrs["code.lineno"]["intValue"] == nil {
+		t.Errorf("caller attributes = %v", attrs)
+	}
+
+	rec = db.LogRecords[0]
+	attrs = attrMap(rec.Attributes)
+	if rec.SeverityNumber != 17 || rec.TraceID != "" || attrs["trace_id"]["stringValue"] != "not-hex" {
+		t.Errorf("invalid trace id should stay an attribute: %+v", rec)
+	}
+	if attrs["tags"]["stringValue"] != `["a","b"]` {
+		t.Errorf("composite attribute = %v", attrs["tags"])
+	}
+}
+
+// pbField 解码后的 protobuf 字段
+type pbField struct {
+	num   int
+	wire  int
+	value uint64 // varint 或 fixed64
+	bytes []byte // 长度前缀类型
+}
+
+// decodePB 解码一层 protobuf 消息
+func decodePB(t *testing.T, b []byte) []pbField {
+	t.Helper()
+	var fields []pbField
+	for len(b) > 0 {
+		tag, n := binary.Uvarint(b)
+		if n <= 0 {
+			t.Fatalf("bad tag in %x", b)
+		}
+		b = b[n:]
+		f := pbField{num: int(tag >> 3), wire: int(tag & 7)}
+		switch f.wire {
+		case 0:
+			f.value, n = binary.Uvarint(b)
+			b = b[n:]
+		case 1:
+			f.value = binary.LittleEndian.Uint64(b)
+			b = b[8:]
+		case 2:
+			size, n := binary.Uvarint(b)
+			f.bytes = b[n : n+int(size)]
+			b = b[n+int(size):]
+		default:
+			t.Fatalf("unexpected wire type %d", f.wire)
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// pbGet 返回指定编号的全部字段
+func pbGet(fields []pbField, num int) []pbField {
+	var out []pbField
+	for _, f := range fields {
+		if f.num == num {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+func TestHTTPSinkOTLPProtobuf(t *testing.T) {
+	srv := newSinkServer(t)
+	sink, err := NewHTTPSink(&HTTPSinkConfig{URL: srv.URL, Format: HTTPOTLPProtobuf, ServiceName: "svc", Gzip: true})
+	if err != nil {
+		t.Fatalf("NewHTTPSink() error = %v", err)
+	}
+	ts := time.Unix(1700000000, 123)
+	_ = sink.fire(&Entry{Time: ts, Level: WARN, Message: "slow", Fields: []Field{
+		String("trace_id", "4bf92f3577b34da6a3ce929d0e0e4736"),
+		Int("ms", -5), Float64("load", 1.5),
+	}})
+	_ = sink.Close()
+
+	reqs := srv.received()
+	if len(reqs) != 1 || reqs[0].header.Get("Content-Type") != "application/x-protobuf" {
+		t.Fatalf("requests = %+v", reqs)
+	}
+
+	// ExportLogsServiceRequest → ResourceLogs → (Resource, ScopeLogs → LogRecord)
+	resourceLogs := decodePB(t, pbGet(decodePB(t, reqs[0].body), 1)[0].bytes)
+	resource := decodePB(t, pbGet(resourceLogs, 1)[0].bytes)
+	kv := decodePB(t, pbGet(resource, 1)[0].bytes)
+	if string(pbGet(kv, 1)[0].bytes) != "service.name" || string(pbGet(decodePB(t, pbGet(kv, 2)[0].bytes), 1)[0].bytes) != "svc" {
+		t.Errorf("resource = %+v", kv)
+	}
+	scopeLogs := decodePB(t, pbGet(resourceLogs, 2)[0].bytes)
+	rec := decodePB(t, pbGet(scopeLogs, 2)[0].bytes)
+
+	if got := pbGet(rec, 1)[0].value; got != uint64(ts.UnixNano()) {
+		t.Errorf("time_unix_nano = %d", got)
+	}
+	if pbGet(rec, 2)[0].value != 13 || string(pbGet(rec, 3)[0].bytes) != "WARN" {
+		t.Errorf("severity = %+v / %+v", pbGet(rec, 2), pbGet(rec, 3))
+	}
+	if body := decodePB(t, pbGet(rec, 5)[0].bytes); string(body[0].bytes) != "slow" {
+		t.Errorf("body = %+v", body)
+	}
+	if id := pbGet(rec, 9); len(id) != 1 || len(id[0].bytes) != 16 || id[0].bytes[0] != 0x4b {
+		t.Errorf("trace_id = %+v", id)
+	}
+	if len(pbGet(rec, 10)) != 0 || len(pbGet(rec, 11)) != 1 {
+		t.Errorf("span_id / observed time = %+v", rec)
+	}
+	attrs := pbGet(rec, 6)
+	if len(attrs) != 2 {
+		t.Fatalf("attributes = %d, want 2", len(attrs))
+	}
+	ms := decodePB(t, pbGet(decodePB(t, attrs[0].bytes), 2)[0].bytes)[0]
+	if ms.num != 3 || int64(ms.value) != -5 {
+		t.Errorf("int attribute = %+v", ms)
+	}
+	load := decodePB(t, pbGet(decodePB(t, attrs[1].bytes), 2)[0].bytes)[0]
+	if load.num != 4 || math.Float64frombits(load.value) != 1.5 {
+		t.Errorf("double attribute = %+v", load)
+	}
+}
+
+func TestHTTPSinkOTLPWriteAndRetry(t *testing.T) {
+	srv := newSinkServer(t, http.StatusServiceUnavailable)
+	sink, _ := NewHTTPSink(&HTTPSinkConfig{URL: srv.URL, Format: HTTPOTLPJSON, RetryBackoff: time.Millisecond})
+	_, _ = sink.Write([]byte("raw line\n"))
+	_ = sink.Close()
+
+	reqs := srv.received()
+	if st := sink.Stats(); st.Sent != 1 || st.Retries != 1 || len(reqs) != 2 {
+		t.Fatalf("stats = %+v, requests = %d", st, len(reqs))
+	}
+	var req otlpRequest
+	if err := json.Unmarshal(reqs[1].body, &req); err != nil {
+		t.Fatalf("invalid OTLP body: %v\n%s", err, reqs[1].body)
+	}
+	rec := req.ResourceLogs[0].ScopeLogs[0].LogRecords[0]
+	if rec.Body["stringValue"] != "raw line" || rec.SeverityNumber != 0 {
+		t.Errorf("record = %+v", rec)
+	}
+}
+
+func TestOTLPSeverity(t *testing.T) {
+	want := map[Level]int{DEBUG: 5, INFO: 9, WARN: 13, ERROR: 17, FATAL: 21, PANIC: 22, 0: 0}
+	for l, n := range want {
+		if got := OTLPSeverity(l); got != n {
+			t.Errorf("OTLPSeverity(%v) = %d, want %d", l, got, n)
+		}
+	}
+}
@@ -0,0 +1,325 @@
+package fastlog
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"slices"
+	"sync"
+)
+
+// Output 独立的日志输出目标
+//
+// 每个输出拥有自己的写入器、格式化器和最低级别, 与 OutputConsole、OutputFile 等
+// 传统输出并存。同一条日志按每个不同的格式化器只格式化一次, 由使用该格式化器的输出共享。
+//
+// 示例:
+//
+//	cfg := &fastlog.Config{
+//		Level: fastlog.DEBUG,
+//		Outputs: []fastlog.Output{
+//			{Writer: fastlog.NewColorWriter(false), Formatter: fastlog.Def{}, Level: fastlog.INFO},
+//			{Path: "logs/app.log", Formatter: fastlog.JSON{}, Level: fastlog.DEBUG},
+//		},
+//	}
+type Output struct {
+	// Writer 写入目标, 实现 io.Closer 时由 Logger.Close 关闭 (os.Stdout 和 os.Stderr 除外)
+	Writer io.Writer
+
+	// Path 日志文件路径, Writer 为 nil 时按 Config 的轮转和缓冲配置创建文件写入器
+	Path string
+
+	// Formatter 格式化器, nil 时使用 Config.Formatter
+	Formatter Formatter
+
+	// Level 最低级别, 零值表示不额外过滤, 仅受日志记录器级别控制
+	Level Level
+}
+
+// validate 验证输出配置
+//
+// 参数:
+//   - i: 输出在 Config.Outputs 中的下标, 用于错误信息
+//
+// 返回:
+//   - error: 验证通过时返回 nil
+func (o *Output) validate(i int) error {
+	if o.Writer == nil && o.Path == "" {
+		return fmt.Errorf("output %d: writer or path must be set", i)
+	}
+	if o.Writer != nil && o.Path != "" {
+		return fmt.Errorf("output %d: writer and path are mutually exclusive", i)
+	}
+	if _, ok := LookupLevel(o.Level); o.Level != 0 && !ok {
+		return fmt.Errorf("output %d: unknown level %d", i, o.Level)
+	}
+	return nil
+}
+
+// defaultOutputsLevel 返回 Config.Level 为零值时的默认级别
+//
+// 所有独立输出都设置了级别时取其中的最低级别, 否则为 INFO。
+//
+// 参数:
+//   - outputs: 独立输出列表
+//
+// 返回:
+//   - Level: 默认级别
+func defaultOutputsLevel(outputs []Output) Level {
+	if len(outputs) == 0 {
+		return INFO
+	}
+	lowest := outputs[0].Level
+	for _, o := range outputs {
+		if o.Level == 0 {
+			return INFO
+		}
+		lowest = min(lowest, o.Level)
+	}
+	return lowest
+}
+
+// output 已创建写入器的输出目标（内部使用）
+type output struct {
+	writer io.WriteCloser // 写入目标
+	level  Level          // 最低级别, 零值表示不过滤
+	slot   int            // 格式化器在 renderPlan.formatters 中的下标
+}
+
+// renderPlan 多格式化器输出计划（内部使用）, 与子日志记录器共享
+//
+// 未配置 Outputs 且级别路由未指定独立格式化器时为 nil, 日志走单格式化器的快速路径。
+type renderPlan struct {
+	formatters []Formatter // 去重后的格式化器, 下标 0 为 Config.Formatter
+	outputs    []output    // Config.Outputs 对应的输出
+	main       bool        // 主写入器是否有输出 (OutputConsole、OutputFile 或 Net)
+}
+
+// slotOf 返回格式化器的下标, 首次出现时追加
+//
+// 不可比较的格式化器 (如包含切片或函数的结构体) 不参与去重, 每次都分配新下标。
+//
+// 参数:
+//   - f: 格式化器, nil 表示 Config.Formatter
+//
+// 返回:
+//   - int: 格式化器下标
+func (p *renderPlan) slotOf(f Formatter) int {
+	if f == nil {
+		return 0
+	}
+	if reflect.TypeOf(f).Comparable() {
+		for i, g := range p.formatters {
+			// 动态类型不同时比较结果为 false, 相同时类型可比较, 不会 panic
+			if g == f {
+				return i
+			}
+		}
+	}
+	p.formatters = append(p.formatters, f)
+	return len(p.formatters) - 1
+}
+
+// renderHook 需要按独立格式化器输出的 hook（内部使用）
+type renderHook interface {
+	hook
+
+	// renderSlot 返回该日志需要的格式化器下标
+	// 参数:
+	//   - entry: 日志条目
+	// 返回:
+	//   - int: 格式化器下标
+	//   - bool: 该日志不需要输出时返回 false
+	renderSlot(entry *Entry) (int, bool)
+}
+
+// rendered 一条日志按各格式化器格式化后的数据（内部使用）
+type rendered struct {
+	level Level       // 日志级别, 用于按输出级别过滤
+	data  [][]byte    // 按格式化器下标存放, 未用到或格式化失败时为 nil
+	bufs  []*[]byte   // data 所属的池化缓冲区, 非追加式格式化器时为 nil
+	done  []bool      // 是否已尝试格式化
+	plan  *renderPlan // 所属输出计划
+}
+
+// renderedPool rendered 对象池
+var renderedPool = sync.Pool{New: func() any { return &rendered{} }}
+
+// newRendered 从池中获取 rendered 并按输出计划重置
+func newRendered(plan *renderPlan, level Level) *rendered {
+	r := renderedPool.Get().(*rendered)
+	n := len(plan.formatters)
+	if cap(r.data) < n {
+		r.data = make([][]byte, n)
+		r.bufs = make([]*[]byte, n)
+		r.done = make([]bool, n)
+	}
+	r.data, r.bufs, r.done = r.data[:n], r.bufs[:n], r.done[:n]
+	r.level, r.plan = level, plan
+	return r
+}
+
+// render 按下标对应的格式化器格式化日志, 每个格式化器只执行一次
+func (r *rendered) render(slot int, entry *Entry) {
+	if r.done[slot] {
+		return
+	}
+	r.done[slot] = true
+	data, bp, err := formatEntry(r.plan.formatters[slot], entry)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "format error: %v\n", err)
+		return
+	}
+	r.data[slot], r.bufs[slot] = data, bp
+}
+
+// release 归还缓冲区并将 rendered 放回池中
+func (r *rendered) release() {
+	for i := range r.data {
+		if r.bufs[i] != nil {
+			putBuffer(r.bufs[i], r.data[i])
+		}
+		r.data[i], r.bufs[i], r.done[i] = nil, nil, false
+	}
+	r.plan = nil
+	renderedPool.Put(r)
+}
+
+// formatEntry 格式化日志条目, 支持追加式格式化时使用池化缓冲区
+//
+// 参数:
+//   - f: 格式化器
+//   - entry: 日志条目
+//
+// 返回:
+//   - []byte: 格式化后的日志
+//   - *[]byte: 数据所属的池化缓冲区, 非追加式格式化器时为 nil
+//   - error: 格式化失败时返回, 此时缓冲区已归还
+func formatEntry(f Formatter, entry *Entry) ([]byte, *[]byte, error) {
+	af, ok := f.(AppendFormatter)
+	if !ok {
+		data, err := f.Format(entry)
+		return data, nil, err
+	}
+	bp := getBuffer()
+	data, err := af.AppendFormat((*bp)[:0], entry)
+	if err != nil {
+		putBuffer(bp, data)
+		return nil, nil, err
+	}
+	return data, bp, nil
+}
+
+// newRenderPlan 根据配置创建输出计划, 不需要多格式化器时返回 nil
+//
+// 参数:
+//   - cfg: 已应用默认值的配置
+//
+// 返回:
+//   - *renderPlan: 输出计划
+func newRenderPlan(cfg *Config) *renderPlan {
+	if len(cfg.Outputs) == 0 && !slices.ContainsFunc(cfg.routes(), func(r Route) bool { return r.Formatter != nil }) &&
+		(cfg.FanOut == nil || cfg.FanOut.Formatter == nil) {
+		return nil
+	}
+	p := &renderPlan{
+		formatters: []Formatter{cfg.Formatter},
+		main:       cfg.OutputConsole || cfg.OutputFile || cfg.Net != nil,
+	}
+	for _, o := range cfg.Outputs {
+		w, ok := o.Writer.(io.WriteCloser)
+		switch {
+		case o.Writer == nil:
+			w = cfg.newFileWriter(o.Path)
+		case o.Writer == os.Stdout || o.Writer == os.Stderr || !ok:
+			w = &ConsoleWriter{w: o.Writer}
+		}
+		p.outputs = append(p.outputs, output{writer: w, level: o.Level, slot: p.slotOf(o.Formatter)})
+	}
+	return p
+}
+
+// renderOutputs 按日志级别格式化输出计划中需要的数据
+//
+// 参数:
+//   - entry: 日志条目
+//   - hooks: 日志记录器的 hooks
+//
+// 返回:
+//   - *rendered: 格式化结果, 使用完毕后调用 release
+func (p *renderPlan) renderOutputs(entry *Entry, hooks []hook) *rendered {
+	r := newRendered(p, entry.Level)
+	if p.main {
+		r.render(0, entry)
+	}
+	for _, o := range p.outputs {
+		if o.level.Enabled(entry.Level) {
+			r.render(o.slot, entry)
+		}
+	}
+	for _, h := range hooks {
+		if rh, ok := h.(renderHook); ok {
+			if slot, ok := rh.renderSlot(entry); ok {
+				r.render(slot, entry)
+			}
+		}
+	}
+	return r
+}
+
+// write 将格式化结果写入各输出, 调用方持有日志记录器的锁
+//
+// 参数:
+//   - main: 主写入器
+//   - r: 格式化结果
+func (p *renderPlan) write(main io.Writer, r *rendered) {
+	if p.main && r.data[0] != nil {
+		if _, err := writeLevel(main, r.level, r.data[0]); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "write error: %v\n", err)
+		}
+	}
+	for _, o := range p.outputs {
+		if !o.level.Enabled(r.level) || r.data[o.slot] == nil {
+			continue
+		}
+		if _, err := writeLevel(o.writer, r.level, r.data[o.slot]); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "write error: %v\n", err)
+		}
+	}
+}
+
+// hookData 返回传给 hook 的格式化数据
+func (r *rendered) hookData(h hook, entry *Entry) []byte {
+	if rh, ok := h.(renderHook); ok {
+		if slot, ok := rh.renderSlot(entry); ok {
+			return r.data[slot]
+		}
+	}
+	return r.data[0]
+}
+
+// sync 同步所有输出
+func (p *renderPlan) sync() error {
+	var errs []error
+	for _, o := range p.outputs {
+		if syncer, ok := o.writer.(interface{ Sync() error }); ok {
+			if err := syncer.Sync(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// close 关闭所有输出
+func (p *renderPlan) close() error {
+	var errs []error
+	for _, o := range p.outputs {
+		if err := o.writer.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,174 @@
+package fastlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// countingFormatter 统计 Format 调用次数的格式化器
+type countingFormatter struct {
+	calls atomic.Int32
+	inner Formatter
+}
+
+func (f *countingFormatter) Format(entry *Entry) ([]byte, error) {
+	f.calls.Add(1)
+	return f.inner.Format(entry)
+}
+
+// tagFormatter 包含切片、不可比较的格式化器
+type tagFormatter struct {
+	tags []string
+}
+
+func (f tagFormatter) Format(entry *Entry) ([]byte, error) {
+	return []byte(strings.Join(f.tags, ",") + " " + entry.Message + "\n"), nil
+}
+
+func TestOutputsPerOutputFormatterAndLevel(t *testing.T) {
+	console, file := &bytes.Buffer{}, &bytes.Buffer{}
+	l := New(&Config{Outputs: []Output{
+		{Writer: console, Formatter: MustPattern("%level %msg"), Level: INFO},
+		{Writer: file, Formatter: JSON{}, Level: DEBUG},
+	}})
+	if l.Level() != DEBUG {
+		t.Errorf("default level = %v, want lowest output level DEBUG", l.Level())
+	}
+
+	l.Debug("connecting")
+	l.Infow("ready", Int("port", 80))
+	_ = l.Close()
+
+	if got := console.String(); got != "INFO ready\n" {
+		t.Errorf("console output = %q", got)
+	}
+	lines := strings.Split(strings.TrimSpace(file.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("file lines = %d, want 2:\n%s", len(lines), file.String())
+	}
+	var rec map[string]any
+	if err := json.Unmarshal([]byte(lines[1]), &rec); err != nil || rec["message"] != "ready" || rec["port"] != float64(80) {
+		t.Errorf("file record = %v, %v", rec, err)
+	}
+}
+
+func TestOutputsFormatOncePerFormatter(t *testing.T) {
+	shared := &countingFormatter{inner: MustPattern("%msg")}
+	main := &bytes.Buffer{}
+	a, b, c := &bytes.Buffer{}, &bytes.Buffer{}, &bytes.Buffer{}
+	l := New(&Config{
+		OutputConsole: true,
+		Formatter:     shared,
+		Outputs: []Output{
+			{Writer: a},
+			{Writer: b, Formatter: shared},
+			{Writer: c, Formatter: tagFormatter{tags: []string{"x", "y"}}},
+			{Writer: &bytes.Buffer{}, Formatter: tagFormatter{tags: []string{"z"}}},
+		},
+	})
+	l.writer = &mockWriteCloser{Buffer: main}
+
+	l.Info("one")
+	l.Info("two")
+	_ = l.Close()
+
+	if n := shared.calls.Load(); n != 2 {
+		t.Errorf("shared formatter calls = %d, want 2", n)
+	}
+	for name, buf := range map[string]*bytes.Buffer{"main": main, "a": a, "b": b} {
+		if buf.String() != "one\ntwo\n" {
+			t.Errorf("%s output = %q", name, buf.String())
+		}
+	}
+	if c.String() != "x,y one\nx,y two\n" {
+		t.Errorf("non-comparable formatter output = %q", c.String())
+	}
+}
+
+func TestOutputsAsync(t *testing.T) {
+	info, all := &bytes.Buffer{}, &bytes.Buffer{}
+	l := New(&Config{
+		AsyncLog: &AsyncConfig{},
+		Outputs: []Output{
+			{Writer: info, Formatter: MustPattern("%level %msg"), Level: INFO},
+			{Writer: all, Formatter: MustPattern("%msg"), Level: DEBUG},
+		},
+	})
+	for _, msg := range []string{"a", "b", "c"} {
+		l.Debug(msg)
+		l.Warn(msg)
+	}
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if got := info.String(); got != "WARN a\nWARN b\nWARN c\n" {
+		t.Errorf("info output = %q", got)
+	}
+	if got := all.String(); got != "a\na\nb\nb\nc\nc\n" {
+		t.Errorf("all output = %q", got)
+	}
+}
+
+func TestLevelRouterFormatter(t *testing.T) {
+	dir := t.TempDir()
+	cfg := NewConfig(filepath.Join(dir, "app.log"))
+	cfg.OutputConsole = false
+	cfg.BufferEnabled = false
+	cfg.Formatter = MustPattern("%level %msg")
+	cfg.LevelRouter = true
+	cfg.LevelRouterFormatter = JSON{}
+
+	l := New(cfg)
+	l.Warnw("disk low", Int("free", 5))
+	_ = l.Close()
+
+	if got := readLogFile(t, filepath.Join(dir, "app.log")); got != "WARN disk low\n" {
+		t.Errorf("app.log = %q", got)
+	}
+	var rec map[string]any
+	if err := json.Unmarshal([]byte(readLogFile(t, filepath.Join(dir, "WARN.log"))), &rec); err != nil || rec["free"] != float64(5) {
+		t.Errorf("WARN.log record = %v, %v", rec, err)
+	}
+}
+
+func TestConfigOutputsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		outputs []Output
+		wantErr bool
+	}{
+		{"writer", []Output{{Writer: &bytes.Buffer{}}}, false},
+		{"path", []Output{{Path: "app.json"}}, false},
+		{"neither", []Output{{Formatter: JSON{}}}, true},
+		{"both", []Output{{Writer: &bytes.Buffer{}, Path: "app.json"}}, true},
+		{"bad level", []Output{{Writer: &bytes.Buffer{}, Level: PANIC + 1}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&Config{Outputs: tt.outputs}).Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	cfg := &Config{OutputFile: true, LogPath: "app.log", Outputs: []Output{{Path: "app.log"}}}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() should reject an output path equal to LogPath")
+	}
+
+	cfg = &Config{Outputs: []Output{{Writer: &bytes.Buffer{}, Level: WARN}, {Writer: &bytes.Buffer{}}}}
+	clone := cfg.Clone()
+	clone.Outputs[0].Level = ERROR
+	if cfg.Outputs[0].Level != WARN {
+		t.Error("Clone() should copy Outputs")
+	}
+	if lvl := defaultOutputsLevel(cfg.Outputs); lvl != INFO {
+		t.Errorf("defaultOutputsLevel() = %v, want INFO when an output has no level", lvl)
+	}
+}
@@ -0,0 +1,506 @@
+package fastlog
+
+import (
+	"fmt"
+	"strconv"
+	"unicode/utf8"
+)
+
+// patternVerb 模板占位符类型
+type patternVerb uint8
+
+const (
+	patternLiteral patternVerb = iota // 字面文本
+	patternTime                       // %time{layout}
+	patternLevel                      // %level
+	patternLogger                     // %logger
+	patternCaller                     // %caller
+	patternMessage                    // %msg / %message
+	patternFields                     // %fields
+	patternField                      // %field{key}
+	patternSection                    // %[ ... %] 条件区段
+)
+
+// patternVerbs 占位符名称到类型的映射
+var patternVerbs = map[string]patternVerb{
+	"time":    patternTime,
+	"level":   patternLevel,
+	"logger":  patternLogger,
+	"caller":  patternCaller,
+	"msg":     patternMessage,
+	"message": patternMessage,
+	"fields":  patternFields,
+	"field":   patternField,
+}
+
+// patternSegment 编译后的模板片段
+type patternSegment struct {
+	verb     patternVerb      // 片段类型
+	text     string           // 字面文本、时间格式或字段键名
+	width    int              // 最小宽度, 正数右对齐, 负数左对齐, 0 表示不填充
+	maxWidth int              // 最大字符数, 超出截断, 0 表示不截断
+	children []patternSegment // 条件区段内的片段
+}
+
+// Pattern 模板格式
+//
+// 通过模板字符串描述一行日志的布局, 模板在构造时编译一次, 格式化时按片段顺序直接追加。
+// 每行末尾自动追加换行符。
+//
+// 支持的占位符:
+//   - %time 或 %time{layout}: 时间, 默认使用 Config.TimeFormat
+//   - %level 或 %level{宽度}: 级别, 如 %level{-5} 表示左对齐 5 个字符
+//   - %logger: 日志记录器名称
+//   - %caller: 调用者信息
+//   - %msg 或 %message: 日志消息
+//   - %fields: 全部字段, 以 key=value 形式空格分隔
+//   - %field{key}: 单个字段的值, 命名空间内的字段使用点号键名, 如 %field{user.name}
+//   - %n: 换行符
+//   - %%: 百分号
+//
+// 宽度修饰符写在 % 与占位符名称之间, 格式为 [-]最小宽度[.最大宽度]:
+// %-8logger 左对齐填充到 8 个字符, %10caller 右对齐, %.20msg 截断为最多 20 个字符。
+//
+// 条件区段 %[ ... %] 仅在区段内至少一个占位符输出非空内容时才输出,
+// 用于省略调用者、字段等可能为空的部分及其分隔符。区段可以嵌套。
+//
+// 示例:
+//
+//	p := fastlog.MustPattern("%time{15:04:05.000} [%level{-5}] %logger%[ %caller -%] %msg%[ %fields%]")
+//	cfg.Formatter = p
+//	// 输出: 10:30:45.123 [INFO ] app.db main.go:main:15 - 连接成功 host=db1
+type Pattern struct {
+	// Sanitize 消息和字段的净化模式, 零值不做处理
+	Sanitize SanitizeMode
+
+	tmpl     string           // 原始模板
+	segments []patternSegment // 编译后的片段
+}
+
+// NewPattern 编译模板并创建模板格式化器
+//
+// 参数:
+//   - tmpl: 模板字符串
+//
+// 返回:
+//   - *Pattern: 模板格式化器
+//   - error: 模板语法错误时返回, 包含出错位置
+func NewPattern(tmpl string) (*Pattern, error) {
+	segs, _, err := compilePattern(tmpl, 0, false)
+	if err != nil {
+		return nil, err
+	}
+	return &Pattern{tmpl: tmpl, segments: segs}, nil
+}
+
+// MustPattern 编译模板并创建模板格式化器, 模板非法时 panic
+//
+// 适用于模板为常量的场景。
+//
+// 参数:
+//   - tmpl: 模板字符串
+//
+// 返回:
+//   - *Pattern: 模板格式化器
+func MustPattern(tmpl string) *Pattern {
+	p, err := NewPattern(tmpl)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// String 返回原始模板
+//
+// 返回:
+//   - string: 模板字符串
+func (p *Pattern) String() string {
+	return p.tmpl
+}
+
+// Format 实现模板格式
+//
+// 参数:
+//   - entry: 日志条目
+//
+// 返回:
+//   - []byte: 格式化后的字节数组
+//   - error: 如果格式化失败
+func (p *Pattern) Format(entry *Entry) ([]byte, error) {
+	return p.AppendFormat(make([]byte, 0, 256), entry)
+}
+
+// AppendFormat 实现 AppendFormatter 接口, 将格式化结果追加到 dst
+//
+// 参数:
+//   - dst: 目标缓冲区
+//   - entry: 日志条目
+//
+// 返回:
+//   - []byte: 追加后的字节数组
+//   - error: 如果格式化失败
+func (p *Pattern) AppendFormat(dst []byte, entry *Entry) ([]byte, error) {
+	for i := range p.segments {
+		dst, _ = p.appendSegment(dst, &p.segments[i], entry)
+	}
+	return append(dst, '\n'), nil
+}
+
+// appendSegment 追加单个片段
+//
+// 参数:
+//   - dst: 目标缓冲区
+//   - seg: 片段
+//   - entry: 日志条目
+//
+// 返回:
+//   - []byte: 追加后的缓冲区
+//   - bool: 占位符是否输出了非空内容 (字面文本始终返回 false)
+func (p *Pattern) appendSegment(dst []byte, seg *patternSegment, entry *Entry) ([]byte, bool) {
+	switch seg.verb {
+	case patternLiteral:
+		return append(dst, seg.text...), false
+
+	case patternSection:
+		start := len(dst)
+		nonEmpty := false
+		for i := range seg.children {
+			var ok bool
+			dst, ok = p.appendSegment(dst, &seg.children[i], entry)
+			nonEmpty = nonEmpty || ok
+		}
+		if !nonEmpty {
+			dst = dst[:start]
+		}
+		return dst, nonEmpty
+	}
+
+	start := len(dst)
+	switch seg.verb {
+	case patternTime:
+		layout := seg.text
+		if layout == "" {
+			layout = entry.TimeFormat
+		}
+		dst = entry.Time.AppendFormat(dst, layout)
+
+	case patternLevel:
+		dst = append(dst, entry.Level.String()...)
+
+	case patternLogger:
+		dst = append(dst, entry.Logger...)
+
+	case patternCaller:
+		dst = append(dst, entry.Caller...)
+
+	case patternMessage:
+		dst = p.appendText(dst, entry.Message)
+
+	case patternFields:
+		dst = p.appendFields(dst, entry)
+
+	case patternField:
+		if f, ok := lookupField(entry.Fields, seg.text); ok {
+			dst = p.appendText(dst, f.valueWithTimeFormat(entry.TimeFormat))
+		}
+	}
+
+	nonEmpty := len(dst) > start
+	return padSegment(dst, start, seg.width, seg.maxWidth), nonEmpty
+}
+
+// appendText 按净化模式追加文本
+func (p *Pattern) appendText(dst []byte, s string) []byte {
+	if p.Sanitize == SanitizeOff || !needsSanitize(s) {
+		return append(dst, s...)
+	}
+	return appendSanitized(dst, s, p.Sanitize)
+}
+
+// appendFields 以 key=value 形式追加全部字段, 空格分隔, 命名空间以点号拼接
+func (p *Pattern) appendFields(dst []byte, entry *Entry) []byte {
+	var ns string
+	n := 0
+	for _, field := range entry.Fields {
+		if field.typ == NamespaceType {
+			ns = joinNamespace(ns, field.key)
+			continue
+		}
+		if n > 0 {
+			dst = append(dst, ' ')
+		}
+		if ns != "" {
+			dst = append(dst, ns...)
+			dst = append(dst, '.')
+		}
+		dst = append(dst, field.key...)
+		dst = append(dst, '=')
+		dst = p.appendText(dst, field.valueWithTimeFormat(entry.TimeFormat))
+		n++
+	}
+	return dst
+}
+
+// lookupField 按键名查找字段, 命名空间内的字段以点号拼接的完整键名匹配
+//
+// 参数:
+//   - fields: 字段列表
+//   - key: 完整键名
+//
+// 返回:
+//   - Field: 找到的第一个字段
+//   - bool: 是否找到
+func lookupField(fields []Field, key string) (Field, bool) {
+	var ns string
+	for _, f := range fields {
+		if f.typ == NamespaceType {
+			ns = joinNamespace(ns, f.key)
+			continue
+		}
+		if ns == "" {
+			if f.key == key {
+				return f, true
+			}
+			continue
+		}
+		if len(key) == len(ns)+1+len(f.key) && key[:len(ns)] == ns && key[len(ns)] == '.' && key[len(ns)+1:] == f.key {
+			return f, true
+		}
+	}
+	return Field{}, false
+}
+
+// padSegment 对 dst[start:] 按宽度修饰符截断和填充
+//
+// 参数:
+//   - dst: 缓冲区
+//   - start: 片段起始位置
+//   - width: 最小宽度, 正数右对齐, 负数左对齐
+//   - maxWidth: 最大字符数, 0 表示不截断
+//
+// 返回:
+//   - []byte: 处理后的缓冲区
+func padSegment(dst []byte, start, width, maxWidth int) []byte {
+	if width == 0 && maxWidth == 0 {
+		return dst
+	}
+	n := utf8.RuneCount(dst[start:])
+
+	// 截断: 保留前 maxWidth 个字符
+	if maxWidth > 0 && n > maxWidth {
+		cut := start
+		for i := 0; i < maxWidth; i++ {
+			_, size := utf8.DecodeRune(dst[cut:])
+			cut += size
+		}
+		dst = dst[:cut]
+		n = maxWidth
+	}
+
+	pad := width
+	if pad < 0 {
+		pad = -pad
+	}
+	pad -= n
+	if pad <= 0 {
+		return dst
+	}
+
+	end := len(dst)
+	for i := 0; i < pad; i++ {
+		dst = append(dst, ' ')
+	}
+	if width > 0 {
+		// 右对齐: 内容后移, 空格填在前面
+		copy(dst[start+pad:], dst[start:end])
+		for i := start; i < start+pad; i++ {
+			dst[i] = ' '
+		}
+	}
+	return dst
+}
+
+// compilePattern 编译模板片段
+//
+// 参数:
+//   - tmpl: 模板字符串
+//   - pos: 起始位置
+//   - inSection: 是否位于条件区段内, 为 true 时遇到 %] 返回
+//
+// 返回:
+//   - []patternSegment: 编译后的片段
+//   - int: 结束位置 (区段内为 %] 之后)
+//   - error: 语法错误
+func compilePattern(tmpl string, pos int, inSection bool) ([]patternSegment, int, error) {
+	var segs []patternSegment
+	literalStart := pos
+
+	flush := func(end int) {
+		if end > literalStart {
+			segs = appendLiteral(segs, tmpl[literalStart:end])
+		}
+	}
+
+	for pos < len(tmpl) {
+		if tmpl[pos] != '%' {
+			pos++
+			continue
+		}
+		flush(pos)
+		verbStart := pos
+		pos++
+		if pos >= len(tmpl) {
+			return nil, 0, fmt.Errorf("pattern: dangling %% at offset %d", verbStart)
+		}
+
+		switch tmpl[pos] {
+		case '%':
+			segs = appendLiteral(segs, "%")
+			pos++
+			literalStart = pos
+			continue
+		case 'n':
+			if pos+1 >= len(tmpl) || !isPatternLetter(tmpl[pos+1]) {
+				segs = appendLiteral(segs, "\n")
+				pos++
+				literalStart = pos
+				continue
+			}
+		case '[':
+			children, next, err := compilePattern(tmpl, pos+1, true)
+			if err != nil {
+				return nil, 0, err
+			}
+			segs = append(segs, patternSegment{verb: patternSection, children: children})
+			pos = next
+			literalStart = pos
+			continue
+		case ']':
+			if !inSection {
+				return nil, 0, fmt.Errorf("pattern: unexpected %%] at offset %d", verbStart)
+			}
+			return segs, pos + 1, nil
+		}
+
+		seg, next, err := compileVerb(tmpl, pos, verbStart)
+		if err != nil {
+			return nil, 0, err
+		}
+		segs = append(segs, seg)
+		pos = next
+		literalStart = pos
+	}
+
+	if inSection {
+		return nil, 0, fmt.Errorf("pattern: unclosed %%[ section")
+	}
+	flush(pos)
+	return segs, pos, nil
+}
+
+// compileVerb 编译单个占位符: [-]最小宽度[.最大宽度]名称[{参数}]
+//
+// 参数:
+//   - tmpl: 模板字符串
+//   - pos: 修饰符或名称的起始位置 (% 之后)
+//   - verbStart: % 的位置, 用于错误信息
+//
+// 返回:
+//   - patternSegment: 编译后的片段
+//   - int: 占位符之后的位置
+//   - error: 语法错误
+func compileVerb(tmpl string, pos, verbStart int) (patternSegment, int, error) {
+	var seg patternSegment
+
+	// 宽度修饰符
+	left := false
+	if tmpl[pos] == '-' {
+		left = true
+		pos++
+	}
+	width, pos := scanPatternInt(tmpl, pos)
+	if left {
+		width = -width
+	}
+	seg.width = width
+	if pos < len(tmpl) && tmpl[pos] == '.' {
+		seg.maxWidth, pos = scanPatternInt(tmpl, pos+1)
+		if seg.maxWidth == 0 {
+			return seg, 0, fmt.Errorf("pattern: invalid max width at offset %d", verbStart)
+		}
+	}
+
+	// 名称
+	nameStart := pos
+	for pos < len(tmpl) && isPatternLetter(tmpl[pos]) {
+		pos++
+	}
+	name := tmpl[nameStart:pos]
+	verb, ok := patternVerbs[name]
+	if !ok {
+		return seg, 0, fmt.Errorf("pattern: unknown verb %q at offset %d", "%"+tmpl[verbStart+1:pos], verbStart)
+	}
+	seg.verb = verb
+
+	// 参数
+	var arg string
+	hasArg := false
+	if pos < len(tmpl) && tmpl[pos] == '{' {
+		end := pos + 1
+		for end < len(tmpl) && tmpl[end] != '}' {
+			end++
+		}
+		if end >= len(tmpl) {
+			return seg, 0, fmt.Errorf("pattern: unclosed { at offset %d", pos)
+		}
+		arg, hasArg = tmpl[pos+1:end], true
+		pos = end + 1
+	}
+
+	switch verb {
+	case patternTime:
+		seg.text = arg
+	case patternLevel:
+		if hasArg {
+			w, err := strconv.Atoi(arg)
+			if err != nil {
+				return seg, 0, fmt.Errorf("pattern: invalid level width %q at offset %d", arg, verbStart)
+			}
+			seg.width = w
+		}
+	case patternField:
+		if arg == "" {
+			return seg, 0, fmt.Errorf("pattern: %%field requires a key at offset %d", verbStart)
+		}
+		seg.text = arg
+	default:
+		if hasArg {
+			return seg, 0, fmt.Errorf("pattern: %%%s does not take an argument at offset %d", name, verbStart)
+		}
+	}
+	return seg, pos, nil
+}
+
+// appendLiteral 追加字面文本片段, 与前一个字面片段合并
+func appendLiteral(segs []patternSegment, text string) []patternSegment {
+	if n := len(segs); n > 0 && segs[n-1].verb == patternLiteral {
+		segs[n-1].text += text
+		return segs
+	}
+	return append(segs, patternSegment{verb: patternLiteral, text: text})
+}
+
+// scanPatternInt 读取非负十进制整数
+func scanPatternInt(s string, pos int) (int, int) {
+	n := 0
+	for pos < len(s) && s[pos] >= '0' && s[pos] <= '9' {
+		n = n*10 + int(s[pos]-'0')
+		pos++
+	}
+	return n, pos
+}
+
+// isPatternLetter 判断是否为占位符名称字符
+func isPatternLetter(c byte) bool {
+	return c >= 'a' && c <= 'z'
+}
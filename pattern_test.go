@@ -0,0 +1,132 @@
+package fastlog
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPatternFormat(t *testing.T) {
+	entry := makeEntry("hello", "main.go:main:10", String("user", "alice"), Int("n", 3))
+	entry.Logger = "app.db"
+
+	tests := []struct {
+		name string
+		tmpl string
+		want string
+	}{
+		{"default time", "%time %level %msg", "2026-01-15 10:30:45 INFO hello"},
+		{"time layout", "%time{15:04:05.000} %message", "10:30:45.000 hello"},
+		{"level width", "[%level{-5}] [%level{7}]", "[INFO ] [   INFO]"},
+		{"prefix width", "[%-8logger][%8logger]", "[app.db  ][  app.db]"},
+		{"truncate", "%.3logger|%-5.2msg|", "app|he   |"},
+		{"logger caller", "%logger %caller", "app.db main.go:main:10"},
+		{"fields", "%msg %fields", "hello user=alice n=3"},
+		{"field lookup", "%field{n}/%field{user}/%field{missing}", "3/alice/"},
+		{"escapes", "100%% %msg%n--", "100% hello\n--"},
+		{"section kept", "%msg%[ (%caller)%]", "hello (main.go:main:10)"},
+		{"literal only", "plain text", "plain text"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewPattern(tt.tmpl)
+			if err != nil {
+				t.Fatalf("NewPattern(%q) error = %v", tt.tmpl, err)
+			}
+			got, err := p.Format(entry)
+			if err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+			if string(got) != tt.want+"\n" {
+				t.Errorf("Format() = %q, want %q", got, tt.want+"\n")
+			}
+		})
+	}
+}
+
+func TestPatternSection(t *testing.T) {
+	p := MustPattern("%level%[ [%logger]%]%[ %caller -%] %msg%[ | %fields%]%[ <%[%field{a}%]%[,%field{b}%]>%]")
+
+	tests := []struct {
+		name  string
+		entry *Entry
+		want  string
+	}{
+		{"all empty", makeEntry("m", ""), "INFO m"},
+		{"caller only", makeEntry("m", "x.go:f:1"), "INFO x.go:f:1 - m"},
+		{"fields", makeEntry("m", "", String("b", "2")), "INFO m | b=2 <,2>"},
+		{"nested", makeEntry("m", "", String("a", "1"), String("b", "2")), "INFO m | a=1 b=2 <1,2>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _ := p.Format(tt.entry)
+			if string(got) != tt.want+"\n" {
+				t.Errorf("Format() = %q, want %q", got, tt.want+"\n")
+			}
+		})
+	}
+}
+
+func TestPatternNamespaceAndSanitize(t *testing.T) {
+	entry := makeEntry("a\nb", "", String("id", "1"), Namespace("user"), String("name", "bob\x1b[2J"))
+
+	got, _ := MustPattern("%field{user.name} %field{name} %fields").Format(entry)
+	if want := "bob\x1b[2J  id=1 user.name=bob\x1b[2J\n"; string(got) != want {
+		t.Errorf("Format() = %q, want %q", got, want)
+	}
+
+	p := MustPattern("%msg %fields")
+	p.Sanitize = SanitizeEscape | SanitizeStripANSI
+	got, _ = p.Format(entry)
+	if want := `a\nb id=1 user.name=bob` + "\n"; string(got) != want {
+		t.Errorf("sanitized Format() = %q, want %q", got, want)
+	}
+}
+
+func TestNewPatternErrors(t *testing.T) {
+	for _, tmpl := range []string{
+		"%",
+		"%unknown",
+		"%time{unclosed",
+		"%level{x}",
+		"%field",
+		"%field{}",
+		"%msg{arg}",
+		"%[ %msg",
+		"%msg %]",
+		"%.0msg",
+	} {
+		if _, err := NewPattern(tmpl); err == nil {
+			t.Errorf("NewPattern(%q) should fail", tmpl)
+		}
+	}
+
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(r.(error).Error(), "unknown verb") {
+			t.Errorf("MustPattern should panic with the compile error, got %v", r)
+		}
+	}()
+	MustPattern("%bogus")
+}
+
+func TestLoggerPattern(t *testing.T) {
+	m := newMock()
+	l := New(&Config{Level: INFO, OutputConsole: true, Formatter: MustPattern("[%level{-5}] %logger%[ %caller -%] %msg%[ %fields%]")})
+	l.writer = m
+
+	l.Named("api").Infow("started", Int("port", 8080))
+	if got := m.String(); got != "[INFO ] api started port=8080\n" {
+		t.Errorf("output = %q", got)
+	}
+}
+
+func TestPatternAppendFormatAllocs(t *testing.T) {
+	p := MustPattern("%time [%level{-5}] %logger%[ %caller%] %msg %field{user}")
+	entry := makeEntry("hello", "main.go:main:10", String("user", "alice"))
+	buf := make([]byte, 0, 512)
+	allocs := testing.AllocsPerRun(100, func() {
+		buf, _ = p.AppendFormat(buf[:0], entry)
+	})
+	if allocs != 0 {
+		t.Errorf("AppendFormat allocs = %v, want 0", allocs)
+	}
+}
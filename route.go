@@ -0,0 +1,278 @@
+package fastlog
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"gitee.com/MM-Q/comprx"
+)
+
+// Route 日志路由规则
+//
+// 命中规则的日志额外写入规则指定的文件, 不影响主输出。匹配条件全部满足时命中,
+// 零值的条件不参与匹配。规则按 Config.Routes 中的顺序匹配, 设置了 Stop 的规则命中后不再匹配后续规则。
+//
+// 示例:
+//
+//	cfg.Routes = []fastlog.Route{
+//		// 审计日志单独存放, 不再进入后续规则
+//		{Fields: map[string]string{"audit": "true"}, Path: "logs/audit.log", Formatter: fastlog.JSON{}, Stop: true},
+//		// 支付模块的 WARN 及以上
+//		{Logger: "payments", MinLevel: fastlog.WARN, Path: "logs/payments.log"},
+//	}
+type Route struct {
+	// MinLevel 最低级别 (含), 按 LevelSpec.Rank 比较, 零值表示不限制
+	MinLevel Level
+
+	// MaxLevel 最高级别 (含), 按 LevelSpec.Rank 比较, 零值表示不限制
+	MaxLevel Level
+
+	// Logger 日志记录器名称前缀, 按点号分段匹配: "app.db" 匹配 "app.db" 和 "app.db.pool", 不匹配 "app.dbx"
+	Logger string
+
+	// MessagePrefix 日志消息前缀
+	MessagePrefix string
+
+	// Fields 字段键 → 值, 所有字段的值 (按字符串形式比较) 都相等时命中
+	// 命名空间内的字段使用点号拼接的完整键名, 如 "req.method"
+	Fields map[string]string
+
+	// Path 日志文件路径
+	Path string
+
+	// Rotation 轮转配置, nil 时沿用 Config 的轮转配置
+	Rotation *Rotation
+
+	// Formatter 格式化器, nil 时使用 Config.Formatter
+	Formatter Formatter
+
+	// Stop 命中后不再匹配后续规则
+	Stop bool
+}
+
+// Rotation 路由文件的轮转配置, 各字段含义与 Config 中的同名字段相同
+type Rotation struct {
+	MaxSize       int                 // 单文件最大大小 (MB), 零值默认 10MB
+	MaxFiles      int                 // 保留的历史日志文件数, 零值表示不限制
+	MaxAge        int                 // 保留天数, 零值表示不限制
+	Compress      bool                // 是否压缩历史日志文件
+	CompressType  comprx.CompressType // 压缩类型
+	LocalTime     bool                // 是否使用本地时间命名轮转文件
+	DateDirLayout bool                // 是否按日期目录存放轮转文件
+	RotateByDay   bool                // 是否按天轮转
+}
+
+// LevelRoutes 返回与 LevelRouter 等价的内置路由规则
+//
+// 为 >= level 的每个级别 (含自定义级别和 Always 级别) 生成一条只匹配该级别的规则,
+// 文件名为级别的 FileName (默认 {LEVEL}.log)。
+//
+// 参数:
+//   - dir: 级别文件所在目录
+//   - level: 最低级别
+//   - f: 级别文件使用的格式化器, nil 时使用 Config.Formatter
+//
+// 返回:
+//   - []Route: 按级别升序排列的路由规则
+//
+// 示例:
+//
+//	// 等价于 LevelRouter, 并额外将 ERROR 及以上汇总到 errors.log
+//	cfg.Routes = append(fastlog.LevelRoutes("logs", fastlog.INFO, nil),
+//		fastlog.Route{MinLevel: fastlog.ERROR, Path: "logs/errors.log"})
+func LevelRoutes(dir string, level Level, f Formatter) []Route {
+	var routes []Route
+	for _, lvl := range AllLevels() {
+		if !level.Enabled(lvl) {
+			continue // 低于设置级别的级别不创建专属文件
+		}
+		routes = append(routes, Route{
+			MinLevel:  lvl,
+			MaxLevel:  lvl,
+			Path:      filepath.Join(dir, levelFileName(lvl)),
+			Formatter: f,
+		})
+	}
+	return routes
+}
+
+// validate 验证路由规则
+//
+// 参数:
+//   - i: 规则在路由列表中的下标, 用于错误信息
+//
+// 返回:
+//   - error: 验证通过时返回 nil
+func (r *Route) validate(i int) error {
+	if r.Path == "" {
+		return fmt.Errorf("route %d: path must be set", i)
+	}
+	if r.MinLevel != 0 && r.MaxLevel != 0 && levelRank(r.MinLevel) > levelRank(r.MaxLevel) {
+		return fmt.Errorf("route %d: min level %s is above max level %s", i, r.MinLevel, r.MaxLevel)
+	}
+	if r.Rotation != nil && (r.Rotation.MaxSize < 0 || r.Rotation.MaxFiles < 0 || r.Rotation.MaxAge < 0) {
+		return fmt.Errorf("route %d: rotation settings must be >= 0", i)
+	}
+	return nil
+}
+
+// inRange 返回级别是否在规则的级别范围内, 按位次比较
+func (r *Route) inRange(lvl Level) bool {
+	rank := levelRank(lvl)
+	return (r.MinLevel == 0 || rank >= levelRank(r.MinLevel)) && (r.MaxLevel == 0 || rank <= levelRank(r.MaxLevel))
+}
+
+// match 返回日志条目是否满足规则的匹配条件
+func (r *Route) match(entry *Entry) bool {
+	if !r.inRange(entry.Level) {
+		return false
+	}
+	if r.Logger != "" && !matchNamePrefix(entry.Logger, r.Logger) {
+		return false
+	}
+	if !strings.HasPrefix(entry.Message, r.MessagePrefix) {
+		return false
+	}
+	for key, want := range r.Fields {
+		f, ok := lookupField(entry.Fields, key)
+		if !ok || f.Value() != want {
+			return false
+		}
+	}
+	return true
+}
+
+// matchNamePrefix 返回名称是否以前缀开头且在点号处分段
+func matchNamePrefix(name, prefix string) bool {
+	return strings.HasPrefix(name, prefix) && (len(name) == len(prefix) || name[len(prefix)] == '.')
+}
+
+// routes 返回生效的路由规则: 启用 LevelRouter 时内置级别规则在前, 随后为 Routes
+func (c *Config) routes() []Route {
+	if !c.LevelRouter {
+		return c.Routes
+	}
+	return append(LevelRoutes(filepath.Dir(c.LogPath), c.Level, c.LevelRouterFormatter), c.Routes...)
+}
+
+// newRouteWriter 按路由规则的轮转配置创建文件写入器
+//
+// 参数:
+//   - r: 路由规则
+//
+// 返回:
+//   - io.WriteCloser: 文件写入器, 缓冲配置沿用 Config
+func (c *Config) newRouteWriter(r *Route) io.WriteCloser {
+	if r.Rotation == nil {
+		return c.newFileWriter(r.Path)
+	}
+	rc := *c
+	rc.MaxSize, rc.MaxFiles, rc.MaxAge = r.Rotation.MaxSize, r.Rotation.MaxFiles, r.Rotation.MaxAge
+	rc.Compress, rc.CompressType = r.Rotation.Compress, r.Rotation.CompressType
+	rc.LocalTime, rc.DateDirLayout, rc.RotateByDay = r.Rotation.LocalTime, r.Rotation.DateDirLayout, r.Rotation.RotateByDay
+	return rc.newFileWriter(r.Path)
+}
+
+// routeHook 按路由规则写入专属文件的钩子（内部使用）
+type routeHook struct {
+	route  Route          // 路由规则
+	stops  []*Route       // 排在前面且设置了 Stop 的规则, 任一命中时本规则不再匹配
+	writer io.WriteCloser // 写入目标
+	slot   int            // 格式化器下标, 0 表示 Config.Formatter
+}
+
+// matches 返回日志条目是否命中本规则
+func (h *routeHook) matches(entry *Entry) bool {
+	if !h.route.match(entry) {
+		return false
+	}
+	for _, r := range h.stops {
+		if r.match(entry) {
+			return false
+		}
+	}
+	return true
+}
+
+// Fire 执行钩子
+// 日志命中规则时写入专属文件
+// 参数:
+//   - entry: 日志条目
+//   - data: 格式化后的日志数据
+//
+// 返回:
+//   - error: 写入过程中的错误
+func (h *routeHook) Fire(entry *Entry, data []byte) error {
+	if !h.matches(entry) {
+		return nil
+	}
+	_, err := writeLevel(h.writer, entry.Level, data)
+	return err
+}
+
+// renderSlot 返回该日志需要的格式化器下标
+// 参数:
+//   - entry: 日志条目
+//
+// 返回:
+//   - int: 格式化器下标
+//   - bool: 未命中规则时返回 false
+func (h *routeHook) renderSlot(entry *Entry) (int, bool) {
+	return h.slot, h.matches(entry)
+}
+
+// Levels 返回关心的级别
+// 返回:
+//   - []Level: 规则级别范围内的所有级别
+func (h *routeHook) Levels() []Level {
+	var levels []Level
+	for _, lvl := range AllLevels() {
+		if h.route.inRange(lvl) {
+			levels = append(levels, lvl)
+		}
+	}
+	return levels
+}
+
+// Sync 同步日志到存储
+// 如果写入器支持 Sync 方法则调用，否则返回 nil
+// 返回:
+//   - error: 同步过程中的错误
+func (h *routeHook) Sync() error {
+	if syncer, ok := h.writer.(interface{ Sync() error }); ok {
+		return syncer.Sync()
+	}
+	return nil
+}
+
+// Close 关闭写入器
+// 返回:
+//   - error: 关闭过程中的错误
+func (h *routeHook) Close() error {
+	return h.writer.Close()
+}
+
+// initRoutes 为每条路由规则创建专属文件 hook（内部方法）
+//
+// 参数:
+//   - routes: 生效的路由规则
+func (l *Logger) initRoutes(routes []Route) {
+	var stops []*Route
+	for i := range routes {
+		r := &routes[i]
+		hook := &routeHook{
+			route:  *r,
+			stops:  stops,
+			writer: l.config.newRouteWriter(r),
+		}
+		if l.render != nil {
+			hook.slot = l.render.slotOf(r.Formatter) // 规则文件使用的格式化器
+		}
+		l.hooks = append(l.hooks, hook)
+		if r.Stop {
+			stops = append(stops[:len(stops):len(stops)], r)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package fastlog
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gitee.com/MM-Q/logrotatex"
+)
+
+func TestRouteMatch(t *testing.T) {
+	entry := &Entry{
+		Level:   WARN,
+		Message: "charge failed",
+		Logger:  "payments.stripe",
+		Fields:  []Field{Bool("audit", true), Namespace("req"), Int("status", 502)},
+	}
+	tests := []struct {
+		name  string
+		route Route
+		want  bool
+	}{
+		{"empty matches all", Route{}, true},
+		{"level range", Route{MinLevel: INFO, MaxLevel: WARN}, true},
+		{"below min", Route{MinLevel: ERROR}, false},
+		{"above max", Route{MaxLevel: INFO}, false},
+		{"logger prefix", Route{Logger: "payments"}, true},
+		{"logger segment", Route{Logger: "pay"}, false},
+		{"message prefix", Route{MessagePrefix: "charge"}, true},
+		{"message mismatch", Route{MessagePrefix: "refund"}, false},
+		{"field value", Route{Fields: map[string]string{"audit": "true", "req.status": "502"}}, true},
+		{"field mismatch", Route{Fields: map[string]string{"audit": "false"}}, false},
+		{"missing field", Route{Fields: map[string]string{"component": "payments"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.route.match(entry); got != tt.want {
+				t.Errorf("match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoutesStopAndFormatter(t *testing.T) {
+	dir := t.TempDir()
+	path := func(name string) string { return filepath.Join(dir, name) }
+	l := New(&Config{
+		Formatter:     MustPattern("%level %msg"),
+		BufferEnabled: false,
+		Routes: []Route{
+			{Fields: map[string]string{"audit": "true"}, Path: path("audit.log"), Formatter: JSON{}, Stop: true},
+			{Logger: "payments", MinLevel: WARN, Path: path("payments.log")},
+			{MinLevel: ERROR, Path: path("errors.log")},
+		},
+	})
+
+	l.Infow("login", String("user", "alice"), Bool("audit", true))
+	l.Errorw("permission denied", Bool("audit", true))
+	pay := l.Named("payments")
+	pay.Info("charge ok")
+	pay.Error("charge failed")
+	l.Error("disk full")
+	_ = l.Close()
+
+	lines := strings.Split(strings.TrimSpace(readLogFile(t, path("audit.log"))), "\n")
+	var rec map[string]any
+	if len(lines) != 2 || json.Unmarshal([]byte(lines[0]), &rec) != nil || rec["user"] != "alice" {
+		t.Errorf("audit.log = %q", lines)
+	}
+	if got := readLogFile(t, path("payments.log")); got != "ERROR charge failed\n" {
+		t.Errorf("payments.log = %q", got)
+	}
+	if got := readLogFile(t, path("errors.log")); got != "ERROR charge failed\nERROR disk full\n" {
+		t.Errorf("errors.log = %q, audit entries should stop before this route", got)
+	}
+}
+
+func TestLevelRoutes(t *testing.T) {
+	withTestLevels(t)
+	routes := LevelRoutes("logs", ERROR, JSON{})
+	var paths []string
+	for _, r := range routes {
+		if r.MinLevel != r.MaxLevel || r.Formatter != (JSON{}) {
+			t.Errorf("route %+v should match a single level with the given formatter", r)
+		}
+		paths = append(paths, r.Path)
+	}
+	// 包含高于 PANIC 的 ALERT 和 Always 级别 AUDIT, 不含低于 DEBUG 的 TRACE
+	want := filepath.Join("logs", "ERROR.log") + " " + filepath.Join("logs", "FATAL.log") + " " +
+		filepath.Join("logs", "PANIC.log") + " " + filepath.Join("logs", "ALERT.log") + " " + filepath.Join("logs", "audit.log")
+	if got := strings.Join(paths, " "); got != want {
+		t.Errorf("LevelRoutes() paths = %s, want %s", got, want)
+	}
+
+	cfg := &Config{OutputFile: true, LogPath: "logs/app.log", LevelRouter: true, Level: WARN}
+	if got := len(cfg.routes()); got != len(LevelRoutes("logs", WARN, nil)) {
+		t.Errorf("LevelRouter routes = %d", got)
+	}
+}
+
+func TestConfigRoutesValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{"routes only", Config{Routes: []Route{{Path: "a.log"}}}, false},
+		{"missing path", Config{Routes: []Route{{MinLevel: INFO}}}, true},
+		{"min above max", Config{Routes: []Route{{Path: "a.log", MinLevel: ERROR, MaxLevel: INFO}}}, true},
+		{"bad rotation", Config{Routes: []Route{{Path: "a.log", Rotation: &Rotation{MaxSize: -1}}}}, true},
+		{"duplicate path", Config{Routes: []Route{{Path: "a.log"}, {Path: "./a.log"}}}, true},
+		{"log path", Config{OutputFile: true, LogPath: "app.log", Routes: []Route{{Path: "app.log"}}}, true},
+		{"level file", Config{OutputFile: true, LogPath: "logs/app.log", LevelRouter: true, Routes: []Route{{Path: "logs/ERROR.log"}}}, true},
+		{"output path", Config{Outputs: []Output{{Path: "a.log"}}, Routes: []Route{{Path: "a.log"}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	cfg := &Config{MaxSize: 100, Compress: true}
+	w, ok := cfg.newRouteWriter(&Route{Path: "a.log", Rotation: &Rotation{MaxSize: 5}}).(*logrotatex.LogRotateX)
+	if !ok || w.MaxSize != 5 || w.Compress {
+		t.Errorf("newRouteWriter() should use the route rotation, got %+v", w)
+	}
+}
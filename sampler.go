@@ -6,7 +6,6 @@ import (
 )
 
 const (
-	samplerLevels            = 6                // 日志级别数: DEBUG ~ PANIC
 	samplerBuckets           = 4096             // 每个级别下的桶数
 	offset32                 = 2166136261       // FNV-1a 哈希函数偏移值
 	prime32                  = 16777619         // FNV-1a 哈希函数质数
@@ -25,11 +24,12 @@ type samplerCounter struct {
 //
 // 使用固定桶 + atomic 计数器实现, 无锁设计。
 // 相同 level 和 message 的日志会被哈希到同一个桶, 在时间窗口内按规则放行或抑制。
+// 每个级别的桶在该级别首次出现时分配, 自定义级别同样参与采样, Always 级别不采样。
 type Sampler struct {
-	tick       time.Duration                                 // 时间窗口
-	initial    int                                           // 窗口内前 N 条放行
-	thereafter int                                           // 之后每 M 条放行 1 条
-	counters   [samplerLevels][samplerBuckets]samplerCounter // 每个级别下的每个桶的计数器
+	tick       time.Duration                                             // 时间窗口
+	initial    int                                                       // 窗口内前 N 条放行
+	thereafter int                                                       // 之后每 M 条放行 1 条
+	counters   [maxLevels]atomic.Pointer[[samplerBuckets]samplerCounter] // 按级别槽位存放的桶
 }
 
 // NewSampler 创建日志采样器
@@ -82,15 +82,15 @@ func DefaultSampler() *Sampler {
 // 返回:
 //   - bool: true 放行, false 抑制
 func (s *Sampler) Allow(level Level, msg string) bool {
-	// level 转索引 (DEBUG=1 → 0, INFO=2 → 1, ...)
-	i := int(level) - 1
-	if i < 0 || i >= samplerLevels {
+	// level 转槽位, 未注册的级别和 Always 级别不采样
+	i := levelSlot(level) - 1
+	if i < 0 || alwaysEnabled(level) {
 		return true
 	}
 
 	// message 哈希到桶
 	j := fnv32a(msg) % samplerBuckets
-	c := &s.counters[i][j]
+	c := &s.buckets(i)[j]
 
 	now := time.Now()
 	tn := now.UnixNano()
@@ -118,6 +118,15 @@ func (s *Sampler) Allow(level Level, msg string) bool {
 	return false
 }
 
+// buckets 返回级别槽位对应的桶, 首次使用时分配
+func (s *Sampler) buckets(i int) *[samplerBuckets]samplerCounter {
+	if b := s.counters[i].Load(); b != nil {
+		return b
+	}
+	s.counters[i].CompareAndSwap(nil, new([samplerBuckets]samplerCounter))
+	return s.counters[i].Load()
+}
+
 // fnv32a FNV-1a 哈希函数, 无内存分配
 func fnv32a(s string) uint32 {
 	hash := uint32(offset32)
@@ -0,0 +1,186 @@
+package fastlog
+
+import (
+	"bytes"
+	"unicode/utf8"
+)
+
+// SanitizeMode 文本格式的净化模式, 可按位组合
+//
+// 用于 Def、Simple、Compact 格式, 防止用户输入中的换行或终端转义序列伪造日志行、污染终端。
+// 零值 SanitizeOff 表示不做任何处理, 输出与未设置时完全一致。
+//
+// 示例:
+//
+//	cfg.Formatter = fastlog.Def{Sanitize: fastlog.SanitizeEscape | fastlog.SanitizeStripANSI}
+//	logger.Infow("登录\n2025-01-15 10:30:45 | ERROR  | 伪造", fastlog.String("user", "\x1b[31malice"))
+//	// 输出: ... | INFO   | 登录\n2025-01-15 10:30:45 | ERROR  | 伪造 user=alice
+type SanitizeMode uint8
+
+const (
+	// SanitizeOff 不做净化 (默认)
+	SanitizeOff SanitizeMode = 0
+
+	// SanitizeEscape 将控制字符转义为可见文本, 如换行输出为 \n, ESC 输出为 \x1b
+	SanitizeEscape SanitizeMode = 1 << (iota - 1)
+
+	// SanitizeIndent 多行文本的后续行统一缩进到首行之下, 续行不会从行首开始
+	// 与 SanitizeEscape 同时设置时, 换行按缩进处理, 其余控制字符仍转义
+	SanitizeIndent
+
+	// SanitizeStripANSI 删除终端转义序列 (CSI、OSC 等), 未设置时由 SanitizeEscape 转义
+	SanitizeStripANSI
+
+	// SanitizeAll 启用全部净化: 缩进多行、删除终端转义序列、转义其余控制字符
+	SanitizeAll = SanitizeEscape | SanitizeIndent | SanitizeStripANSI
+)
+
+// sanitizeIndent 多行文本续行的缩进
+const sanitizeIndent = "    "
+
+// writeSanitized 按净化模式写入文本 (内部辅助函数)
+//
+// 参数:
+//   - buf: 输出缓冲区
+//   - s: 原始文本
+//   - mode: 净化模式
+func writeSanitized(buf *bytes.Buffer, s string, mode SanitizeMode) {
+	if mode == SanitizeOff || !needsSanitize(s) {
+		buf.WriteString(s)
+		return
+	}
+	buf.Write(appendSanitized(buf.AvailableBuffer(), s, mode))
+}
+
+// needsSanitize 判断文本是否包含控制字符 (含 C1 控制字符)
+//
+// 参数:
+//   - s: 原始文本
+//
+// 返回:
+//   - bool: 需要净化时返回 true
+func needsSanitize(s string) bool {
+	for i := 0; i < len(s); i++ {
+		// 0xC2 是 U+0080~U+00BF 的 UTF-8 首字节, 可能是 C1 控制字符, 交给慢路径精确判断
+		if c := s[i]; c < 0x20 || c == 0x7f || c == 0xc2 {
+			return true
+		}
+	}
+	return false
+}
+
+// appendSanitized 按净化模式追加文本
+//
+// 参数:
+//   - dst: 目标缓冲区
+//   - s: 原始文本
+//   - mode: 净化模式
+//
+// 返回:
+//   - []byte: 追加后的缓冲区
+func appendSanitized(dst []byte, s string, mode SanitizeMode) []byte {
+	for i := 0; i < len(s); {
+		c := s[i]
+
+		// 终端转义序列
+		if c == 0x1b && mode&SanitizeStripANSI != 0 {
+			i += ansiSequenceLen(s[i:])
+			continue
+		}
+
+		// 换行: \r\n、\n 和单独的 \r 都视为换行
+		if (c == '\n' || c == '\r') && mode&SanitizeIndent != 0 {
+			if c == '\r' && i+1 < len(s) && s[i+1] == '\n' {
+				i++
+			}
+			dst = append(dst, '\n')
+			dst = append(dst, sanitizeIndent...)
+			i++
+			continue
+		}
+
+		// ASCII 控制字符
+		if c < 0x20 || c == 0x7f {
+			if mode&SanitizeEscape != 0 {
+				dst = appendEscapedControl(dst, rune(c))
+			} else {
+				dst = append(dst, c)
+			}
+			i++
+			continue
+		}
+
+		// C1 控制字符 (U+0080~U+009F)
+		if c == 0xc2 {
+			r, size := utf8.DecodeRuneInString(s[i:])
+			if r >= 0x80 && r <= 0x9f && mode&SanitizeEscape != 0 {
+				dst = appendEscapedControl(dst, r)
+				i += size
+				continue
+			}
+		}
+
+		dst = append(dst, c)
+		i++
+	}
+	return dst
+}
+
+// appendEscapedControl 将控制字符转义为可见文本
+//
+// 参数:
+//   - dst: 目标缓冲区
+//   - r: 控制字符, 范围为 U+0000~U+009F
+//
+// 返回:
+//   - []byte: 追加后的缓冲区
+func appendEscapedControl(dst []byte, r rune) []byte {
+	switch r {
+	case '\n':
+		return append(dst, '\\', 'n')
+	case '\r':
+		return append(dst, '\\', 'r')
+	case '\t':
+		return append(dst, '\\', 't')
+	}
+	return append(dst, '\\', 'x', hexDigits[r>>4&0xF], hexDigits[r&0xF])
+}
+
+// ansiSequenceLen 返回 s 开头的终端转义序列长度, s 必须以 ESC 开头
+//
+// 支持的序列:
+//   - CSI: ESC [ 参数 中间字节 结束字节 (0x40~0x7E), 如颜色 \x1b[31m、光标移动 \x1b[2J
+//   - OSC/DCS/SOS/PM/APC: ESC ] ... 以 BEL 或 ESC \ 结束, 如窗口标题、超链接
+//   - 其余两字节序列: ESC 后跟一个字节
+//
+// 参数:
+//   - s: 以 ESC 开头的文本
+//
+// 返回:
+//   - int: 序列长度, 不完整的序列视为延伸到文本末尾
+func ansiSequenceLen(s string) int {
+	if len(s) < 2 {
+		return len(s)
+	}
+	switch s[1] {
+	case '[':
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7e {
+				return i + 1
+			}
+		}
+		return len(s)
+	case ']', 'P', 'X', '^', '_':
+		for i := 2; i < len(s); i++ {
+			if s[i] == 0x07 {
+				return i + 1
+			}
+			if s[i] == 0x1b && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+		return len(s)
+	default:
+		return 2
+	}
+}
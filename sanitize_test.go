@@ -0,0 +1,90 @@
+package fastlog
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAppendSanitized(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		mode SanitizeMode
+		want string
+	}{
+		{"escape newline", "a\nb", SanitizeEscape, `a\nb`},
+		{"escape crlf tab", "a\r\n\tb", SanitizeEscape, `a\r\n\tb`},
+		{"escape esc", "\x1b[31mred\x1b[0m", SanitizeEscape, `\x1b[31mred\x1b[0m`},
+		{"escape nul del", "a\x00b\x7f", SanitizeEscape, `a\x00b\x7f`},
+		{"escape c1", "a\u009bb", SanitizeEscape, `a\x9bb`},
+		{"keeps unicode", "中文é ", SanitizeEscape, "中文é "},
+		{"strip csi", "\x1b[1;31mred\x1b[0m text", SanitizeStripANSI, "red text"},
+		{"strip osc bel", "\x1b]0;title\x07ok", SanitizeStripANSI, "ok"},
+		{"strip osc st", "\x1b]8;;http://x\x1b\\link\x1b]8;;\x1b\\", SanitizeStripANSI, "link"},
+		{"strip two byte", "\x1bcreset", SanitizeStripANSI, "reset"},
+		{"strip truncated", "ok\x1b[31", SanitizeStripANSI, "ok"},
+		{"indent", "line1\nline2\r\nline3\rline4", SanitizeIndent, "line1\n    line2\n    line3\n    line4"},
+		{"all", "a\x1b[31m\nb\x00", SanitizeAll, "a\n    b\\x00"},
+		{"off keeps bytes", "a\nb\x1b", SanitizeOff, "a\nb\x1b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(appendSanitized(nil, tt.in, tt.mode)); got != tt.want {
+				t.Errorf("appendSanitized(%q, %v) = %q, want %q", tt.in, tt.mode, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatterSanitize(t *testing.T) {
+	forged := "login ok\n2026-01-15 10:30:46 | ERROR  | forged"
+	entry := makeEntry(forged, "", String("user", "bob\x1b[2J"), String("k\ney", "v"))
+
+	t.Run("off is compatible", func(t *testing.T) {
+		for _, f := range []Formatter{Def{}, Simple{}, Compact{}} {
+			got, _ := f.Format(entry)
+			if !strings.Contains(string(got), forged) || !strings.Contains(string(got), "bob\x1b[2J") {
+				t.Errorf("%T without sanitize should keep raw bytes, got %q", f, got)
+			}
+		}
+	})
+
+	t.Run("escape", func(t *testing.T) {
+		tests := []struct {
+			f    Formatter
+			want string
+		}{
+			{Def{Sanitize: SanitizeEscape}, "2026-01-15 10:30:45 | INFO   | login ok\\n2026-01-15 10:30:46 | ERROR  | forged user=bob\\x1b[2J, k\\ney=v\n"},
+			{Simple{Sanitize: SanitizeEscape}, "2026-01-15 10:30:45 INFO login ok\\n2026-01-15 10:30:46 | ERROR  | forged user=bob\\x1b[2J, k\\ney=v\n"},
+			{Compact{Sanitize: SanitizeEscape}, "[I] 2026-01-15 10:30:45 login ok\\n2026-01-15 10:30:46 | ERROR  | forged | user=bob\\x1b[2J k\\ney=v\n"},
+		}
+		for _, tt := range tests {
+			got, _ := tt.f.Format(entry)
+			if string(got) != tt.want {
+				t.Errorf("%T.Format() =\n%q\nwant\n%q", tt.f, got, tt.want)
+			}
+		}
+	})
+
+	t.Run("all", func(t *testing.T) {
+		got, _ := Def{Sanitize: SanitizeAll}.Format(entry)
+		lines := strings.Split(strings.TrimSuffix(string(got), "\n"), "\n")
+		if len(lines) != 2 || !strings.HasPrefix(lines[1], sanitizeIndent) {
+			t.Fatalf("continuation line should be indented, got %q", got)
+		}
+		if strings.Contains(string(got), "\x1b") || !strings.HasSuffix(lines[1], `forged user=bob, k\ney=v`) {
+			t.Errorf("ANSI should be stripped and keys escaped, got %q", got)
+		}
+	})
+}
+
+func TestLoggerSanitize(t *testing.T) {
+	m := newMock()
+	l := New(&Config{Level: INFO, OutputConsole: true, Formatter: Simple{Sanitize: SanitizeEscape | SanitizeStripANSI}})
+	l.writer = m
+
+	l.Infow("hello\nINFO fake", String("name", "\x1b[31malice\x1b[0m"))
+	if got := m.String(); strings.Count(got, "\n") != 1 || !strings.Contains(got, `hello\nINFO fake name=alice`) {
+		t.Errorf("sanitized output = %q", got)
+	}
+}
@@ -0,0 +1,217 @@
+package fastlog
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// SlogHandler 基于 fastlog 的 slog.Handler 实现
+//
+// 通过 log/slog 记录的日志会经由 Logger 的格式化器、写入器和 hooks 输出,
+// 与直接调用 Logger 的日志共享级别、采样、轮转等全部配置。
+//
+// 级别映射:
+//   - slog.LevelDebug 及以下 → DEBUG
+//   - slog.LevelInfo ~ LevelWarn 之前 → INFO
+//   - slog.LevelWarn ~ LevelError 之前 → WARN
+//   - slog.LevelError 及以上 → ERROR
+//
+// 分组 (WithGroup / slog.Group) 以点号前缀展开为字段键, 如 "req.method"。
+//
+// 使用示例:
+//
+//	logger := fastlog.New(fastlog.NewConfig("logs/app.log"))
+//	slog.SetDefault(slog.New(fastlog.NewSlogHandler(logger)))
+//	slog.Info("服务启动", "port", 8080)
+type SlogHandler struct {
+	l      *Logger // 日志记录器, 携带 WithAttrs 累积的字段
+	prefix string  // WithGroup 累积的键前缀, 如 "req.header."
+}
+
+// NewSlogHandler 创建基于 Logger 的 slog.Handler
+//
+// 参数:
+//   - l: 日志记录器, 不能为 nil
+//
+// 返回:
+//   - *SlogHandler: slog.Handler 实例
+func NewSlogHandler(l *Logger) *SlogHandler {
+	if l == nil {
+		panic("logger is nil")
+	}
+	return &SlogHandler{l: l}
+}
+
+// Enabled 检查是否启用指定的 slog 级别
+//
+// 参数:
+//   - ctx: 上下文
+//   - level: slog 级别
+//
+// 返回:
+//   - bool: 是否启用
+func (h *SlogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.l.Enabled(slogLevel(level))
+}
+
+// Handle 处理一条 slog 记录
+//
+// 记录属性转换为字段后, 与 WithAttrs 字段、上下文字段一起输出;
+// 启用 Config.Caller 时根据 Record.PC 获取调用者信息。
+//
+// 参数:
+//   - ctx: 上下文, 会经过 Config.ContextExtractors 提取字段
+//   - r: slog 记录
+//
+// 返回:
+//   - error: 始终返回 nil, 格式化和写入错误由 Logger 输出到 stderr
+func (h *SlogHandler) Handle(ctx context.Context, r slog.Record) error {
+	level := slogLevel(r.Level)
+	if !h.l.Enabled(level) {
+		return nil
+	}
+	if h.l.sampler != nil && !h.l.sampler.Allow(level, r.Message) {
+		return nil
+	}
+
+	// 转换记录属性
+	var fields []Field
+	if r.NumAttrs() > 0 {
+		fields = make([]Field, 0, r.NumAttrs())
+		r.Attrs(func(a slog.Attr) bool {
+			fields = appendSlogAttr(fields, h.prefix, a)
+			return true
+		})
+	}
+	fields = h.l.contextFields(ctx, fields)
+
+	var pc uintptr
+	if h.l.config.Caller {
+		pc = r.PC
+	}
+
+	t := r.Time
+	if t.IsZero() {
+		t = time.Now()
+	}
+
+	h.l.output(t, level, r.Message, fields, pc)
+	return nil
+}
+
+// WithAttrs 返回携带额外属性的新 Handler
+//
+// 参数:
+//   - attrs: 属性列表, 使用当前分组前缀转换为字段
+//
+// 返回:
+//   - slog.Handler: 新的 Handler
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	fields := make([]Field, 0, len(attrs))
+	for _, a := range attrs {
+		fields = appendSlogAttr(fields, h.prefix, a)
+	}
+	return &SlogHandler{l: h.l.With(fields...), prefix: h.prefix}
+}
+
+// WithGroup 返回开启新分组的 Handler
+//
+// 参数:
+//   - name: 分组名称, 为空时返回自身
+//
+// 返回:
+//   - slog.Handler: 新的 Handler
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &SlogHandler{l: h.l, prefix: h.prefix + name + "."}
+}
+
+// slogLevel 将 slog 级别映射为 fastlog 级别
+//
+// 参数:
+//   - level: slog 级别
+//
+// 返回:
+//   - Level: fastlog 级别
+func slogLevel(level slog.Level) Level {
+	switch {
+	case level < slog.LevelInfo:
+		return DEBUG
+	case level < slog.LevelWarn:
+		return INFO
+	case level < slog.LevelError:
+		return WARN
+	default:
+		return ERROR
+	}
+}
+
+// appendSlogAttr 将 slog 属性转换为字段并追加
+//
+// 属性值会先解析 slog.LogValuer; 空属性被忽略;
+// 分组属性以点号前缀展开, 空键分组内联到当前层级。
+//
+// 参数:
+//   - fields: 目标字段列表
+//   - prefix: 键前缀
+//   - a: slog 属性
+//
+// 返回:
+//   - []Field: 追加后的字段列表
+func appendSlogAttr(fields []Field, prefix string, a slog.Attr) []Field {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return fields
+	}
+
+	key := prefix + a.Key
+	v := a.Value
+	switch v.Kind() {
+	case slog.KindGroup:
+		attrs := v.Group()
+		if len(attrs) == 0 {
+			return fields
+		}
+		groupPrefix := prefix
+		if a.Key != "" {
+			groupPrefix = key + "."
+		}
+		for _, ga := range attrs {
+			fields = appendSlogAttr(fields, groupPrefix, ga)
+		}
+		return fields
+
+	case slog.KindString:
+		return append(fields, String(key, v.String()))
+
+	case slog.KindInt64:
+		return append(fields, Int64(key, v.Int64()))
+
+	case slog.KindUint64:
+		return append(fields, Uint64(key, v.Uint64()))
+
+	case slog.KindFloat64:
+		return append(fields, Float64(key, v.Float64()))
+
+	case slog.KindBool:
+		return append(fields, Bool(key, v.Bool()))
+
+	case slog.KindDuration:
+		return append(fields, Duration(key, v.Duration()))
+
+	case slog.KindTime:
+		return append(fields, Time(key, v.Time()))
+
+	default:
+		if err, ok := v.Any().(error); ok {
+			return append(fields, Err(key, err))
+		}
+		return append(fields, Any(key, v.Any()))
+	}
+}
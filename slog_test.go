@@ -0,0 +1,118 @@
+package fastlog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testSlogToken 实现 slog.LogValuer 的测试类型
+type testSlogToken string
+
+func (t testSlogToken) LogValue() slog.Value {
+	return slog.StringValue("***")
+}
+
+func newSlogTestLogger(level Level) (*Logger, *bytes.Buffer) {
+	buf := &bytes.Buffer{}
+	l := New(&Config{Level: level, OutputConsole: true, Formatter: &testFormatter{buf: buf}})
+	return l, buf
+}
+
+func TestSlogHandlerAttrs(t *testing.T) {
+	l, buf := newSlogTestLogger(DEBUG)
+	log := slog.New(NewSlogHandler(l))
+
+	log.Info("login",
+		"user", "alice",
+		"age", 30,
+		"vip", true,
+		"ratio", 0.5,
+		"elapsed", 2*time.Second,
+		"err", errors.New("boom"),
+		"token", testSlogToken("secret"),
+		slog.Group("req", "method", "GET", slog.Group("header", "ua", "curl")),
+		slog.Group("", "inline", 1),
+		slog.Group("empty"),
+	)
+
+	want := "INFO login user=alice age=30 vip=true ratio=0.5 elapsed=2s err=boom token=*** " +
+		"req.method=GET req.header.ua=curl inline=1\n"
+	if got := buf.String(); got != want {
+		t.Errorf("slog output = %q, want %q", got, want)
+	}
+}
+
+func TestSlogHandlerWithAttrsAndGroup(t *testing.T) {
+	l, buf := newSlogTestLogger(DEBUG)
+	log := slog.New(NewSlogHandler(l)).
+		With("app", "svc").
+		WithGroup("req").
+		With("id", "r-1").
+		WithGroup("body")
+
+	log.Info("handled", "size", 12)
+	want := "INFO handled app=svc req.id=r-1 req.body.size=12\n"
+	if got := buf.String(); got != want {
+		t.Errorf("slog output = %q, want %q", got, want)
+	}
+}
+
+func TestSlogHandlerLevels(t *testing.T) {
+	tests := []struct {
+		in   slog.Level
+		want Level
+	}{
+		{slog.LevelDebug - 4, DEBUG},
+		{slog.LevelDebug, DEBUG},
+		{slog.LevelInfo, INFO},
+		{slog.LevelInfo + 2, INFO},
+		{slog.LevelWarn, WARN},
+		{slog.LevelError, ERROR},
+		{slog.LevelError + 4, ERROR},
+	}
+	for _, tt := range tests {
+		if got := slogLevel(tt.in); got != tt.want {
+			t.Errorf("slogLevel(%v) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+
+	l, buf := newSlogTestLogger(WARN)
+	h := NewSlogHandler(l)
+	if h.Enabled(context.Background(), slog.LevelInfo) {
+		t.Errorf("Enabled(Info) at WARN = true, want false")
+	}
+	log := slog.New(h)
+	log.Info("suppressed")
+	log.Warn("kept")
+	if got := buf.String(); got != "WARN kept\n" {
+		t.Errorf("slog level filtering output = %q", got)
+	}
+}
+
+func TestSlogHandlerCaller(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := New(&Config{Level: INFO, OutputConsole: true, Formatter: &testFormatter{buf: buf}, Caller: true})
+	slog.New(NewSlogHandler(l)).Info("with caller")
+
+	if !strings.Contains(buf.String(), "slog_test.go:TestSlogHandlerCaller") {
+		t.Errorf("caller should point at slog call site, got: %q", buf.String())
+	}
+}
+
+func TestSlogHandlerContextAndName(t *testing.T) {
+	m := newMock()
+	l := New(&Config{Level: INFO, OutputConsole: true, Formatter: Simple{}})
+	l.writer = m
+
+	ctx := ContextWithFields(context.Background(), String("trace_id", "t-1"))
+	slog.New(NewSlogHandler(l.Named("db"))).InfoContext(ctx, "query", "rows", 3)
+
+	if !strings.Contains(m.String(), "query logger=db, trace_id=t-1, rows=3") {
+		t.Errorf("slog output should carry name and context fields, got: %q", m.String())
+	}
+}
@@ -0,0 +1,642 @@
+package fastlog
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Facility syslog 设施类型, 标识日志来源
+//
+// 零值在 SyslogConfig 和 SyslogFormatter 中默认按 FacilityUser 处理。
+type Facility uint8
+
+// syslog 设施常量 (RFC 5424 6.2.1), 不提供仅供内核使用的 kern
+const (
+	FacilityUser     Facility = 1  // 用户级消息 (默认)
+	FacilityMail     Facility = 2  // 邮件系统
+	FacilityDaemon   Facility = 3  // 系统守护进程
+	FacilityAuth     Facility = 4  // 安全/认证
+	FacilitySyslog   Facility = 5  // syslog 自身
+	FacilityLPR      Facility = 6  // 打印子系统
+	FacilityNews     Facility = 7  // 网络新闻子系统
+	FacilityUUCP     Facility = 8  // UUCP 子系统
+	FacilityCron     Facility = 9  // 定时任务
+	FacilityAuthPriv Facility = 10 // 私有安全/认证
+	FacilityFTP      Facility = 11 // FTP 守护进程
+	FacilityLocal0   Facility = 16 // 本地使用 0
+	FacilityLocal1   Facility = 17 // 本地使用 1
+	FacilityLocal2   Facility = 18 // 本地使用 2
+	FacilityLocal3   Facility = 19 // 本地使用 3
+	FacilityLocal4   Facility = 20 // 本地使用 4
+	FacilityLocal5   Facility = 21 // 本地使用 5
+	FacilityLocal6   Facility = 22 // 本地使用 6
+	FacilityLocal7   Facility = 23 // 本地使用 7
+)
+
+// SyslogProtocol syslog 消息协议格式
+type SyslogProtocol uint8
+
+const (
+	// SyslogRFC5424 RFC 5424 格式 (默认), 字段输出为结构化数据
+	SyslogRFC5424 SyslogProtocol = iota
+
+	// SyslogRFC3164 RFC 3164 (BSD) 格式, 字段以 key=value 形式追加到消息之后
+	SyslogRFC3164
+)
+
+// 默认值
+const (
+	DefaultSyslogSDID         = "fields@32473" // 默认结构化数据 ID, 32473 为 RFC 5612 保留的示例企业号
+	DefaultSyslogDialTimeout  = 5 * time.Second
+	DefaultSyslogWriteTimeout = 5 * time.Second
+)
+
+// syslogLocalPaths 本地 syslog 套接字的常见路径
+var syslogLocalPaths = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// SyslogConfig syslog 输出配置
+//
+// 设置到 Config.Syslog 后, 每条日志会额外以 syslog 格式发送, 不影响终端和文件输出。
+//
+// 示例:
+//
+//	cfg := fastlog.NewConfig("logs/app.log")
+//	cfg.Syslog = &fastlog.SyslogConfig{
+//	    Network:  "tcp",
+//	    Addr:     "syslog.example.com:601",
+//	    Facility: fastlog.FacilityLocal0,
+//	}
+type SyslogConfig struct {
+	// Network 网络类型: "udp"、"tcp"、"unix"、"unixgram" 及 "udp4" 等变体
+	// 零值表示本地 syslog, 依次尝试 /dev/log、/var/run/syslog、/var/run/log
+	// "unix" 会先尝试数据报套接字, 失败后再尝试流式套接字
+	Network string
+
+	// Addr 地址, 如 "localhost:514" 或 "/dev/log", Network 非空时必须设置
+	Addr string
+
+	// Level 发送到 syslog 的最低级别, 零值表示不额外过滤
+	Level Level
+
+	// Protocol 消息格式, 零值默认 RFC 5424
+	Protocol SyslogProtocol
+
+	// Facility 设施, 零值默认 FacilityUser
+	Facility Facility
+
+	// AppName 应用名称, 零值默认为程序文件名
+	AppName string
+
+	// Hostname 主机名, 零值默认为 os.Hostname()
+	Hostname string
+
+	// ProcID 进程标识, 零值默认为当前进程 PID
+	ProcID string
+
+	// StructuredDataID 字段所在结构化数据的 ID, 仅 RFC 5424 格式使用, 零值默认 DefaultSyslogSDID
+	StructuredDataID string
+
+	// DialTimeout 连接超时时间, 零值默认 5 秒
+	DialTimeout time.Duration
+
+	// WriteTimeout 单次写入超时时间, 零值默认 5 秒
+	WriteTimeout time.Duration
+
+	// MinBackoff 首次重连前的等待时间, 之后每次失败翻倍, 零值默认 100 毫秒
+	MinBackoff time.Duration
+
+	// MaxBackoff 重连等待时间上限, 零值默认 30 秒
+	MaxBackoff time.Duration
+
+	// BufferSize 断线期间缓冲的最大字节数, 零值默认 1MB
+	BufferSize int
+
+	// Overflow 缓冲区已满时的处理策略, 零值默认 OverflowDropNewest
+	Overflow OverflowPolicy
+}
+
+// validate 验证 syslog 配置
+//
+// 返回:
+//   - error: 验证通过时返回 nil, 否则返回错误信息
+func (c *SyslogConfig) validate() error {
+	switch c.Network {
+	case "":
+	case "udp", "udp4", "udp6", "tcp", "tcp4", "tcp6", "unix", "unixgram":
+		if c.Addr == "" {
+			return errors.New("syslog addr must be set when network is set")
+		}
+	default:
+		return fmt.Errorf("unsupported syslog network %q", c.Network)
+	}
+	if c.Protocol > SyslogRFC3164 {
+		return fmt.Errorf("unsupported syslog protocol %d", c.Protocol)
+	}
+	if c.Facility > FacilityLocal7 {
+		return fmt.Errorf("invalid syslog facility %d", c.Facility)
+	}
+	if c.DialTimeout < 0 || c.WriteTimeout < 0 || c.MinBackoff < 0 || c.MaxBackoff < 0 {
+		return errors.New("syslog timeouts must be >= 0")
+	}
+	if c.BufferSize < 0 {
+		return errors.New("syslog buffer size must be >= 0")
+	}
+	if c.Overflow > OverflowBlock {
+		return fmt.Errorf("unsupported overflow policy %d", c.Overflow)
+	}
+	return nil
+}
+
+// formatter 根据配置创建 syslog 格式化器
+func (c *SyslogConfig) formatter() SyslogFormatter {
+	return SyslogFormatter{
+		Protocol:         c.Protocol,
+		Facility:         c.Facility,
+		AppName:          c.AppName,
+		Hostname:         c.Hostname,
+		ProcID:           c.ProcID,
+		StructuredDataID: c.StructuredDataID,
+	}
+}
+
+// SyslogFormatter syslog 格式
+//
+// 按 RFC 5424 或 RFC 3164 生成 syslog 消息 (不含传输层分帧), 末尾追加换行符,
+// 可单独用作 Config.Formatter, 也由 Config.Syslog 内部使用。
+//
+// 级别与 syslog 严重性的对应关系:
+//
+//	DEBUG → debug(7)  INFO → info(6)  WARN → warning(4)
+//	ERROR → err(3)    FATAL → crit(2) PANIC → alert(1)
+//
+// RFC 5424 格式中, 日志记录器名称作为 MSGID, 调用者和字段作为结构化数据:
+//
+//	<134>1 2026-01-15T10:30:45.000000Z host app 1234 api [fields@32473 caller="main.go:main:10" user="alice"] 登录成功
+type SyslogFormatter struct {
+	// Protocol 消息格式, 零值默认 RFC 5424
+	Protocol SyslogProtocol
+
+	// Facility 设施, 零值默认 FacilityUser
+	Facility Facility
+
+	// AppName 应用名称, 零值默认为程序文件名
+	AppName string
+
+	// Hostname 主机名, 零值默认为 os.Hostname()
+	Hostname string
+
+	// ProcID 进程标识, 零值默认为当前进程 PID
+	ProcID string
+
+	// StructuredDataID 结构化数据 ID, 零值默认 DefaultSyslogSDID
+	StructuredDataID string
+}
+
+// syslogDefaults 进程级默认值, 首次使用时获取一次
+var syslogDefaults struct {
+	once     sync.Once
+	hostname string
+	appName  string
+	procID   string
+}
+
+// loadSyslogDefaults 获取默认的主机名、应用名称和进程标识
+func loadSyslogDefaults() {
+	syslogDefaults.once.Do(func() {
+		syslogDefaults.hostname, _ = os.Hostname()
+		syslogDefaults.appName = filepath.Base(os.Args[0])
+		syslogDefaults.procID = strconv.Itoa(os.Getpid())
+	})
+}
+
+// SyslogSeverity 返回日志级别对应的 syslog 严重性
+//
+// 自定义级别按位次 (LevelSpec.Rank) 所在区间映射: 介于 INFO 和 WARN 之间的 NOTICE 映射为 notice(5),
+// 低于 DEBUG 的映射为 debug(7), 高于 PANIC 的映射为 alert(1)。
+//
+// 参数:
+//   - l: 日志级别
+//
+// 返回:
+//   - int: syslog 严重性, 范围 0~7
+func SyslogSeverity(l Level) int {
+	rank := levelRank(l)
+	switch {
+	case rank >= levelRank(PANIC):
+		return 1 // alert
+	case rank >= levelRank(FATAL):
+		return 2 // crit
+	case rank >= levelRank(ERROR):
+		return 3 // err
+	case rank >= levelRank(WARN):
+		return 4 // warning
+	case rank > levelRank(INFO):
+		return 5 // notice
+	case rank >= levelRank(INFO):
+		return 6 // info
+	default:
+		return 7 // debug
+	}
+}
+
+// Format 实现 syslog 格式
+//
+// 参数:
+//   - entry: 日志条目
+//
+// 返回:
+//   - []byte: 格式化后的字节数组
+//   - error: 如果格式化失败
+func (f SyslogFormatter) Format(entry *Entry) ([]byte, error) {
+	return f.AppendFormat(make([]byte, 0, 256), entry)
+}
+
+// AppendFormat 实现 AppendFormatter 接口, 将格式化结果追加到 dst
+//
+// 参数:
+//   - dst: 目标缓冲区
+//   - entry: 日志条目
+//
+// 返回:
+//   - []byte: 追加后的字节数组
+//   - error: 如果格式化失败
+func (f SyslogFormatter) AppendFormat(dst []byte, entry *Entry) ([]byte, error) {
+	loadSyslogDefaults()
+	hostname := orDefault(f.Hostname, syslogDefaults.hostname)
+	appName := orDefault(f.AppName, syslogDefaults.appName)
+	procID := orDefault(f.ProcID, syslogDefaults.procID)
+
+	facility := f.Facility
+	if facility == 0 {
+		facility = FacilityUser
+	}
+	dst = append(dst, '<')
+	dst = strconv.AppendInt(dst, int64(facility)*8+int64(SyslogSeverity(entry.Level)), 10)
+	dst = append(dst, '>')
+
+	if f.Protocol == SyslogRFC3164 {
+		dst = entry.Time.AppendFormat(dst, time.Stamp)
+		dst = append(dst, ' ')
+		dst = appendSyslogHeader(dst, hostname, 255)
+		dst = append(dst, ' ')
+		dst = appendSyslogHeader(dst, appName, 32)
+		dst = append(dst, '[')
+		dst = appendSyslogHeader(dst, procID, 128)
+		dst = append(dst, "]: "...)
+		dst = append(dst, entry.Message...)
+		if entry.Logger != "" {
+			dst = append(dst, " logger="...)
+			dst = append(dst, entry.Logger...)
+		}
+		if entry.Caller != "" {
+			dst = append(dst, " caller="...)
+			dst = append(dst, entry.Caller...)
+		}
+		dst = appendSyslogKV(dst, entry)
+		return append(dst, '\n'), nil
+	}
+
+	// RFC 5424: VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA [MSG]
+	dst = append(dst, "1 "...)
+	dst = entry.Time.AppendFormat(dst, "2006-01-02T15:04:05.000000Z07:00")
+	dst = append(dst, ' ')
+	dst = appendSyslogHeader(dst, hostname, 255)
+	dst = append(dst, ' ')
+	dst = appendSyslogHeader(dst, appName, 48)
+	dst = append(dst, ' ')
+	dst = appendSyslogHeader(dst, procID, 128)
+	dst = append(dst, ' ')
+	dst = appendSyslogHeader(dst, entry.Logger, 32)
+	dst = append(dst, ' ')
+	dst = f.appendStructuredData(dst, entry)
+	if entry.Message != "" {
+		dst = append(dst, ' ')
+		dst = append(dst, entry.Message...)
+	}
+	return append(dst, '\n'), nil
+}
+
+// appendStructuredData 追加 RFC 5424 结构化数据, 无调用者和字段时输出 "-"
+func (f SyslogFormatter) appendStructuredData(dst []byte, entry *Entry) []byte {
+	if entry.Caller == "" && !hasFields(entry) {
+		return append(dst, '-')
+	}
+	dst = append(dst, '[')
+	dst = appendSyslogHeader(dst, orDefault(f.StructuredDataID, DefaultSyslogSDID), 32)
+	if entry.Caller != "" {
+		dst = append(dst, " caller=\""...)
+		dst = appendSDValue(dst, entry.Caller)
+		dst = append(dst, '"')
+	}
+	var ns string
+	for _, field := range entry.Fields {
+		if field.typ == NamespaceType {
+			ns = joinNamespace(ns, field.key)
+			continue
+		}
+		dst = append(dst, ' ')
+		dst = appendSDName(dst, ns, field.key)
+		dst = append(dst, '=', '"')
+		dst = appendSDValue(dst, field.valueWithTimeFormat(entry.TimeFormat))
+		dst = append(dst, '"')
+	}
+	return append(dst, ']')
+}
+
+// appendSyslogKV 以 key=value 形式追加字段 (RFC 3164 使用)
+func appendSyslogKV(dst []byte, entry *Entry) []byte {
+	var ns string
+	for _, field := range entry.Fields {
+		if field.typ == NamespaceType {
+			ns = joinNamespace(ns, field.key)
+			continue
+		}
+		dst = append(dst, ' ')
+		if ns != "" {
+			dst = append(dst, ns...)
+			dst = append(dst, '.')
+		}
+		dst = append(dst, field.key...)
+		dst = append(dst, '=')
+		dst = append(dst, field.valueWithTimeFormat(entry.TimeFormat)...)
+	}
+	return dst
+}
+
+// appendSyslogHeader 追加头部字段, 仅保留可打印 ASCII 字符, 其余替换为 '_', 为空时输出 "-"
+//
+// 参数:
+//   - dst: 目标缓冲区
+//   - s: 字段值
+//   - maxLen: 最大长度, 超出截断
+//
+// 返回:
+//   - []byte: 追加后的缓冲区
+func appendSyslogHeader(dst []byte, s string, maxLen int) []byte {
+	if s == "" {
+		return append(dst, '-')
+	}
+	if len(s) > maxLen {
+		s = s[:maxLen]
+	}
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c >= 33 && c <= 126 {
+			dst = append(dst, c)
+		} else {
+			dst = append(dst, '_')
+		}
+	}
+	return dst
+}
+
+// appendSDName 追加结构化数据参数名, 命名空间以点号拼接
+//
+// 参数名最长 32 个字符, 不允许 '='、空格、']'、'"' 和非可打印 ASCII 字符, 非法字符替换为 '_'。
+func appendSDName(dst []byte, ns, key string) []byte {
+	start := len(dst)
+	if ns != "" {
+		dst = append(dst, ns...)
+		dst = append(dst, '.')
+	}
+	dst = append(dst, key...)
+	if len(dst)-start > 32 {
+		dst = dst[:start+32]
+	}
+	if len(dst) == start {
+		return append(dst, '_')
+	}
+	for i := start; i < len(dst); i++ {
+		if c := dst[i]; c < 33 || c > 126 || c == '=' || c == ']' || c == '"' {
+			dst[i] = '_'
+		}
+	}
+	return dst
+}
+
+// appendSDValue 追加结构化数据参数值, 转义 '"'、'\' 和 ']'
+func appendSDValue(dst []byte, s string) []byte {
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '"', '\\', ']':
+			dst = append(dst, '\\', c)
+		default:
+			dst = append(dst, c)
+		}
+	}
+	return dst
+}
+
+// orDefault 返回 s, 为空时返回 def
+func orDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
+
+// SyslogWriter syslog 写入器
+//
+// 每次 Write 发送一条消息, 末尾的换行符会被去除。流式连接 (tcp、unix) 按 RFC 6587
+// 八位组计数方式分帧 ("长度 消息"), 数据报连接 (udp、unixgram) 每条消息一个数据报。
+//
+// 连接管理与 NetWriter 相同: 连接失败或被对端关闭后转为断线状态, 消息写入有界缓冲区,
+// 后台协程按指数退避重连, 重连成功后按原顺序补发; Write 不会在调用方建立连接。
+// State 和 Stats 返回连接状态和统计信息。写入器是并发安全的。
+type SyslogWriter struct {
+	*NetWriter
+}
+
+// DialSyslog 连接 syslog 服务并创建写入器
+//
+// 首次连接在调用方完成, 之后的重连均在后台进行。
+//
+// 参数:
+//   - cfg: syslog 配置, 仅使用网络、地址、超时、重连和缓冲配置
+//
+// 返回:
+//   - *SyslogWriter: syslog 写入器
+//   - error: 配置非法或连接失败时返回
+func DialSyslog(cfg *SyslogConfig) (*SyslogWriter, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	w := newSyslogWriter(cfg)
+	conn, stream, err := w.dial()
+	if err != nil {
+		return nil, err
+	}
+	w.attach(conn, stream, nil)
+	go w.run()
+	return w, nil
+}
+
+// newSyslogWriter 创建尚未连接的 syslog 写入器, 调用方负责启动后台协程
+func newSyslogWriter(cfg *SyslogConfig) *SyslogWriter {
+	w := newNetWriter(NetWriterConfig{
+		Network:      cfg.Network,
+		Addr:         cfg.Addr,
+		DialTimeout:  cfg.DialTimeout,
+		WriteTimeout: cfg.WriteTimeout,
+		MinBackoff:   cfg.MinBackoff,
+		MaxBackoff:   cfg.MaxBackoff,
+		BufferSize:   cfg.BufferSize,
+		Overflow:     cfg.Overflow,
+	})
+	if cfg.DialTimeout == 0 {
+		w.dialTimeout = DefaultSyslogDialTimeout
+	}
+	if cfg.WriteTimeout == 0 {
+		w.writeTimeout = DefaultSyslogWriteTimeout
+	}
+	sw := &SyslogWriter{NetWriter: w}
+	w.dialer = sw.dialSyslog
+	w.framer = frameSyslog
+	return sw
+}
+
+// dialSyslog 连接 syslog 服务
+//
+// 本地 syslog 依次尝试 syslogLocalPaths, unix 套接字先尝试数据报再尝试流式。
+//
+// 返回:
+//   - net.Conn: 新建立的连接
+//   - bool: 是否为流式连接
+//   - error: 连接失败时返回
+func (w *SyslogWriter) dialSyslog() (net.Conn, bool, error) {
+	switch w.network {
+	case "":
+		for _, path := range syslogLocalPaths {
+			if conn, stream, err := w.dialUnix(path); err == nil {
+				return conn, stream, nil
+			}
+		}
+		return nil, false, errors.New("syslog: no local syslog socket found")
+	case "unix":
+		return w.dialUnix(w.addr)
+	}
+
+	conn, err := net.DialTimeout(w.network, w.addr, w.dialTimeout)
+	if err != nil {
+		return nil, false, fmt.Errorf("syslog: %w", err)
+	}
+	return conn, w.network == "tcp" || w.network == "tcp4" || w.network == "tcp6", nil
+}
+
+// dialUnix 连接 unix 套接字, 先尝试数据报再尝试流式
+func (w *SyslogWriter) dialUnix(path string) (net.Conn, bool, error) {
+	if conn, err := net.DialTimeout("unixgram", path, w.dialTimeout); err == nil {
+		return conn, false, nil
+	}
+	conn, err := net.DialTimeout("unix", path, w.dialTimeout)
+	if err != nil {
+		return nil, false, fmt.Errorf("syslog: %w", err)
+	}
+	return conn, true, nil
+}
+
+// frameSyslog 去除末尾换行符, 流式连接按八位组计数方式分帧
+//
+// 参数:
+//   - dst: 目标缓冲区
+//   - p: 格式化后的 syslog 消息
+//   - stream: 是否为流式连接
+//
+// 返回:
+//   - []byte: 追加后的缓冲区
+func frameSyslog(dst, p []byte, stream bool) []byte {
+	msg := bytes.TrimSuffix(p, []byte{'\n'})
+	if stream {
+		dst = strconv.AppendInt(dst, int64(len(msg)), 10)
+		dst = append(dst, ' ')
+	}
+	return append(dst, msg...)
+}
+
+// syslogHook 将日志以 syslog 格式发送的钩子（内部使用）
+type syslogHook struct {
+	level     Level           // 最低级别
+	formatter SyslogFormatter // syslog 格式化器
+	writer    *SyslogWriter   // syslog 写入器
+}
+
+// newSyslogHook 根据配置创建 syslog 钩子
+//
+// 连接在后台建立, 不阻塞日志记录器创建, 连接成功前的消息写入缓冲区。
+//
+// 参数:
+//   - cfg: syslog 配置
+//
+// 返回:
+//   - *syslogHook: syslog 钩子
+func newSyslogHook(cfg *SyslogConfig) *syslogHook {
+	w := newSyslogWriter(cfg)
+	w.start()
+	return &syslogHook{
+		level:     cfg.Level,
+		formatter: cfg.formatter(),
+		writer:    w,
+	}
+}
+
+// Fire 以 syslog 格式重新格式化日志条目并发送, 忽略主输出的格式化结果
+//
+// 参数:
+//   - entry: 日志条目
+//   - data: 主输出格式化后的日志数据 (未使用)
+//
+// 返回:
+//   - error: 发送过程中的错误
+func (h *syslogHook) Fire(entry *Entry, data []byte) error {
+	if !h.level.Enabled(entry.Level) {
+		return nil
+	}
+	bp := getBuffer()
+	msg, err := h.formatter.AppendFormat((*bp)[:0], entry)
+	if err == nil {
+		_, err = h.writer.Write(msg)
+	}
+	putBuffer(bp, msg)
+	return err
+}
+
+// Levels 返回关心的级别
+//
+// 返回:
+//   - []Level: 不低于最低级别的所有级别
+func (h *syslogHook) Levels() []Level {
+	var levels []Level
+	for _, lvl := range AllLevels() {
+		if h.level.Enabled(lvl) {
+			levels = append(levels, lvl)
+		}
+	}
+	return levels
+}
+
+// Sync 检查是否还有未发送的消息
+//
+// 已连接时消息在 Write 中直接发送; 断线期间消息留在缓冲区, 由后台协程重连后补发, Sync 不会等待。
+//
+// 返回:
+//   - error: 缓冲区中还有消息时返回错误, 包含缓冲条数和连接状态
+func (h *syslogHook) Sync() error {
+	if st := h.writer.Stats(); st.Buffered > 0 {
+		return fmt.Errorf("syslog: %d messages still buffered (%s)", st.Buffered, st.State)
+	}
+	return nil
+}
+
+// Close 关闭 syslog 写入器
+//
+// 返回:
+//   - error: 关闭过程中的错误
+func (h *syslogHook) Close() error {
+	return h.writer.Close()
+}
@@ -0,0 +1,310 @@
+package fastlog
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testSyslogFormatter 固定头部字段的 syslog 格式化器
+var testSyslogFormatter = SyslogFormatter{Hostname: "host", AppName: "app", ProcID: "42"}
+
+// readOctetFrame 读取一条八位组计数分帧的消息
+func readOctetFrame(t *testing.T, r *bufio.Reader) string {
+	t.Helper()
+	length, err := r.ReadString(' ')
+	if err != nil {
+		t.Fatalf("read frame length: %v", err)
+	}
+	n, err := strconv.Atoi(strings.TrimSuffix(length, " "))
+	if err != nil {
+		t.Fatalf("invalid frame length %q", length)
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		t.Fatalf("read frame body: %v", err)
+	}
+	return string(buf)
+}
+
+func TestSyslogFormatter(t *testing.T) {
+	t.Run("rfc5424", func(t *testing.T) {
+		entry := makeEntry("login ok", "main.go:main:10", String("user", `a"b]c\d`), Namespace("req"), Int("id", 7))
+		entry.Logger = "api"
+		got, _ := testSyslogFormatter.Format(entry)
+		want := `<14>1 2026-01-15T10:30:45.000000Z host app 42 api [fields@32473 caller="main.go:main:10" user="a\"b\]c\\d" req.id="7"] login ok` + "\n"
+		if string(got) != want {
+			t.Errorf("Format() =\n%q\nwant\n%q", got, want)
+		}
+	})
+
+	t.Run("rfc5424 nil values", func(t *testing.T) {
+		f := SyslogFormatter{Hostname: "host", AppName: "my app", ProcID: "42", Facility: FacilityLocal0}
+		got, _ := f.Format(makeEntry("", ""))
+		if want := "<134>1 2026-01-15T10:30:45.000000Z host my_app 42 - -\n"; string(got) != want {
+			t.Errorf("Format() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("rfc3164", func(t *testing.T) {
+		f := testSyslogFormatter
+		f.Protocol = SyslogRFC3164
+		f.Facility = FacilityDaemon
+		entry := makeEntry("disk full", "", String("path", "/var"))
+		entry.Level = ERROR
+		got, _ := f.Format(entry)
+		if want := "<27>Jan 15 10:30:45 host app[42]: disk full path=/var\n"; string(got) != want {
+			t.Errorf("Format() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("severity", func(t *testing.T) {
+		want := map[Level]int{DEBUG: 7, INFO: 6, WARN: 4, ERROR: 3, FATAL: 2, PANIC: 1}
+		for lvl, sev := range want {
+			if got := SyslogSeverity(lvl); got != sev {
+				t.Errorf("SyslogSeverity(%v) = %d, want %d", lvl, got, sev)
+			}
+		}
+	})
+
+	t.Run("defaults", func(t *testing.T) {
+		got, _ := SyslogFormatter{}.Format(makeEntry("m", ""))
+		if !strings.Contains(string(got), " "+strconv.Itoa(os.Getpid())+" ") {
+			t.Errorf("default procid missing: %q", got)
+		}
+	})
+}
+
+func TestSyslogWriterUDP(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("udp not available: %v", err)
+	}
+	defer func() { _ = pc.Close() }()
+
+	w, err := DialSyslog(&SyslogConfig{Network: "udp", Addr: pc.LocalAddr().String()})
+	if err != nil {
+		t.Fatalf("DialSyslog() error = %v", err)
+	}
+	defer func() { _ = w.Close() }()
+
+	if _, err := w.Write([]byte("<14>1 - - - - - - hello\n")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	buf := make([]byte, 1024)
+	_ = pc.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := pc.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("ReadFrom() error = %v", err)
+	}
+	if got := string(buf[:n]); got != "<14>1 - - - - - - hello" {
+		t.Errorf("datagram = %q", got)
+	}
+}
+
+func TestSyslogWriterTCPReconnect(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("tcp not available: %v", err)
+	}
+	defer func() { _ = ln.Close() }()
+
+	conns := make(chan net.Conn, 2)
+	go func() {
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conns <- c
+		}
+	}()
+
+	w, err := DialSyslog(&SyslogConfig{Network: "tcp", Addr: ln.Addr().String()})
+	if err != nil {
+		t.Fatalf("DialSyslog() error = %v", err)
+	}
+	defer func() { _ = w.Close() }()
+
+	first := <-conns
+	_, _ = w.Write([]byte("first message\n"))
+	_, _ = w.Write([]byte("second\n"))
+	r := bufio.NewReader(first)
+	_ = first.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if got := readOctetFrame(t, r); got != "first message" {
+		t.Errorf("frame 1 = %q", got)
+	}
+	if got := readOctetFrame(t, r); got != "second" {
+		t.Errorf("frame 2 = %q", got)
+	}
+
+	// 服务端断开后, 下一次写入应重连并送达
+	_ = first.Close()
+	time.Sleep(50 * time.Millisecond)
+	if _, err := w.Write([]byte("after reconnect\n")); err != nil {
+		t.Fatalf("Write() after peer close error = %v", err)
+	}
+	select {
+	case second := <-conns:
+		defer func() { _ = second.Close() }()
+		_ = second.SetReadDeadline(time.Now().Add(5 * time.Second))
+		if got := readOctetFrame(t, bufio.NewReader(second)); got != "after reconnect" {
+			t.Errorf("frame after reconnect = %q", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("writer did not reconnect")
+	}
+
+	_ = w.Close()
+	if _, err := w.Write([]byte("closed\n")); err == nil {
+		t.Error("Write() after Close should fail")
+	}
+}
+
+func TestSyslogWriterUnixgram(t *testing.T) {
+	dir, err := os.MkdirTemp("", "fastlog-syslog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+	path := filepath.Join(dir, "log.sock")
+
+	pc, err := net.ListenPacket("unixgram", path)
+	if err != nil {
+		t.Skipf("unixgram not available: %v", err)
+	}
+	defer func() { _ = pc.Close() }()
+
+	w, err := DialSyslog(&SyslogConfig{Network: "unix", Addr: path})
+	if err != nil {
+		t.Fatalf("DialSyslog() error = %v", err)
+	}
+	defer func() { _ = w.Close() }()
+
+	_, _ = w.Write([]byte("local message\n"))
+	buf := make([]byte, 1024)
+	_ = pc.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := pc.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("ReadFrom() error = %v", err)
+	}
+	if got := string(buf[:n]); got != "local message" {
+		t.Errorf("datagram = %q", got)
+	}
+}
+
+func TestLoggerSyslog(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("udp not available: %v", err)
+	}
+	defer func() { _ = pc.Close() }()
+
+	cfg := &Config{Syslog: &SyslogConfig{
+		Network:  "udp",
+		Addr:     pc.LocalAddr().String(),
+		Level:    WARN,
+		Facility: FacilityLocal3,
+		AppName:  "svc",
+		Hostname: "node1",
+	}}
+	l := New(cfg)
+	defer func() { _ = l.Close() }()
+
+	l.Info("filtered by syslog level")
+	l.Named("db").Warnw("slow query", Int("ms", 1200))
+
+	buf := make([]byte, 1024)
+	_ = pc.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := pc.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("ReadFrom() error = %v", err)
+	}
+	got := string(buf[:n])
+	if !strings.HasPrefix(got, "<156>1 ") || !strings.HasSuffix(got, ` node1 svc `+strconv.Itoa(os.Getpid())+` db [fields@32473 ms="1200"] slow query`) {
+		t.Errorf("syslog message = %q", got)
+	}
+}
+
+func TestLoggerSyslogBuffersUntilReachable(t *testing.T) {
+	path := unreachableSocket(t)
+	start := time.Now()
+	l := New(&Config{Syslog: &SyslogConfig{
+		Network:    "unix",
+		Addr:       path,
+		AppName:    "svc",
+		MinBackoff: 10 * time.Millisecond,
+		MaxBackoff: 20 * time.Millisecond,
+	}})
+	defer func() { _ = l.Close() }()
+	l.Info("queued")
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("New() and Info() took %v while syslog was unreachable", d)
+	}
+	var w *SyslogWriter
+	for _, h := range l.hooks {
+		if sh, ok := h.(*syslogHook); ok {
+			w = sh.writer
+		}
+	}
+	if st := w.Stats(); st.State != NetDisconnected || st.Buffered != 1 {
+		t.Fatalf("stats while unreachable = %+v", st)
+	}
+	if err := l.Sync(); err == nil || !strings.Contains(err.Error(), "1 messages still buffered") {
+		t.Errorf("Sync() error = %v, want buffered messages reported", err)
+	}
+
+	// 服务端就绪后由后台协程补发
+	pc, err := net.ListenPacket("unixgram", path)
+	if err != nil {
+		t.Skipf("unixgram not available: %v", err)
+	}
+	defer func() { _ = pc.Close() }()
+	buf := make([]byte, 1024)
+	_ = pc.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := pc.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("ReadFrom() error = %v", err)
+	}
+	if got := string(buf[:n]); !strings.HasSuffix(got, " svc "+strconv.Itoa(os.Getpid())+" - - queued") {
+		t.Errorf("syslog message = %q", got)
+	}
+}
+
+func TestConfigSyslogValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		syslog  *SyslogConfig
+		wantErr bool
+	}{
+		{"local", &SyslogConfig{}, false},
+		{"udp", &SyslogConfig{Network: "udp", Addr: "localhost:514"}, false},
+		{"missing addr", &SyslogConfig{Network: "tcp"}, true},
+		{"bad network", &SyslogConfig{Network: "http", Addr: "x"}, true},
+		{"bad facility", &SyslogConfig{Facility: 24}, true},
+		{"bad protocol", &SyslogConfig{Protocol: 9}, true},
+		{"bad buffer size", &SyslogConfig{BufferSize: -1}, true},
+		{"bad overflow", &SyslogConfig{Overflow: 9}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Syslog: tt.syslog}
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	cfg := &Config{Syslog: &SyslogConfig{AppName: "a"}}
+	clone := cfg.Clone()
+	clone.Syslog.AppName = "b"
+	if cfg.Syslog.AppName != "a" {
+		t.Error("Clone() should copy Syslog config")
+	}
+}
@@ -0,0 +1,205 @@
+package fastlog
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"math/rand/v2"
+)
+
+// 默认的链路追踪字段键名
+const (
+	DefaultTraceIDKey = "trace_id" // 链路 ID 字段键名, 值为 32 位十六进制字符串
+	DefaultSpanIDKey  = "span_id"  // Span ID 字段键名, 值为 16 位十六进制字符串
+)
+
+// TraceparentHeader W3C Trace Context 请求头名称
+const TraceparentHeader = "traceparent"
+
+// errInvalidTraceparent traceparent 格式非法
+var errInvalidTraceparent = errors.New("invalid traceparent")
+
+// TraceContext W3C Trace Context 中的链路信息
+//
+// 通过 ContextWithTrace 存入 context.Context 后, *Ctx 方法、Logger.Ctx 和 slog 集成
+// 会自动添加 trace_id 和 span_id 字段, OTLP 导出时映射为 LogRecord 的链路上下文。
+type TraceContext struct {
+	TraceID [16]byte // 链路 ID
+	SpanID  [8]byte  // 当前 Span ID
+	Flags   byte     // 追踪标志, 最低位表示已采样
+}
+
+// NewTraceContext 生成新的链路, 链路 ID 和 Span ID 随机生成, 标记为已采样
+//
+// 返回:
+//   - TraceContext: 新的链路信息
+func NewTraceContext() TraceContext {
+	tc := TraceContext{Flags: 0x01}
+	fillRandom(tc.TraceID[:])
+	fillRandom(tc.SpanID[:])
+	return tc
+}
+
+// ParseTraceparent 解析 traceparent 请求头
+//
+// 格式为 "版本-链路ID-父SpanID-标志", 如 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01。
+// 版本 ff 和全零 ID 视为非法; 高于 00 的版本只要前四段格式正确即可接受。
+//
+// 参数:
+//   - s: traceparent 请求头的值
+//
+// 返回:
+//   - TraceContext: 解析出的链路信息, SpanID 为上游的 Span ID
+//   - error: 格式非法时返回
+func ParseTraceparent(s string) (TraceContext, error) {
+	var tc TraceContext
+	// 00-<32>-<16>-<2>, 共 55 个字符; 未来版本可能在末尾追加 "-..."
+	if len(s) < 55 || s[2] != '-' || s[35] != '-' || s[52] != '-' {
+		return tc, errInvalidTraceparent
+	}
+	if len(s) > 55 && (s[:2] == "00" || s[55] != '-') {
+		return tc, errInvalidTraceparent
+	}
+
+	var version [1]byte
+	if _, err := hex.Decode(version[:], []byte(s[:2])); err != nil || version[0] == 0xff {
+		return tc, errInvalidTraceparent
+	}
+	if _, err := hex.Decode(tc.TraceID[:], []byte(s[3:35])); err != nil {
+		return tc, errInvalidTraceparent
+	}
+	if _, err := hex.Decode(tc.SpanID[:], []byte(s[36:52])); err != nil {
+		return tc, errInvalidTraceparent
+	}
+	var flags [1]byte
+	if _, err := hex.Decode(flags[:], []byte(s[53:55])); err != nil {
+		return tc, errInvalidTraceparent
+	}
+	tc.Flags = flags[0]
+	if !tc.IsValid() {
+		return tc, errInvalidTraceparent
+	}
+	return tc, nil
+}
+
+// IsValid 链路 ID 和 Span ID 均非全零时返回 true
+//
+// 返回:
+//   - bool: 是否有效
+func (tc TraceContext) IsValid() bool {
+	return tc.TraceID != [16]byte{} && tc.SpanID != [8]byte{}
+}
+
+// Sampled 返回上游是否已采样该链路
+//
+// 返回:
+//   - bool: 是否已采样
+func (tc TraceContext) Sampled() bool {
+	return tc.Flags&0x01 != 0
+}
+
+// TraceIDString 返回 32 位小写十六进制的链路 ID
+//
+// 返回:
+//   - string: 链路 ID
+func (tc TraceContext) TraceIDString() string {
+	return hex.EncodeToString(tc.TraceID[:])
+}
+
+// SpanIDString 返回 16 位小写十六进制的 Span ID
+//
+// 返回:
+//   - string: Span ID
+func (tc TraceContext) SpanIDString() string {
+	return hex.EncodeToString(tc.SpanID[:])
+}
+
+// NewSpan 返回同一链路下的子 Span, 保留链路 ID 和追踪标志, 生成新的 Span ID
+//
+// 返回:
+//   - TraceContext: 子 Span 的链路信息
+func (tc TraceContext) NewSpan() TraceContext {
+	child := tc
+	fillRandom(child.SpanID[:])
+	return child
+}
+
+// String 返回 traceparent 格式的字符串 (版本 00)
+//
+// 返回:
+//   - string: 如 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01
+func (tc TraceContext) String() string {
+	buf := make([]byte, 0, 55)
+	buf = append(buf, "00-"...)
+	buf = hex.AppendEncode(buf, tc.TraceID[:])
+	buf = append(buf, '-')
+	buf = hex.AppendEncode(buf, tc.SpanID[:])
+	buf = append(buf, '-', hexDigits[tc.Flags>>4], hexDigits[tc.Flags&0x0f])
+	return string(buf)
+}
+
+// fillRandom 以随机字节填充 b, 保证结果不为全零
+func fillRandom(b []byte) {
+	for {
+		for i := 0; i < len(b); i += 8 {
+			v := rand.Uint64()
+			for j := i; j < len(b) && j < i+8; j++ {
+				b[j] = byte(v)
+				v >>= 8
+			}
+		}
+		for _, c := range b {
+			if c != 0 {
+				return
+			}
+		}
+	}
+}
+
+// traceCtxKey 上下文中存放 TraceContext 的键
+type traceCtxKey struct{}
+
+// ContextWithTrace 返回携带链路信息的新上下文
+//
+// 参数:
+//   - ctx: 父上下文
+//   - tc: 链路信息
+//
+// 返回:
+//   - context.Context: 携带链路信息的上下文
+//
+// 示例:
+//
+//	ctx = fastlog.ContextWithTrace(ctx, fastlog.NewTraceContext())
+//	logger.InfoCtx(ctx, "开始处理") // 输出: ... 开始处理 trace_id=... span_id=...
+func ContextWithTrace(ctx context.Context, tc TraceContext) context.Context {
+	return context.WithValue(ctx, traceCtxKey{}, tc)
+}
+
+// TraceFromContext 返回上下文中的链路信息
+//
+// 参数:
+//   - ctx: 上下文
+//
+// 返回:
+//   - TraceContext: 链路信息
+//   - bool: 上下文中存在有效的链路信息时返回 true
+func TraceFromContext(ctx context.Context) (TraceContext, bool) {
+	if ctx == nil {
+		return TraceContext{}, false
+	}
+	tc, ok := ctx.Value(traceCtxKey{}).(TraceContext)
+	return tc, ok && tc.IsValid()
+}
+
+// traceFields 返回上下文中链路信息对应的 trace_id 和 span_id 字段, 没有时返回 nil
+func traceFields(ctx context.Context) []Field {
+	tc, ok := TraceFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	return []Field{
+		String(DefaultTraceIDKey, tc.TraceIDString()),
+		String(DefaultSpanIDKey, tc.SpanIDString()),
+	}
+}
@@ -0,0 +1,75 @@
+package fastlog
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		wantErr bool
+	}{
+		{"valid", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", false},
+		{"not sampled", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", false},
+		{"future version with suffix", "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", false},
+		{"empty", "", true},
+		{"version ff", "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", true},
+		{"version 00 with suffix", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", true},
+		{"zero trace id", "00-00000000000000000000000000000000-00f067aa0ba902b7-01", true},
+		{"zero span id", "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", true},
+		{"bad hex", "00-4bf92f3577b34da6a3ce929d0e0e473z-00f067aa0ba902b7-01", true},
+		{"bad separator", "00_4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc, err := ParseTraceparent(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTraceparent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && tc.TraceIDString() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+				t.Errorf("TraceIDString() = %q", tc.TraceIDString())
+			}
+		})
+	}
+
+	tc, _ := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	if tc.String() != "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01" || !tc.Sampled() {
+		t.Errorf("String() = %q, Sampled() = %v", tc.String(), tc.Sampled())
+	}
+}
+
+func TestTraceContextSpans(t *testing.T) {
+	root := NewTraceContext()
+	if !root.IsValid() || !root.Sampled() {
+		t.Fatalf("NewTraceContext() = %+v", root)
+	}
+	child := root.NewSpan()
+	if child.TraceID != root.TraceID || child.SpanID == root.SpanID || child.Flags != root.Flags {
+		t.Errorf("NewSpan() = %+v, parent %+v", child, root)
+	}
+	if parsed, err := ParseTraceparent(child.String()); err != nil || parsed != child {
+		t.Errorf("round trip = %+v, %v", parsed, err)
+	}
+}
+
+func TestContextWithTraceFields(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := New(&Config{OutputConsole: true, Formatter: &testFormatter{buf: buf}})
+
+	tc, _ := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx := ContextWithFields(context.Background(), String("request_id", "r-1"))
+	ctx = ContextWithTrace(ctx, tc)
+
+	l.InfoCtx(ctx, "handled", String("path", "/api"))
+	want := "INFO handled request_id=r-1 trace_id=4bf92f3577b34da6a3ce929d0e0e4736 span_id=00f067aa0ba902b7 path=/api\n"
+	if got := buf.String(); got != want {
+		t.Errorf("InfoCtx output = %q, want %q", got, want)
+	}
+
+	if _, ok := TraceFromContext(ContextWithTrace(context.Background(), TraceContext{})); ok {
+		t.Error("TraceFromContext() should ignore an invalid trace context")
+	}
+}
@@ -5,8 +5,9 @@ import (
 	"errors"
 	"io"
 	"os"
+	"sync"
 
-	"gitee.com/MM-Q/color"
+	"github.com/mattn/go-colorable"
 )
 
 // ConsoleWriter 控制台写入器
@@ -34,27 +35,103 @@ func (c *ConsoleWriter) Close() error {
 	return nil
 }
 
+// LevelWriter 可感知日志级别的写入器
+//
+// 日志记录器写入实现了该接口的写入器时调用 WriteLevel 并传入日志的真实级别,
+// 写入器无需再从字节流中猜测级别。输出 (Config.Outputs)、级别路由文件和
+// MultiWriter 中的写入器同样适用。
+type LevelWriter interface {
+	io.Writer
+
+	// WriteLevel 写入一条指定级别的日志
+	//
+	// 参数:
+	//   - level: 日志级别
+	//   - p: 格式化后的日志
+	//
+	// 返回:
+	//   - int: 写入的字节数
+	//   - error: 写入过程中的错误
+	WriteLevel(level Level, p []byte) (n int, err error)
+}
+
+// writeLevel 写入一条日志, 写入器实现 LevelWriter 时传入日志级别
+//
+// 参数:
+//   - w: 写入器
+//   - level: 日志级别
+//   - p: 格式化后的日志
+//
+// 返回:
+//   - int: 写入的字节数
+//   - error: 写入过程中的错误
+func writeLevel(w io.Writer, level Level, p []byte) (int, error) {
+	if lw, ok := w.(LevelWriter); ok {
+		return lw.WriteLevel(level, p)
+	}
+	return w.Write(p)
+}
+
+// WriteLevel 写入指定级别的日志, 底层写入器实现 LevelWriter 时传入级别
+//
+// 参数:
+//   - level: 日志级别
+//   - p: 要写入的字节数据
+//
+// 返回:
+//   - int: 写入的字节数
+//   - error: 写入过程中的错误
+func (c *ConsoleWriter) WriteLevel(level Level, p []byte) (n int, err error) {
+	return writeLevel(c.w, level, p)
+}
+
+// ColorScope 彩色输出的着色范围
+type ColorScope int
+
+const (
+	// ColorScopeLevel 仅为级别名称着色 (默认), 如 "| INFO   |" 中的 INFO
+	ColorScopeLevel ColorScope = iota
+
+	// ColorScopeLine 为整行着色, 行尾换行符不着色
+	ColorScopeLine
+)
+
+// colorReset 重置终端颜色的转义序列
+const colorReset = "\x1b[0m"
+
 // ColorWriter 彩色控制台写入器
 //
-// 通过扫描字节流中的日志级别关键字自动着色输出。
+// 日志记录器通过 WriteLevel 传入日志的真实级别, 默认只为级别名称着色,
+// 消息中出现的其他级别名称不影响颜色。直接调用 Write 时从字节流中识别级别。
 // 将 NoColor 设为 true 可禁用颜色输出, 恢复原始文本。
+//
+// Depth 为 ColorDepthAuto 时, 写入终端文件会检测 TTY 和 NO_COLOR、FORCE_COLOR、TERM 等环境变量,
+// 输出到管道或文件时不着色。Theme 和 Depth 须在首次写入前设置。
 type ColorWriter struct {
 	w       io.Writer
-	NoColor bool // 设为 true 禁用颜色, false 启用颜色
+	NoColor bool       // 设为 true 禁用颜色, false 启用颜色
+	Scope   ColorScope // 着色范围, 零值仅为级别名称着色
+	Depth   ColorDepth // 颜色深度, 零值自动检测, 主题中的颜色超出深度时自动降级
+	Theme   *Theme     // 颜色主题, nil 时使用 DefaultTheme
+
+	once  sync.Once      // 首次写入时按颜色深度生成主题
+	theme *compiledTheme // 预先生成的主题
 }
 
 // NewColorWriter 创建彩色控制台写入器, 默认写入 os.Stdout
 //
+// 创建时检测 os.Stdout 的颜色深度, 不是终端时不着色; Windows 旧版控制台通过 go-colorable 转换颜色。
+//
 // 参数:
 //   - noColor: 设为 true 禁用颜色输出
 //
 // 返回:
 //   - *ColorWriter: 彩色写入器实例
 func NewColorWriter(noColor bool) *ColorWriter {
-	return &ColorWriter{w: os.Stdout, NoColor: noColor}
+	return &ColorWriter{w: colorable.NewColorable(os.Stdout), NoColor: noColor, Depth: DetectColorDepth(os.Stdout)}
 }
 
-// Write 写入数据到控制台, 自动根据日志级别着色
+// Write 写入数据到控制台, 根据识别出的日志级别着色
 //
 // 参数:
 //   - p: 要写入的字节数据
@@ -66,36 +143,169 @@ func (c *ColorWriter) Write(p []byte) (n int, err error) {
 	if c.NoColor {
 		return c.w.Write(p)
 	}
+	return c.WriteLevel(c.detectLevel(p), p)
+}
 
-	// 检测日志级别
-	level := c.detectLevel(p)
+// WriteLevel 写入指定级别的日志, 按 Scope 和 Theme 着色
+//
+// 着色结果写入池化缓冲区, 不产生额外分配。ColorScopeLevel 模式下
+// 日志中找不到级别名称时原样输出。
+//
+// 参数:
+//   - level: 日志级别
+//   - p: 要写入的字节数据
+//
+// 返回:
+//   - int: 写入的字节数 (不含颜色转义序列)
+//   - error: 写入过程中的错误
+func (c *ColorWriter) WriteLevel(level Level, p []byte) (n int, err error) {
+	if c.NoColor {
+		return c.w.Write(p)
+	}
+	ct := c.compiled()
+	code := ct.levels[level]
+	if code == "" && (c.Scope == ColorScopeLine || !ct.segments()) {
+		return c.w.Write(p)
+	}
 
-	// 根据级别着色
-	var colored string
-	switch level {
-	case DEBUG:
-		colored = color.New(color.FgCyan, color.Bold).Sprint(string(p))
+	bp := getBuffer()
+	var buf []byte
+	if c.Scope == ColorScopeLine {
+		end := len(p)
+		for end > 0 && (p[end-1] == '\n' || p[end-1] == '\r') {
+			end--
+		}
+		buf = appendStyled((*bp)[:0], code, p[:end])
+		buf = append(buf, p[end:]...)
+	} else {
+		buf = ct.appendSegments((*bp)[:0], p, level)
+	}
+	_, err = c.w.Write(buf)
+	putBuffer(bp, buf)
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
 
-	case INFO:
-		colored = color.New(color.FgBlue, color.Bold).Sprint(string(p))
+// compiled 返回按颜色深度生成的主题, 首次调用时检测颜色深度
+func (c *ColorWriter) compiled() *compiledTheme {
+	c.once.Do(func() {
+		depth := c.Depth
+		if depth == ColorDepthAuto {
+			if f, ok := c.w.(*os.File); ok {
+				depth = DetectColorDepth(f)
+			} else {
+				// 非文件写入器由调用方指定, 视为终端, 仍遵循环境变量
+				depth = detectColorDepth(true, os.LookupEnv)
+			}
+		}
+		theme := c.Theme
+		if theme == nil {
+			theme = DefaultTheme()
+		}
+		c.theme = theme.compile(depth)
+	})
+	return c.theme
+}
 
-	case WARN:
-		colored = color.New(color.FgYellow, color.Bold).Sprint(string(p))
+// appendSegments 按主题为级别名称、时间戳、调用者和字段键着色
+//
+// 参数:
+//   - dst: 目标缓冲区
+//   - p: 一条日志
+//   - level: 日志级别
+//
+// 返回:
+//   - []byte: 追加后的缓冲区
+func (ct *compiledTheme) appendSegments(dst, p []byte, level Level) []byte {
+	ls := indexLevel(p, level)
+	if ls < 0 {
+		return append(dst, p...)
+	}
+	le := ls + len(level.String())
 
-	case ERROR:
-		colored = color.New(color.FgRed, color.Bold).Sprint(string(p))
+	// 时间戳: 级别之前以数字开头的部分
+	ts, te := 0, 0
+	if ct.time != "" {
+		te = ls
+		for te > 0 && isSegmentSep(p[te-1]) {
+			te--
+		}
+		for ts < te && isSegmentSep(p[ts]) {
+			ts++
+		}
+		if ts == te || p[ts] < '0' || p[ts] > '9' {
+			ts, te = 0, 0
+		}
+	}
+	dst = append(dst, p[:ts]...)
+	dst = appendStyled(dst, ct.time, p[ts:te])
+	dst = append(dst, p[te:ls]...)
+	dst = appendStyled(dst, ct.levels[level], p[ls:le])
+	rest := le
+
+	// 调用者: 级别之后、" - " 之前形如 file.go:func:line 的部分
+	if ct.caller != "" {
+		cs := le
+		for cs < len(p) && isSegmentSep(p[cs]) {
+			cs++
+		}
+		if i := bytes.Index(p[cs:], []byte(" - ")); i > 0 {
+			caller := p[cs : cs+i]
+			if bytes.Contains(caller, []byte(".go:")) && bytes.IndexByte(caller, ' ') < 0 {
+				dst = append(dst, p[le:cs]...)
+				dst = appendStyled(dst, ct.caller, caller)
+				rest = cs + i
+			}
+		}
+	}
 
-	case FATAL:
-		colored = color.New(color.FgRed, color.Bold).Sprint(string(p))
+	if ct.fieldKey == "" {
+		return append(dst, p[rest:]...)
+	}
+	return appendFieldKeys(dst, p[rest:], ct.fieldKey)
+}
 
-	case PANIC:
-		colored = color.New(color.FgMagenta, color.Bold).Sprint(string(p))
+// appendFieldKeys 为 key=value 中的 key 着色, key 须位于行首或空格、逗号之后
+func appendFieldKeys(dst, p []byte, seq string) []byte {
+	last := 0
+	for i := 1; i < len(p); i++ {
+		if p[i] != '=' {
+			continue
+		}
+		j := i
+		for j > last && isKeyByte(p[j-1]) {
+			j--
+		}
+		if j == i || (j > 0 && p[j-1] != ' ' && p[j-1] != ',') {
+			continue
+		}
+		dst = append(dst, p[last:j]...)
+		dst = appendStyled(dst, seq, p[j:i])
+		last = i
+	}
+	return append(dst, p[last:]...)
+}
 
-	default:
-		return c.w.Write(p)
+// appendStyled 追加带样式的文本, 样式为空或文本为空时原样追加
+func appendStyled(dst []byte, seq string, text []byte) []byte {
+	if seq == "" || len(text) == 0 {
+		return append(dst, text...)
 	}
+	dst = append(dst, seq...)
+	dst = append(dst, text...)
+	return append(dst, colorReset...)
+}
+
+// isSegmentSep 判断字节是否为文本格式中各部分之间的分隔字符
+func isSegmentSep(b byte) bool {
+	return b == ' ' || b == '|' || b == '[' || b == ']' || b == '\t'
+}
 
-	return c.w.Write([]byte(colored))
+// isKeyByte 判断字节是否可以出现在字段键中
+func isKeyByte(b byte) bool {
+	return isLetter(b) || b >= '0' && b <= '9' || b == '_' || b == '.' || b == '-'
 }
 
 // Close 关闭写入器
@@ -106,21 +316,55 @@ func (c *ColorWriter) Close() error {
 	return nil
 }
 
-// detectLevel 从字节流中检测日志级别
+// detectLevel 从字节流中识别日志级别
+//
+// 取最先出现的完整级别名称, 如 "INFO: failed to reach PANIC handler" 识别为 INFO,
+// "INFORMATION" 这类单词中的片段不会被识别。
 //
 // 参数:
 //   - p: 字节流数据
 //
 // 返回:
-//   - Level: 检测到的日志级别, 未识别时返回 0
+//   - Level: 识别出的日志级别, 未识别时返回 0
 func (c *ColorWriter) detectLevel(p []byte) Level {
-	// 从高优先级到低优先级匹配, 避免误判
-	for level := PANIC; level >= DEBUG; level-- {
-		if bytes.Contains(p, []byte(level.String())) {
-			return level
+	var found Level
+	first := len(p)
+	for _, level := range levels.Load().sorted {
+		if i := indexLevel(p[:first], level); i >= 0 {
+			found, first = level, i
 		}
 	}
-	return 0
+	return found
+}
+
+// indexLevel 返回级别名称作为完整单词首次出现的位置, 未找到返回 -1
+//
+// 参数:
+//   - p: 字节流数据
+//   - level: 日志级别
+//
+// 返回:
+//   - int: 级别名称的起始位置
+func indexLevel(p []byte, level Level) int {
+	name := level.String()
+	for off := 0; off < len(p); {
+		i := bytes.Index(p[off:], []byte(name))
+		if i < 0 {
+			return -1
+		}
+		i += off
+		end := i + len(name)
+		if (i == 0 || !isLetter(p[i-1])) && (end == len(p) || !isLetter(p[end])) {
+			return i
+		}
+		off = i + 1
+	}
+	return -1
+}
+
+// isLetter 判断字节是否为 ASCII 字母
+func isLetter(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
 }
 
 // MultiWriter 多路写入器, 同时将日志写入多个输出目标
@@ -157,6 +401,25 @@ func (m *MultiWriter) Write(p []byte) (n int, err error) {
 	return len(p), nil
 }
 
+// WriteLevel 写入指定级别的日志到所有输出目标, 实现 LevelWriter 的目标会收到级别
+//
+// 参数:
+//   - level: 日志级别
+//   - p: 要写入的字节数据
+//
+// 返回:
+//   - int: 写入的字节数
+//   - error: 写入过程中的错误
+func (m *MultiWriter) WriteLevel(level Level, p []byte) (n int, err error) {
+	for _, w := range m.writers {
+		_, err = writeLevel(w, level, p)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
 // Close 关闭所有输出目标
 //
 // 返回:
@@ -70,6 +70,68 @@ func TestColorWriterUnknownLevel(t *testing.T) {
 	}
 }
 
+func TestColorWriterWriteLevel(t *testing.T) {
+	line := []byte("10:30:45 | ERROR  | INFO: failed to reach PANIC handler\n")
+	tests := []struct {
+		name  string
+		cw    *ColorWriter
+		level Level
+		want  string
+	}{
+		{"level token", &ColorWriter{Depth: ColorDepth16}, ERROR, "10:30:45 | \x1b[31;1mERROR\x1b[0m  | INFO: failed to reach PANIC handler\n"},
+		{"whole line", &ColorWriter{Depth: ColorDepth16, Scope: ColorScopeLine}, ERROR, "\x1b[31;1m10:30:45 | ERROR  | INFO: failed to reach PANIC handler\x1b[0m\n"},
+		{"token missing", &ColorWriter{Depth: ColorDepth16}, DEBUG, string(line)},
+		{"unknown level", &ColorWriter{Depth: ColorDepth16}, Level(0), string(line)},
+		{"no color", &ColorWriter{NoColor: true}, ERROR, string(line)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			tt.cw.w = buf
+			n, err := tt.cw.WriteLevel(tt.level, line)
+			if err != nil || n != len(line) {
+				t.Fatalf("WriteLevel() = %d, %v", n, err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("WriteLevel() output = %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+
+	cw := &ColorWriter{w: io.Discard, Depth: ColorDepth16}
+	if allocs := testing.AllocsPerRun(100, func() { _, _ = cw.WriteLevel(ERROR, line) }); allocs != 0 {
+		t.Errorf("WriteLevel() allocs = %v, want 0", allocs)
+	}
+}
+
+// levelRecorder 记录 WriteLevel 收到的级别
+type levelRecorder struct {
+	*mockWriteCloser
+	levels []Level
+}
+
+func (r *levelRecorder) WriteLevel(level Level, p []byte) (int, error) {
+	r.levels = append(r.levels, level)
+	return r.Write(p)
+}
+
+func TestLoggerUsesLevelWriter(t *testing.T) {
+	rec := &levelRecorder{mockWriteCloser: newMock()}
+	plain := newMock()
+	l := New(&Config{OutputConsole: true, Level: DEBUG, Formatter: MustPattern("%msg")})
+	l.writer = NewMultiWriter(rec, plain)
+
+	l.Debug("PANIC handler missing")
+	l.Error("retrying")
+
+	if len(rec.levels) != 2 || rec.levels[0] != DEBUG || rec.levels[1] != ERROR {
+		t.Errorf("WriteLevel levels = %v, want [DEBUG ERROR]", rec.levels)
+	}
+	if plain.String() != "PANIC handler missing\nretrying\n" {
+		t.Errorf("plain writer output = %q", plain.String())
+	}
+}
+
 func TestColorWriterClose(t *testing.T) {
 	cw := NewColorWriter(false)
 	if err := cw.Close(); err != nil {
@@ -89,8 +151,11 @@ func TestDetectLevel(t *testing.T) {
 		{"ERROR", "[ERROR] msg", ERROR},
 		{"FATAL", "[FATAL] msg", FATAL},
 		{"PANIC", "[PANIC] msg", PANIC},
-		// 高优先级优先 (PANIC > DEBUG)
+		// 最先出现的完整级别名称优先
 		{"PANIC before DEBUG", "PANIC and DEBUG", PANIC},
+		{"DEBUG before PANIC", "DEBUG and PANIC", DEBUG},
+		{"level word in message", "INFO: failed to reach PANIC handler", INFO},
+		{"part of a word", "INFORMATION about ERRORS", Level(0)},
 		{"no match", "some random text", Level(0)},
 	}
 	for _, tt := range tests {